@@ -0,0 +1,59 @@
+package icumsg
+
+import "golang.org/x/text/language"
+
+// AdaptPlurals converts the ICU message src tokenized into buffer for fromLocale
+// to a translator-ready skeleton for toLocale. For every plural and
+// selectordinal argument, options of CLDR plural categories unsupported by
+// toLocale are dropped and categories required by toLocale but missing
+// are added with contents seeded from option "other".
+// Options "=n", select arguments and nested contents are preserved.
+// Plural rules of both locales are taken from CLDRPluralRules.
+// The returned message is printed in canonical form (see Format).
+func AdaptPlurals(src string, buffer []Token, fromLocale, toLocale language.Tag) string {
+	if fromLocale == toLocale {
+		return Format(src, buffer)
+	}
+	cardinal, ordinal := CLDRPluralRules{}.PluralRules(toLocale)
+
+	targetRules := func(choiceIndex int) PluralRules {
+		if buffer[choiceIndex].Type == TokenTypeSelectOrdinal {
+			return ordinal
+		}
+		return cardinal
+	}
+	p := printer{src: src, buffer: buffer}
+	p.skipOption = func(choiceIndex, optionIndex int) bool {
+		if buffer[choiceIndex].Type == TokenTypeSelect {
+			return false
+		}
+		tp := buffer[optionIndex].Type
+		return tp >= TokenTypeOptionZero && tp < TokenTypeOptionOther &&
+			!targetRules(choiceIndex).has(tp)
+	}
+	p.afterOptions = func(index int) {
+		if t := buffer[index].Type; t != TokenTypePlural && t != TokenTypeSelectOrdinal {
+			return
+		}
+		var present PluralRules
+		other := -1
+		for j := range Options(buffer, index) {
+			present.set(buffer[j].Type)
+			if buffer[j].Type == TokenTypeOptionOther {
+				other = j
+			}
+		}
+		for tp := range targetRules(index).all() {
+			if present.has(tp) || tp == TokenTypeOptionOther {
+				continue
+			}
+			p.b.WriteByte(' ')
+			p.b.WriteString(categoryName(tp))
+			p.b.WriteString(" {")
+			p.printOptionContents(other)
+			p.b.WriteByte('}')
+		}
+	}
+	p.print(0, len(buffer))
+	return p.b.String()
+}
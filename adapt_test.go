@@ -0,0 +1,60 @@
+package icumsg_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestAdaptPlurals(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+
+	f := func(t *testing.T, from, to language.Tag, input, expect string) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(from, nil, input)
+		test.RequireNoErr(t, err)
+		actual := icumsg.AdaptPlurals(input, buffer, from, to)
+		test.RequireEqual(t, expect, actual)
+
+		// The result must be valid and complete for the target locale.
+		buffer, err = tokenizer.Tokenize(to, nil, actual)
+		test.RequireNoErr(t, err)
+		report := icumsg.NewCompletenessReport(actual, buffer, to, nil,
+			func(string) ([]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy) {
+				return nil, 0, 0
+			})
+		test.RequireEqual(t, 0, report.Incomplete)
+	}
+
+	f(t, language.English, language.English,
+		"{n,plural,one{# file} other{# files}}",
+		"{n, plural, one {# file} other {# files}}")
+	f(t, language.English, language.Arabic,
+		"{n, plural, =0{no files} one{# file} other{# files}}",
+		"{n, plural, =0 {no files} one {# file} other {# files}"+
+			" zero {# files} two {# files} few {# files} many {# files}}")
+	f(t, language.Arabic, language.English,
+		"{n, plural, zero{a} one{b} two{c} few{d} many{e} other{f}}",
+		"{n, plural, one {b} other {f}}")
+	f(t, language.English, language.Japanese,
+		"You have {n, plural, one{# file} other{# files}}.",
+		"You have {n, plural, other {# files}}.")
+	f(t, language.Japanese, language.English,
+		"{n, plural, =1{one file} other{# files}}",
+		"{n, plural, =1 {one file} other {# files} one {# files}}")
+	f(t, language.English, language.German,
+		"{p, selectordinal, one{#st} two{#nd} few{#rd} other{#th}}",
+		"{p, selectordinal, other {#th}}")
+	f(t, language.English, language.Polish,
+		"{g, select, female{{n, plural, one{her #} other{her #s}}} other{{n, plural, "+
+			"other{their #s}}}}",
+		"{g, select, female {{n, plural, one {her #} other {her #s} few {her #s}"+
+			" many {her #s}}} other {{n, plural, other {their #s} one {their #s}"+
+			" few {their #s} many {their #s}}}}")
+	// Nested plural inside a dropped option is dropped with it.
+	f(t, language.Ukrainian, language.English,
+		"{a, plural, few{{b, plural, few{x} other{y}}} other{{b, plural, many{z} other{w}}}}",
+		"{a, plural, other {{b, plural, other {w} one {w}}} one {{b, plural, other {w} one {w}}}}")
+}
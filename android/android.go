@@ -0,0 +1,346 @@
+// Package android reads and writes Android string resources (strings.xml)
+// and converts them to ICU messages and back.
+//
+// Arguments become positional format specifiers: "%1$s" for simple
+// arguments and "%1$d" for number arguments. Plural arguments become
+// <plurals> resources with an item per CLDR plural category where the
+// plural argument is always the first format argument and "#" becomes "%1$d".
+package android
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/printf"
+)
+
+// File is a string resource file.
+type File struct {
+	Resources []Resource
+}
+
+// Resource is a <string> or <plurals> resource.
+type Resource struct {
+	Name string
+
+	// Text is the unescaped text of a <string> resource.
+	Text string
+
+	// Items are the quantity items of a <plurals> resource
+	// and nil for <string> resources.
+	Items []Item
+}
+
+// IsPlurals returns true for <plurals> resources.
+func (r Resource) IsPlurals() bool { return r.Items != nil }
+
+// Item is a quantity item of a <plurals> resource.
+type Item struct {
+	Quantity cldr.Category
+
+	// Text is the unescaped text.
+	Text string
+}
+
+var (
+	ErrSyntax            = errors.New("syntax error")
+	ErrUnsupportedMarkup = errors.New("unsupported markup")
+	ErrUnknownQuantity   = errors.New("unknown quantity")
+
+	// ErrUnrepresentable is returned for ICU constructs that can't be
+	// represented, such as select arguments, "=n" options, offsets,
+	// nested plurals and arguments other than strings and integers.
+	ErrUnrepresentable = printf.ErrUnrepresentable
+
+	// ErrUnsupportedSpecifier is returned for format specifiers other
+	// than strings (s) and integers (d).
+	ErrUnsupportedSpecifier = printf.ErrUnsupportedSpecifier
+)
+
+var verbs = printf.Verbs{String: "s", Number: "d"}
+
+// FromICU converts the ICU message src tokenized into buffer
+// to a resource and returns the argument names by position.
+// Messages with a plural argument, optionally surrounded by text that's
+// repeated in every item, become <plurals> resources.
+func FromICU(name, src string, buffer []icumsg.Token) (Resource, []string, error) {
+	r := Resource{Name: name}
+	e := printf.Encoder{Verbs: verbs}
+	plural := -1
+	for i := 0; i < len(buffer); i++ {
+		if buffer[i].Type == icumsg.TokenTypePlural {
+			plural = i
+			break
+		} else if buffer[i].Type >= icumsg.TokenTypePlural {
+			i = buffer[i].IndexEnd
+		}
+	}
+	if plural == -1 {
+		var b strings.Builder
+		err := e.Encode(&b, src, buffer, 0, len(buffer), 0, nil)
+		r.Text = b.String()
+		return r, e.Args, err
+	}
+
+	pluralName := buffer[plural+1].String(src, buffer)
+	hash := e.Position(pluralName)
+	if buffer[plural+2].Type == icumsg.TokenTypePluralOffset {
+		return r, nil, fmt.Errorf("%w: offset of %q", ErrUnrepresentable, pluralName)
+	}
+	r.Items = []Item{}
+	multiple := func(int) error {
+		return fmt.Errorf("%w: multiple plural arguments", ErrUnrepresentable)
+	}
+	for j := range icumsg.Options(buffer, plural) {
+		c, ok := cldr.CategoryOf(buffer[j].Type)
+		if !ok {
+			return r, nil, fmt.Errorf("%w: option %s of %q",
+				ErrUnrepresentable, icumsg.OptionName(src, buffer, j), pluralName)
+		}
+		var b strings.Builder
+		err := e.Encode(&b, src, buffer, 0, plural, 0, multiple)
+		if err == nil {
+			err = e.Encode(&b, src, buffer, j+1, buffer[j].IndexEnd, hash, nil)
+		}
+		if err == nil {
+			err = e.Encode(&b, src, buffer,
+				buffer[plural].IndexEnd+1, len(buffer), 0, multiple)
+		}
+		if err != nil {
+			return r, nil, err
+		}
+		r.Items = append(r.Items, Item{Quantity: c, Text: b.String()})
+	}
+	return r, e.Args, nil
+}
+
+// ToICU converts r to an ICU message. args are the argument names by
+// position, arguments without name are named "arg1", "arg2", and so on.
+// The result isn't validated.
+func (r Resource) ToICU(args []string) (string, error) {
+	if !r.IsPlurals() {
+		return printf.Decode(r.Text, args, 0, nil)
+	}
+	name := "arg1"
+	if len(args) > 0 && args[0] != "" {
+		name = args[0]
+	}
+	var b strings.Builder
+	b.WriteString("{" + name + ", plural,")
+	for _, item := range r.Items {
+		s, err := printf.Decode(item.Text, args, 1, nil)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", item.Quantity, err)
+		}
+		b.WriteString(" " + item.Quantity.String() + " {" + s + "}")
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// Read reads a string resource file. Resources other than <string>
+// and <plurals> are ignored. Texts are unescaped: whitespace outside
+// of double quotes is collapsed and escape sequences are resolved.
+// Markup other than <xliff:g> is ErrUnsupportedMarkup.
+func Read(r io.Reader) (*File, error) {
+	d := xml.NewDecoder(r)
+	f := new(File)
+	var plurals *Resource
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return f, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "resources":
+			case "string":
+				s, err := readText(d)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", attr(t, "name"), err)
+				}
+				f.Resources = append(f.Resources, Resource{Name: attr(t, "name"), Text: s})
+			case "plurals":
+				plurals = &Resource{Name: attr(t, "name"), Items: []Item{}}
+			case "item":
+				if plurals == nil {
+					if err := d.Skip(); err != nil {
+						return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+					}
+					continue
+				}
+				c, err := cldr.ParseCategory(attr(t, "quantity"))
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w: %q",
+						plurals.Name, ErrUnknownQuantity, attr(t, "quantity"))
+				}
+				s, err := readText(d)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", plurals.Name, err)
+				}
+				plurals.Items = append(plurals.Items, Item{Quantity: c, Text: s})
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "plurals" && plurals != nil {
+				f.Resources = append(f.Resources, *plurals)
+				plurals = nil
+			}
+		}
+	}
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// readText reads and unescapes the content of the current element.
+func readText(d *xml.Decoder) (string, error) {
+	raw, err := readRaw(d)
+	if err != nil {
+		return "", err
+	}
+	return unescape(raw)
+}
+
+// readRaw reads the content of the current element unwrapping <xliff:g>.
+func readRaw(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.StartElement:
+			if t.Name.Local != "g" {
+				return "", fmt.Errorf("%w: <%s>", ErrUnsupportedMarkup, t.Name.Local)
+			}
+			s, err := readRaw(d)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		case xml.EndElement:
+			return b.String(), nil
+		}
+	}
+}
+
+// unescape resolves the escape sequences of s and collapses
+// whitespace outside of double quotes like aapt does.
+func unescape(s string) (string, error) {
+	var b strings.Builder
+	inQuote, space := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+			continue
+		case !inQuote && (c == ' ' || c == '\n' || c == '\t' || c == '\r'):
+			space = true
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i >= len(s) {
+			return "", fmt.Errorf("%w: unterminated escape sequence", ErrSyntax)
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("%w: invalid escape sequence \\%s", ErrSyntax, s[i:])
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("%w: invalid escape sequence \\%s", ErrSyntax, s[i:i+5])
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// Write writes f as a string resource file.
+func Write(w io.Writer, f *File) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n<resources>\n")
+	for _, r := range f.Resources {
+		if !r.IsPlurals() {
+			b.WriteString(`    <string name="` + attrEscaper.Replace(r.Name) + `">`)
+			b.WriteString(escape(r.Text))
+			b.WriteString("</string>\n")
+			continue
+		}
+		b.WriteString(`    <plurals name="` + attrEscaper.Replace(r.Name) + "\">\n")
+		for _, item := range r.Items {
+			b.WriteString(`        <item quantity="` + item.Quantity.String() + `">`)
+			b.WriteString(escape(item.Text))
+			b.WriteString("</item>\n")
+		}
+		b.WriteString("    </plurals>\n")
+	}
+	b.WriteString("</resources>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var (
+	textEscaper = strings.NewReplacer(
+		`\`, `\\`, `'`, `\'`, `"`, `\"`, "\n", `\n`, "\t", `\t`,
+		"&", "&amp;", "<", "&lt;", ">", "&gt;",
+	)
+	attrEscaper = strings.NewReplacer(
+		"&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;",
+	)
+)
+
+// escape escapes s for a resource text. Texts with whitespace
+// that would be collapsed are enclosed in double quotes.
+func escape(s string) string {
+	e := textEscaper.Replace(s)
+	if strings.HasPrefix(e, "@") || strings.HasPrefix(e, "?") {
+		e = `\` + e
+	}
+	first, _ := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	if first == ' ' || last == ' ' || strings.Contains(s, "  ") ||
+		strings.ContainsAny(s, "\r") {
+		return `"` + e + `"`
+	}
+	return e
+}
@@ -0,0 +1,149 @@
+package android_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/android"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+const stringsXML = `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="greeting">Hello,
+        <xliff:g id="name">%1$s</xliff:g>! It\'s \"100%%\"!</string>
+    <string name="spaces">"  padded  "</string>
+    <string-array name="ignored"><item>x</item></string-array>
+    <plurals name="files">
+        <item quantity="one">%1$d file in %2$s</item>
+        <item quantity="other">%1$d files in %2$s</item>
+    </plurals>
+</resources>
+`
+
+func TestRead(t *testing.T) {
+	f, err := android.Read(strings.NewReader(stringsXML))
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, []android.Resource{
+		{Name: "greeting", Text: `Hello, %1$s! It's "100%%"!`},
+		{Name: "spaces", Text: "  padded  "},
+		{Name: "files", Items: []android.Item{
+			{Quantity: cldr.CategoryOne, Text: "%1$d file in %2$s"},
+			{Quantity: cldr.CategoryOther, Text: "%1$d files in %2$s"},
+		}},
+	}, f.Resources)
+}
+
+func TestReadErr(t *testing.T) {
+	f := func(t *testing.T, input string, expect error) {
+		t.Helper()
+		_, err := android.Read(strings.NewReader(input))
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, `<resources><string name="a">x`, android.ErrSyntax)
+	f(t, `<resources><string name="a">x\</string></resources>`, android.ErrSyntax)
+	f(t, `<resources><string name="a">\u00</string></resources>`, android.ErrSyntax)
+	f(t, `<resources><string name="a"><b>x</b></string></resources>`,
+		android.ErrUnsupportedMarkup)
+	f(t, `<resources><plurals name="a"><item quantity="lots">x</item></plurals></resources>`,
+		android.ErrUnknownQuantity)
+}
+
+func TestWrite(t *testing.T) {
+	var b bytes.Buffer
+	test.RequireNoErr(t, android.Write(&b, &android.File{Resources: []android.Resource{
+		{Name: "a", Text: "It's \"x\" <b>\n@home"},
+		{Name: "b", Text: "@ref"},
+		{Name: "c", Text: " padded"},
+		{Name: "files", Items: []android.Item{
+			{Quantity: cldr.CategoryOne, Text: "%1$d file"},
+			{Quantity: cldr.CategoryOther, Text: "%1$d files"},
+		}},
+	}}))
+	test.RequireEqual(t, `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="a">It\'s \"x\" &lt;b&gt;\n@home</string>
+    <string name="b">\@ref</string>
+    <string name="c">" padded"</string>
+    <plurals name="files">
+        <item quantity="one">%1$d file</item>
+        <item quantity="other">%1$d files</item>
+    </plurals>
+</resources>
+`, b.String())
+
+	// Written files read back unchanged.
+	f, err := android.Read(&b)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "It's \"x\" <b>\n@home", f.Resources[0].Text)
+	test.RequireEqual(t, "@ref", f.Resources[1].Text)
+	test.RequireEqual(t, " padded", f.Resources[2].Text)
+}
+
+func TestFromICU(t *testing.T) {
+	f := func(t *testing.T, src string, expect android.Resource, expectArgs ...string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		actual, args, err := android.FromICU("r", src, buffer)
+		test.RequireNoErr(t, err)
+		test.RequireDeepEqual(t, expect, actual)
+		test.RequireDeepEqual(t, expectArgs, args)
+
+		// Converting back yields the original message.
+		back, err := actual.ToICU(args)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, src, back)
+	}
+
+	f(t, "It''s {name}, 100%", android.Resource{Name: "r", Text: "It's %1$s, 100%%"}, "name")
+	f(t, "{n, plural, one {# file in {dir}} other {# files in {dir}}}",
+		android.Resource{Name: "r", Items: []android.Item{
+			{Quantity: cldr.CategoryOne, Text: "%1$d file in %2$s"},
+			{Quantity: cldr.CategoryOther, Text: "%1$d files in %2$s"},
+		}}, "n", "dir")
+}
+
+func TestFromICUSurrounding(t *testing.T) {
+	src := "{dir}: {n, plural, one {# file} other {# files}}."
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.English, nil, src)
+	test.RequireNoErr(t, err)
+	r, args, err := android.FromICU("r", src, buffer)
+	test.RequireNoErr(t, err)
+	// The plural argument is always the first format argument.
+	test.RequireDeepEqual(t, []string{"n", "dir"}, args)
+	test.RequireDeepEqual(t, []android.Item{
+		{Quantity: cldr.CategoryOne, Text: "%2$s: %1$d file."},
+		{Quantity: cldr.CategoryOther, Text: "%2$s: %1$d files."},
+	}, r.Items)
+}
+
+func TestFromICUErr(t *testing.T) {
+	f := func(t *testing.T, src string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		_, _, err = android.FromICU("r", src, buffer)
+		test.RequireErrIs(t, android.ErrUnrepresentable, err)
+	}
+
+	f(t, "{g, select, other {x}}")
+	f(t, "{d, date}")
+	f(t, "{n, plural, =0 {none} other {#}}")
+	f(t, "{n, plural, offset:1 other {#}}")
+	f(t, "{n, plural, other {{m, plural, other {#}}}}")
+	f(t, "{n, plural, other {#}} {m, plural, other {#}}")
+}
+
+func TestToICUErr(t *testing.T) {
+	_, err := android.Resource{Text: "%.2f"}.ToICU(nil)
+	test.RequireErrIs(t, android.ErrUnsupportedSpecifier, err)
+}
@@ -0,0 +1,153 @@
+// Package apple reads and writes Apple string resources (.stringsdict
+// and .xcstrings) and converts them to ICU messages and back.
+//
+// Arguments become positional format specifiers: "%1$@" for simple
+// arguments and "%1$lld" for number arguments. Plural arguments become
+// plural variables referenced from the format as "%1$#@name@"
+// with an item per CLDR plural category where "#" becomes "%1$lld".
+package apple
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/printf"
+)
+
+// String is a localized format string with its plural variables.
+type String struct {
+	// Format is the format string referencing Variables by name.
+	Format string
+
+	Variables []Variable
+}
+
+// Variable is a plural variable (NSStringPluralRuleType).
+type Variable struct {
+	Name  string
+	Items []Item
+}
+
+// Item is a plural category item of a variable.
+type Item struct {
+	Category cldr.Category
+	Text     string
+}
+
+var (
+	ErrSyntax          = errors.New("syntax error")
+	ErrUnsupportedRule = errors.New("unsupported rule type")
+	ErrUndefined       = errors.New("undefined variable")
+
+	// ErrUnrepresentable is returned for ICU constructs that can't be
+	// represented, such as select arguments, "=n" options, offsets,
+	// nested plurals and arguments other than strings and integers.
+	ErrUnrepresentable = printf.ErrUnrepresentable
+
+	// ErrUnsupportedSpecifier is returned for format specifiers other
+	// than objects (@), strings (s), integers (d, i, u) and variables.
+	ErrUnsupportedSpecifier = printf.ErrUnsupportedSpecifier
+)
+
+var verbs = printf.Verbs{String: "@", Number: "lld"}
+
+// Variable returns the variable by name or nil if there is none.
+func (s *String) Variable(name string) *Variable {
+	for i := range s.Variables {
+		if s.Variables[i].Name == name {
+			return &s.Variables[i]
+		}
+	}
+	return nil
+}
+
+// FromICU converts the ICU message src tokenized into buffer to a string
+// and returns the argument names by position. Every plural argument
+// becomes a variable named after the argument.
+func FromICU(src string, buffer []icumsg.Token) (String, []string, error) {
+	var s String
+	e := printf.Encoder{Verbs: verbs}
+	var plural func(index int) error
+	plural = func(index int) error {
+		argName := buffer[index+1].String(src, buffer)
+		if buffer[index+2].Type == icumsg.TokenTypePluralOffset {
+			return fmt.Errorf("%w: offset of %q", ErrUnrepresentable, argName)
+		}
+		pos := e.Position(argName)
+		v := Variable{Name: argName, Items: []Item{}}
+		for i := 2; s.Variable(v.Name) != nil; i++ {
+			v.Name = fmt.Sprintf("%s_%d", argName, i)
+		}
+		for j := range icumsg.Options(buffer, index) {
+			c, ok := cldr.CategoryOf(buffer[j].Type)
+			if !ok {
+				return fmt.Errorf("%w: option %s of %q",
+					ErrUnrepresentable, icumsg.OptionName(src, buffer, j), argName)
+			}
+			var b strings.Builder
+			if err := e.Encode(&b, src, buffer, j+1, buffer[j].IndexEnd, pos, nil); err != nil {
+				return err
+			}
+			v.Items = append(v.Items, Item{Category: c, Text: b.String()})
+		}
+		s.Variables = append(s.Variables, v)
+		return nil
+	}
+	var b strings.Builder
+	// Variables are written in the plural callback and the format
+	// references them in the same order.
+	err := e.Encode(&b, src, buffer, 0, len(buffer), 0, func(index int) error {
+		if err := plural(index); err != nil {
+			return err
+		}
+		v := s.Variables[len(s.Variables)-1]
+		fmt.Fprintf(&b, "%%%d$#@%s@", e.Position(buffer[index+1].String(src, buffer)), v.Name)
+		return nil
+	})
+	if err != nil {
+		return String{}, nil, err
+	}
+	s.Format = b.String()
+	return s, e.Args, nil
+}
+
+// ToICU converts s to an ICU message. args are the argument names by
+// position, arguments without name are named "arg1", "arg2", and so on.
+// Plural variables without an argument name are named after the variable.
+// The result isn't validated.
+func (s String) ToICU(args []string) (string, error) {
+	return printf.Decode(s.Format, args, 0, func(position int, name string) (string, error) {
+		v := s.Variable(name)
+		if v == nil {
+			return "", fmt.Errorf("%w: %q", ErrUndefined, name)
+		}
+		argName := name
+		if position <= len(args) && args[position-1] != "" {
+			argName = args[position-1]
+		}
+		var b strings.Builder
+		b.WriteString("{" + argName + ", plural,")
+		for _, item := range v.Items {
+			t, err := printf.Decode(item.Text, args, position, nil)
+			if err != nil {
+				return "", fmt.Errorf("%s %s: %w", name, item.Category, err)
+			}
+			b.WriteString(" " + item.Category.String() + " {" + t + "}")
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	})
+}
+
+// positions returns the positions of the variables referenced by format.
+func positions(format string) map[string]int {
+	m := map[string]int{}
+	_, _ = printf.Decode(format, nil, 0, func(position int, name string) (string, error) {
+		m[name] = position
+		return "", nil
+	})
+	return m
+}
@@ -0,0 +1,90 @@
+package apple_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/apple"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestFromICU(t *testing.T) {
+	f := func(t *testing.T, src string, expect apple.String, expectArgs ...string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		actual, args, err := apple.FromICU(src, buffer)
+		test.RequireNoErr(t, err)
+		test.RequireDeepEqual(t, expect, actual)
+		test.RequireDeepEqual(t, expectArgs, args)
+
+		// Converting back yields the original message.
+		back, err := actual.ToICU(args)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, src, back)
+	}
+
+	f(t, "It''s {name}, 100%", apple.String{Format: "It's %1$@, 100%%"}, "name")
+	f(t, "{dir}: {n, plural, one {# file} other {# files}} and {d, number}",
+		apple.String{
+			Format: "%1$@: %2$#@n@ and %3$lld",
+			Variables: []apple.Variable{{Name: "n", Items: []apple.Item{
+				{Category: cldr.CategoryOne, Text: "%2$lld file"},
+				{Category: cldr.CategoryOther, Text: "%2$lld files"},
+			}}},
+		}, "dir", "n", "d")
+	// The same argument may be used by multiple variables.
+	f(t, "{n, plural, other {# a}} {n, plural, one {b} other {c {x}}}",
+		apple.String{
+			Format: "%1$#@n@ %1$#@n_2@",
+			Variables: []apple.Variable{
+				{Name: "n", Items: []apple.Item{
+					{Category: cldr.CategoryOther, Text: "%1$lld a"},
+				}},
+				{Name: "n_2", Items: []apple.Item{
+					{Category: cldr.CategoryOne, Text: "b"},
+					{Category: cldr.CategoryOther, Text: "c %2$@"},
+				}},
+			},
+		}, "n", "x")
+}
+
+func TestFromICUErr(t *testing.T) {
+	f := func(t *testing.T, src string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		_, _, err = apple.FromICU(src, buffer)
+		test.RequireErrIs(t, apple.ErrUnrepresentable, err)
+	}
+
+	f(t, "{g, select, other {x}}")
+	f(t, "{d, date}")
+	f(t, "{n, plural, =0 {none} other {#}}")
+	f(t, "{n, plural, offset:1 other {#}}")
+	f(t, "{n, plural, other {{m, plural, other {#}}}}")
+}
+
+func TestToICU(t *testing.T) {
+	// Arguments without name are named after the variable
+	// or their position.
+	actual, err := apple.String{
+		Format: "%#@files@ in %@",
+		Variables: []apple.Variable{{Name: "files", Items: []apple.Item{
+			{Category: cldr.CategoryOne, Text: "%d file"},
+			{Category: cldr.CategoryOther, Text: "%d files"},
+		}}},
+	}.ToICU(nil)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "{files, plural, one {# file} other {# files}} in {arg2}", actual)
+
+	_, err = apple.String{Format: "%#@files@"}.ToICU(nil)
+	test.RequireErrIs(t, apple.ErrUndefined, err)
+
+	_, err = apple.String{Format: "%.2f"}.ToICU(nil)
+	test.RequireErrIs(t, apple.ErrUnsupportedSpecifier, err)
+}
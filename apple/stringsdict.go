@@ -0,0 +1,186 @@
+package apple
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/romshark/icumsg/cldr"
+)
+
+// Stringsdict is a .stringsdict property list.
+type Stringsdict struct {
+	Entries []Entry
+}
+
+// Entry is a localized string by key.
+type Entry struct {
+	Key string
+	String
+}
+
+const (
+	keyFormat    = "NSStringLocalizedFormatKey"
+	keySpecType  = "NSStringFormatSpecTypeKey"
+	keyValueType = "NSStringFormatValueTypeKey"
+	rulePlural   = "NSStringPluralRuleType"
+)
+
+// plistDict is an ordered property list dictionary
+// with values of type string or plistDict.
+type plistDict []plistEntry
+
+type plistEntry struct {
+	key   string
+	value any
+}
+
+func (d plistDict) get(key string) any {
+	for _, e := range d {
+		if e.key == key {
+			return e.value
+		}
+	}
+	return nil
+}
+
+// ReadStringsdict reads a .stringsdict property list.
+// Variables of rule types other than NSStringPluralRuleType
+// are ErrUnsupportedRule.
+func ReadStringsdict(r io.Reader) (*Stringsdict, error) {
+	d := xml.NewDecoder(r)
+	var root plistDict
+	for root == nil {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "dict" {
+			if root, err = readDict(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+	f := new(Stringsdict)
+	for _, e := range root {
+		dict, ok := e.value.(plistDict)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: expected dict", ErrSyntax, e.key)
+		}
+		format, _ := dict.get(keyFormat).(string)
+		entry := Entry{Key: e.key, String: String{Format: format}}
+		for _, v := range dict {
+			if v.key == keyFormat {
+				continue
+			}
+			vdict, ok := v.value.(plistDict)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: %s: expected dict", ErrSyntax, e.key, v.key)
+			}
+			if t, _ := vdict.get(keySpecType).(string); t != rulePlural {
+				return nil, fmt.Errorf("%s: %s: %w: %q", e.key, v.key, ErrUnsupportedRule, t)
+			}
+			variable := Variable{Name: v.key, Items: []Item{}}
+			for _, item := range vdict {
+				c, err := cldr.ParseCategory(item.key)
+				if err != nil {
+					continue // Spec and value type keys.
+				}
+				text, ok := item.value.(string)
+				if !ok {
+					return nil, fmt.Errorf("%w: %s: %s: %s: expected string",
+						ErrSyntax, e.key, v.key, item.key)
+				}
+				variable.Items = append(variable.Items, Item{Category: c, Text: text})
+			}
+			entry.Variables = append(entry.Variables, variable)
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+	return f, nil
+}
+
+// readDict reads the contents of a <dict> element.
+func readDict(d *xml.Decoder) (plistDict, error) {
+	dict := plistDict{}
+	var key *string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value any
+			switch t.Name.Local {
+			case "key":
+				var k string
+				if err := d.DecodeElement(&k, &t); err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+				}
+				key = &k
+				continue
+			case "string":
+				var s string
+				if err := d.DecodeElement(&s, &t); err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+				}
+				value = s
+			case "dict":
+				if value, err = readDict(d); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, fmt.Errorf("%w: unsupported value <%s>", ErrSyntax, t.Name.Local)
+			}
+			if key == nil {
+				return nil, fmt.Errorf("%w: value without key", ErrSyntax)
+			}
+			dict = append(dict, plistEntry{key: *key, value: value})
+			key = nil
+		case xml.EndElement:
+			return dict, nil
+		}
+	}
+}
+
+// WriteStringsdict writes f as a .stringsdict property list.
+func WriteStringsdict(w io.Writer, f *Stringsdict) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	for _, e := range f.Entries {
+		writeElement(&b, 1, "key", e.Key)
+		b.WriteString("\t<dict>\n")
+		writeElement(&b, 2, "key", keyFormat)
+		writeElement(&b, 2, "string", e.Format)
+		for _, v := range e.Variables {
+			writeElement(&b, 2, "key", v.Name)
+			b.WriteString("\t\t<dict>\n")
+			writeElement(&b, 3, "key", keySpecType)
+			writeElement(&b, 3, "string", rulePlural)
+			writeElement(&b, 3, "key", keyValueType)
+			writeElement(&b, 3, "string", verbs.Number)
+			for _, item := range v.Items {
+				writeElement(&b, 3, "key", item.Category.String())
+				writeElement(&b, 3, "string", item.Text)
+			}
+			b.WriteString("\t\t</dict>\n")
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeElement writes an indented element with escaped text.
+func writeElement(b *strings.Builder, indent int, name, text string) {
+	b.WriteString(strings.Repeat("\t", indent) + "<" + name + ">")
+	_ = xml.EscapeText(b, []byte(text))
+	b.WriteString("</" + name + ">\n")
+}
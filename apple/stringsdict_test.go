@@ -0,0 +1,73 @@
+package apple_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg/apple"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+const stringsdict = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%1$#@n@ in &lt;%2$@&gt;</string>
+		<key>n</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>lld</string>
+			<key>one</key>
+			<string>%1$lld file</string>
+			<key>other</key>
+			<string>%1$lld files</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+func TestStringsdict(t *testing.T) {
+	f, err := apple.ReadStringsdict(strings.NewReader(stringsdict))
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, &apple.Stringsdict{Entries: []apple.Entry{{
+		Key: "files",
+		String: apple.String{
+			Format: "%1$#@n@ in <%2$@>",
+			Variables: []apple.Variable{{Name: "n", Items: []apple.Item{
+				{Category: cldr.CategoryOne, Text: "%1$lld file"},
+				{Category: cldr.CategoryOther, Text: "%1$lld files"},
+			}}},
+		},
+	}}}, f)
+
+	var b bytes.Buffer
+	test.RequireNoErr(t, apple.WriteStringsdict(&b, f))
+	test.RequireEqual(t, stringsdict, b.String())
+}
+
+func TestReadStringsdictErr(t *testing.T) {
+	f := func(t *testing.T, input string, expect error) {
+		t.Helper()
+		_, err := apple.ReadStringsdict(strings.NewReader(input))
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, `<plist><dict><key>a</key>`, apple.ErrSyntax)
+	f(t, `<plist><dict><key>a</key><string>x</string></dict></plist>`, apple.ErrSyntax)
+	f(t, `<plist><dict><string>x</string></dict></plist>`, apple.ErrSyntax)
+	f(t, `<plist><dict><key>a</key><integer>1</integer></dict></plist>`, apple.ErrSyntax)
+	f(t, `<plist><dict><key>a</key><dict>
+		<key>NSStringLocalizedFormatKey</key><string>%#@v@</string>
+		<key>v</key><dict>
+			<key>NSStringFormatSpecTypeKey</key><string>NSStringVariableWidthRuleType</string>
+		</dict>
+	</dict></dict></plist>`, apple.ErrUnsupportedRule)
+}
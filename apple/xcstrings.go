@@ -0,0 +1,197 @@
+package apple
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/icumsg/cldr"
+)
+
+// StringCatalog is an Xcode string catalog (.xcstrings).
+type StringCatalog struct {
+	SourceLanguage string
+
+	// Strings are sorted by key.
+	Strings []CatalogString
+}
+
+// CatalogString is a string of a catalog by key.
+type CatalogString struct {
+	Key     string
+	Comment string
+
+	// Localizations are the localized strings by language.
+	Localizations map[string]Localization
+}
+
+// Localization is a localized string of a catalog.
+type Localization struct {
+	// State is the translation state, such as "translated" or "needs_review".
+	State string
+	String
+}
+
+// variablePlural is the name of the variable a top-level plural
+// variation of a localization is read as.
+const variablePlural = "plural"
+
+type xcFile struct {
+	SourceLanguage string              `json:"sourceLanguage"`
+	Strings        map[string]xcString `json:"strings"`
+	Version        string              `json:"version"`
+}
+
+type xcString struct {
+	Comment       string                    `json:"comment,omitempty"`
+	Localizations map[string]xcLocalization `json:"localizations,omitempty"`
+}
+
+type xcLocalization struct {
+	StringUnit    *xcUnit                   `json:"stringUnit,omitempty"`
+	Substitutions map[string]xcSubstitution `json:"substitutions,omitempty"`
+	Variations    *xcVariations             `json:"variations,omitempty"`
+}
+
+type xcUnit struct {
+	State string `json:"state"`
+	Value string `json:"value"`
+}
+
+type xcSubstitution struct {
+	ArgNum          int          `json:"argNum"`
+	FormatSpecifier string       `json:"formatSpecifier"`
+	Variations      xcVariations `json:"variations"`
+}
+
+type xcVariations struct {
+	Plural map[string]xcVariation `json:"plural"`
+}
+
+type xcVariation struct {
+	StringUnit xcUnit `json:"stringUnit"`
+}
+
+// ReadStringCatalog reads an Xcode string catalog. Substitutions refer to
+// their argument as "%arg" which is replaced by a positional specifier.
+// Top-level plural variations are read as a variable named "plural"
+// of the first argument referenced by the format "%1$#@plural@".
+// Variations other than plural are ErrUnsupportedRule.
+func ReadStringCatalog(r io.Reader) (*StringCatalog, error) {
+	var x xcFile
+	if err := json.NewDecoder(r).Decode(&x); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+	c := &StringCatalog{SourceLanguage: x.SourceLanguage}
+	for _, key := range slices.Sorted(maps.Keys(x.Strings)) {
+		xs := x.Strings[key]
+		s := CatalogString{Key: key, Comment: xs.Comment, Localizations: map[string]Localization{}}
+		for lang, xl := range xs.Localizations {
+			var l Localization
+			switch {
+			case xl.StringUnit != nil:
+				l.State, l.Format = xl.StringUnit.State, xl.StringUnit.Value
+			case xl.Variations != nil && xl.Variations.Plural != nil:
+				l.Format = "%1$#@" + variablePlural + "@"
+				v, state, err := readVariations(*xl.Variations, "")
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", key, lang, err)
+				}
+				l.State = state
+				v.Name = variablePlural
+				l.Variables = append(l.Variables, v)
+			default:
+				return nil, fmt.Errorf("%s: %s: %w: expected stringUnit or plural variations",
+					key, lang, ErrUnsupportedRule)
+			}
+			for _, name := range slices.Sorted(maps.Keys(xl.Substitutions)) {
+				sub := xl.Substitutions[name]
+				v, _, err := readVariations(sub.Variations, "%"+strconv.Itoa(sub.ArgNum)+"$"+
+					sub.FormatSpecifier)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %s: %w", key, lang, name, err)
+				}
+				v.Name = name
+				l.Variables = append(l.Variables, v)
+			}
+			s.Localizations[lang] = l
+		}
+		c.Strings = append(c.Strings, s)
+	}
+	return c, nil
+}
+
+// readVariations reads plural variations replacing "%arg" by arg
+// unless arg is empty.
+func readVariations(x xcVariations, arg string) (v Variable, state string, err error) {
+	if x.Plural == nil {
+		return v, "", fmt.Errorf("%w: expected plural variations", ErrUnsupportedRule)
+	}
+	v.Items = []Item{}
+	for name, variation := range x.Plural {
+		c, err := cldr.ParseCategory(name)
+		if err != nil {
+			return v, "", fmt.Errorf("%w: unknown plural category %q", ErrSyntax, name)
+		}
+		text := variation.StringUnit.Value
+		if arg != "" {
+			text = strings.ReplaceAll(text, "%arg", arg)
+		}
+		v.Items = append(v.Items, Item{Category: c, Text: text})
+		state = variation.StringUnit.State
+	}
+	slices.SortFunc(v.Items, func(a, b Item) int { return int(a.Category) - int(b.Category) })
+	return v, state, nil
+}
+
+// WriteStringCatalog writes c as an Xcode string catalog. Variables become
+// substitutions referring to their argument as "%arg".
+// Localizations without a state are written as "translated".
+func WriteStringCatalog(w io.Writer, c *StringCatalog) error {
+	x := xcFile{
+		SourceLanguage: c.SourceLanguage,
+		Strings:        map[string]xcString{},
+		Version:        "1.0",
+	}
+	for _, s := range c.Strings {
+		xs := xcString{Comment: s.Comment}
+		if len(s.Localizations) > 0 {
+			xs.Localizations = map[string]xcLocalization{}
+		}
+		for lang, l := range s.Localizations {
+			state := l.State
+			if state == "" {
+				state = "translated"
+			}
+			xl := xcLocalization{StringUnit: &xcUnit{State: state, Value: l.Format}}
+			pos := positions(l.Format)
+			for _, v := range l.Variables {
+				if xl.Substitutions == nil {
+					xl.Substitutions = map[string]xcSubstitution{}
+				}
+				arg := "%" + strconv.Itoa(pos[v.Name]) + "$" + verbs.Number
+				sub := xcSubstitution{
+					ArgNum:          pos[v.Name],
+					FormatSpecifier: verbs.Number,
+					Variations:      xcVariations{Plural: map[string]xcVariation{}},
+				}
+				for _, item := range v.Items {
+					sub.Variations.Plural[item.Category.String()] = xcVariation{
+						StringUnit: xcUnit{State: state, Value: strings.ReplaceAll(item.Text, arg, "%arg")},
+					}
+				}
+				xl.Substitutions[v.Name] = sub
+			}
+			xs.Localizations[lang] = xl
+		}
+		x.Strings[s.Key] = xs
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.SetEscapeHTML(false)
+	return e.Encode(x)
+}
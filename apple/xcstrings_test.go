@@ -0,0 +1,126 @@
+package apple_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg/apple"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+const xcstrings = `{
+  "sourceLanguage": "en",
+  "strings": {
+    "files": {
+      "comment": "File count",
+      "localizations": {
+        "en": {
+          "stringUnit": {
+            "state": "translated",
+            "value": "%1$#@n@ in %2$@"
+          },
+          "substitutions": {
+            "n": {
+              "argNum": 1,
+              "formatSpecifier": "lld",
+              "variations": {
+                "plural": {
+                  "one": {
+                    "stringUnit": {
+                      "state": "translated",
+                      "value": "%arg file"
+                    }
+                  },
+                  "other": {
+                    "stringUnit": {
+                      "state": "translated",
+                      "value": "%arg files"
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "hello": {
+      "localizations": {
+        "de": {
+          "stringUnit": {
+            "state": "needs_review",
+            "value": "Hallo <%@>"
+          }
+        }
+      }
+    }
+  },
+  "version": "1.0"
+}
+`
+
+func TestStringCatalog(t *testing.T) {
+	c, err := apple.ReadStringCatalog(strings.NewReader(xcstrings))
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, &apple.StringCatalog{
+		SourceLanguage: "en",
+		Strings: []apple.CatalogString{
+			{
+				Key: "files", Comment: "File count",
+				Localizations: map[string]apple.Localization{
+					"en": {State: "translated", String: apple.String{
+						Format: "%1$#@n@ in %2$@",
+						Variables: []apple.Variable{{Name: "n", Items: []apple.Item{
+							{Category: cldr.CategoryOne, Text: "%1$lld file"},
+							{Category: cldr.CategoryOther, Text: "%1$lld files"},
+						}}},
+					}},
+				},
+			},
+			{
+				Key: "hello",
+				Localizations: map[string]apple.Localization{
+					"de": {State: "needs_review", String: apple.String{Format: "Hallo <%@>"}},
+				},
+			},
+		},
+	}, c)
+
+	var b bytes.Buffer
+	test.RequireNoErr(t, apple.WriteStringCatalog(&b, c))
+	test.RequireEqual(t, xcstrings, b.String())
+}
+
+func TestReadStringCatalogPluralVariations(t *testing.T) {
+	c, err := apple.ReadStringCatalog(strings.NewReader(`{
+		"sourceLanguage": "en",
+		"strings": {"files": {"localizations": {"en": {"variations": {"plural": {
+			"other": {"stringUnit": {"state": "translated", "value": "%lld files"}},
+			"one": {"stringUnit": {"state": "translated", "value": "%lld file"}}
+		}}}}}}
+	}`))
+	test.RequireNoErr(t, err)
+	l := c.Strings[0].Localizations["en"]
+	test.RequireEqual(t, "translated", l.State)
+	actual, err := l.ToICU([]string{"n"})
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "{n, plural, one {# file} other {# files}}", actual)
+}
+
+func TestReadStringCatalogErr(t *testing.T) {
+	f := func(t *testing.T, input string, expect error) {
+		t.Helper()
+		_, err := apple.ReadStringCatalog(strings.NewReader(input))
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, `{"strings": `, apple.ErrSyntax)
+	f(t, `{"strings": {"a": {"localizations": {"en": {}}}}}`, apple.ErrUnsupportedRule)
+	f(t, `{"strings": {"a": {"localizations": {"en": {"variations": {"device": {}}}}}}}`,
+		apple.ErrUnsupportedRule)
+	f(t, `{"strings": {"a": {"localizations": {"en": {"variations": {"plural": {
+		"lots": {"stringUnit": {"state": "translated", "value": "x"}}
+	}}}}}}}`, apple.ErrSyntax)
+}
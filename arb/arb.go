@@ -0,0 +1,352 @@
+// Package arb reads and writes Application Resource Bundle (ARB) files
+// and validates their ICU messages and placeholder declarations.
+package arb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"golang.org/x/text/language"
+)
+
+// File is an ARB file.
+type File struct {
+	// Locale is the value of "@@locale" or language.Und if there's none.
+	Locale language.Tag
+
+	// Attributes are the global attributes ("@@" keys) other than
+	// "@@locale" in the order of appearance.
+	Attributes []Attribute
+
+	// Messages are the messages in the order of appearance.
+	Messages []Message
+}
+
+// Attribute is a global attribute of an ARB file.
+type Attribute struct {
+	// Name is the name including the "@@" prefix, such as "@@last_modified".
+	Name  string
+	Value json.RawMessage
+}
+
+// Message is a message of an ARB file.
+type Message struct {
+	ID string
+
+	// Text is the ICU message.
+	Text string
+
+	// Metadata is the raw JSON metadata object ("@" followed by the ID)
+	// or nil if there's none. It's preserved as is, see Meta for
+	// a structured view.
+	Metadata json.RawMessage
+}
+
+// Metadata is the structured view of message metadata.
+type Metadata struct {
+	Description  string                 `json:"description,omitempty"`
+	Type         string                 `json:"type,omitempty"`
+	Context      string                 `json:"context,omitempty"`
+	Placeholders map[string]Placeholder `json:"placeholders,omitempty"`
+}
+
+// Placeholder is the declaration of a message argument.
+type Placeholder struct {
+	// Type is the type of the argument, such as "String", "int", "double",
+	// "num", "DateTime" or "Object". Empty if not declared.
+	Type        string `json:"type,omitempty"`
+	Example     string `json:"example,omitempty"`
+	Description string `json:"description,omitempty"`
+	Format      string `json:"format,omitempty"`
+}
+
+// Meta returns the structured view of the metadata of m.
+// Unknown fields are ignored.
+func (m Message) Meta() (Metadata, error) {
+	var meta Metadata
+	if m.Metadata == nil {
+		return meta, nil
+	}
+	if err := json.Unmarshal(m.Metadata, &meta); err != nil {
+		return meta, fmt.Errorf("%s: %w: %v", m.ID, ErrInvalidMetadata, err)
+	}
+	return meta, nil
+}
+
+var (
+	ErrSyntax          = errors.New("syntax error")
+	ErrInvalidValue    = errors.New("message value is not a string")
+	ErrInvalidMetadata = errors.New("invalid metadata")
+	ErrOrphanMetadata  = errors.New("metadata without message")
+	ErrDuplicateKey    = errors.New("duplicate key")
+)
+
+// Read reads an ARB file.
+func Read(r io.Reader) (*File, error) {
+	d := json.NewDecoder(r)
+	if err := expectDelim(d, '{'); err != nil {
+		return nil, err
+	}
+	f := new(File)
+	metadata := map[string]json.RawMessage{}
+	seen := map[string]struct{}{}
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		key := t.(string) // Object keys are always strings.
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateKey, key)
+		}
+		seen[key] = struct{}{}
+		var value json.RawMessage
+		if err := d.Decode(&value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch {
+		case key == "@@locale":
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return nil, fmt.Errorf("%w: @@locale: %v", ErrInvalidValue, err)
+			}
+			if f.Locale, err = language.Parse(s); err != nil {
+				return nil, fmt.Errorf("invalid @@locale %q: %w", s, err)
+			}
+		case strings.HasPrefix(key, "@@"):
+			f.Attributes = append(f.Attributes, Attribute{Name: key, Value: value})
+		case strings.HasPrefix(key, "@"):
+			if !bytes.HasPrefix(value, []byte("{")) {
+				return nil, fmt.Errorf("%w: %s: not an object", ErrInvalidMetadata, key)
+			}
+			metadata[key[1:]] = value
+		default:
+			var text string
+			if err := json.Unmarshal(value, &text); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidValue, key)
+			}
+			f.Messages = append(f.Messages, Message{ID: key, Text: text})
+		}
+	}
+	if err := expectDelim(d, '}'); err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after object", ErrSyntax)
+	}
+	for i := range f.Messages {
+		m := &f.Messages[i]
+		if meta, ok := metadata[m.ID]; ok {
+			m.Metadata = meta
+			delete(metadata, m.ID)
+		}
+	}
+	if len(metadata) > 0 {
+		ids := make([]string, 0, len(metadata))
+		for id := range metadata {
+			ids = append(ids, "@"+id)
+		}
+		slices.Sort(ids)
+		return nil, fmt.Errorf("%w: %s", ErrOrphanMetadata, strings.Join(ids, ", "))
+	}
+	return f, nil
+}
+
+func expectDelim(d *json.Decoder, delim json.Delim) error {
+	t, err := d.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+	if t != delim {
+		return fmt.Errorf("%w: expected %q", ErrSyntax, delim)
+	}
+	return nil
+}
+
+// Write writes f as an indented ARB file: "@@locale" first followed
+// by the other global attributes and the messages each followed
+// by its metadata. Attribute and metadata values are written unchanged
+// except for indentation.
+func Write(w io.Writer, f *File) error {
+	var b bytes.Buffer
+	b.WriteString("{")
+	first := true
+	writeKey := func(key string) {
+		if !first {
+			b.WriteByte(',')
+		}
+		first = false
+		b.WriteString("\n  ")
+		b.Write(marshalString(key))
+		b.WriteString(": ")
+	}
+	writeRaw := func(key string, value json.RawMessage) error {
+		writeKey(key)
+		if err := json.Indent(&b, value, "  ", "  "); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		return nil
+	}
+
+	if f.Locale != language.Und {
+		writeKey("@@locale")
+		b.Write(marshalString(f.Locale.String()))
+	}
+	for _, a := range f.Attributes {
+		if err := writeRaw(a.Name, a.Value); err != nil {
+			return err
+		}
+	}
+	for _, m := range f.Messages {
+		writeKey(m.ID)
+		b.Write(marshalString(m.Text))
+		if m.Metadata != nil {
+			if err := writeRaw("@"+m.ID, m.Metadata); err != nil {
+				return err
+			}
+		}
+	}
+	b.WriteString("\n}\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// marshalString returns s as a JSON string without HTML escaping.
+func marshalString(s string) []byte {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	_ = e.Encode(s) // Encoding a string never fails.
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+var (
+	ErrPlaceholderUnused     = errors.New("placeholder declared but not used")
+	ErrPlaceholderUndeclared = errors.New("argument not declared as placeholder")
+	ErrPlaceholderType       = errors.New("placeholder type mismatch")
+)
+
+// Problem is a problem with a message of an ARB file.
+type Problem struct {
+	ID string
+
+	// Placeholder is the name of the placeholder for placeholder problems.
+	Placeholder string
+
+	// Err is either the tokenizer error for invalid messages,
+	// ErrInvalidMetadata or one of ErrPlaceholderUnused,
+	// ErrPlaceholderUndeclared and ErrPlaceholderType.
+	Err error
+}
+
+func (p Problem) Error() string {
+	if p.Placeholder != "" {
+		return fmt.Sprintf("%s: %v: %q", p.ID, p.Err, p.Placeholder)
+	}
+	return fmt.Sprintf("%s: %v", p.ID, p.Err)
+}
+
+func (p Problem) Unwrap() error { return p.Err }
+
+// Validate tokenizes all messages for the locale and cross-checks
+// the declared placeholders against the arguments of each message.
+// If locale is language.Und then f.Locale is used.
+//
+// Placeholders must be used by the message. If a message declares
+// placeholders then all of its arguments must be declared.
+// The declared type must match the type inferred from the argument:
+//
+//   - "int", "double" and "num" for plural, selectordinal, number,
+//     spellout, ordinal and duration arguments.
+//   - "DateTime" for date and time arguments.
+//   - "String" for select arguments.
+//
+// Simple arguments ({name}) and placeholders of type "Object" or
+// without type match any type.
+func (f *File) Validate(locale language.Tag) []Problem {
+	if locale == language.Und {
+		locale = f.Locale
+	}
+	var problems []Problem
+	var tokenizer icumsg.Tokenizer
+	var buffer []icumsg.Token
+	for _, m := range f.Messages {
+		var err error
+		buffer, err = tokenizer.Tokenize(locale, buffer[:0], m.Text)
+		if err != nil {
+			problems = append(problems, Problem{ID: m.ID, Err: err})
+			continue
+		}
+		meta, err := m.Meta()
+		if err != nil {
+			problems = append(problems, Problem{ID: m.ID, Err: ErrInvalidMetadata})
+			continue
+		}
+		problems = checkPlaceholders(problems, m, meta, buffer)
+	}
+	return problems
+}
+
+func checkPlaceholders(
+	problems []Problem, m Message, meta Metadata, buffer []icumsg.Token,
+) []Problem {
+	used := map[string]struct{}{}
+	for a := range icumsg.Arguments(m.Text, buffer) {
+		_, seen := used[a.Name]
+		used[a.Name] = struct{}{}
+		p, declared := meta.Placeholders[a.Name]
+		switch {
+		case !declared && !seen && meta.Placeholders != nil:
+			problems = append(problems, Problem{
+				ID: m.ID, Placeholder: a.Name, Err: ErrPlaceholderUndeclared,
+			})
+		case declared && !typeMatches(p.Type, a.Type):
+			problems = append(problems, Problem{
+				ID: m.ID, Placeholder: a.Name, Err: fmt.Errorf(
+					"%w: declared %q but used as %s", ErrPlaceholderType, p.Type, InferType(a.Type),
+				),
+			})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(meta.Placeholders)) {
+		if _, ok := used[name]; !ok {
+			problems = append(problems, Problem{
+				ID: m.ID, Placeholder: name, Err: ErrPlaceholderUnused,
+			})
+		}
+	}
+	return problems
+}
+
+// InferType returns the placeholder type inferred from the type of
+// an argument (see icumsg.Argument): "num", "DateTime", "String"
+// or "Object" if any type is accepted.
+func InferType(t icumsg.TokenType) string {
+	switch (icumsg.Argument{Type: t}).Class() {
+	case icumsg.ArgumentClassNumber:
+		return "num"
+	case icumsg.ArgumentClassDate:
+		return "DateTime"
+	case icumsg.ArgumentClassString:
+		return "String"
+	}
+	return "Object"
+}
+
+func typeMatches(declared string, t icumsg.TokenType) bool {
+	inferred := InferType(t)
+	switch {
+	case declared == "" || declared == "Object" || inferred == "Object":
+		return true
+	case inferred == "num":
+		return declared == "num" || declared == "int" || declared == "double"
+	}
+	return declared == inferred
+}
@@ -0,0 +1,161 @@
+package arb_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/arb"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+const arbFile = `{
+  "@@locale": "de",
+  "@@last_modified": "2026-01-02T10:00:00Z",
+  "@@x-generator": {"name": "tool", "version": [1, 2]},
+  "greeting": "Hallo {name} <3",
+  "@greeting": {
+    "description": "Begrüßung",
+    "placeholders": {
+      "name": {"type": "String", "example": "Anna", "x-custom": 1.50}
+    }
+  },
+  "files": "{count, plural, one {# Datei} other {# Dateien}}",
+  "@files": {"placeholders": {"count": {"type": "int", "format": "compact"}}},
+  "plain": "Ohne Metadaten"
+}`
+
+func TestReadWrite(t *testing.T) {
+	f, err := arb.Read(strings.NewReader(arbFile))
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.German, f.Locale)
+	test.RequireEqual(t, 2, len(f.Attributes))
+	test.RequireEqual(t, "@@last_modified", f.Attributes[0].Name)
+	test.RequireEqual(t, 3, len(f.Messages))
+	test.RequireEqual(t, "greeting", f.Messages[0].ID)
+	test.RequireEqual(t, "Hallo {name} <3", f.Messages[0].Text)
+	test.RequireEqual(t, "plain", f.Messages[2].ID)
+	test.RequireEqual(t, 0, len(f.Messages[2].Metadata))
+
+	meta, err := f.Messages[0].Meta()
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "Begrüßung", meta.Description)
+	test.RequireEqual(t, arb.Placeholder{Type: "String", Example: "Anna"},
+		meta.Placeholders["name"])
+
+	var b bytes.Buffer
+	test.RequireNoErr(t, arb.Write(&b, f))
+	written := b.String()
+	test.RequireEqual(t, `{
+  "@@locale": "de",
+  "@@last_modified": "2026-01-02T10:00:00Z",
+  "@@x-generator": {
+    "name": "tool",
+    "version": [
+      1,
+      2
+    ]
+  },
+  "greeting": "Hallo {name} <3",
+  "@greeting": {
+    "description": "Begrüßung",
+    "placeholders": {
+      "name": {
+        "type": "String",
+        "example": "Anna",
+        "x-custom": 1.50
+      }
+    }
+  },
+  "files": "{count, plural, one {# Datei} other {# Dateien}}",
+  "@files": {
+    "placeholders": {
+      "count": {
+        "type": "int",
+        "format": "compact"
+      }
+    }
+  },
+  "plain": "Ohne Metadaten"
+}
+`, written)
+
+	// Round-tripping is idempotent.
+	f, err = arb.Read(strings.NewReader(written))
+	test.RequireNoErr(t, err)
+	b.Reset()
+	test.RequireNoErr(t, arb.Write(&b, f))
+	test.RequireEqual(t, written, b.String())
+}
+
+func TestReadErr(t *testing.T) {
+	f := func(t *testing.T, input string, expect error) {
+		t.Helper()
+		_, err := arb.Read(strings.NewReader(input))
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, ``, arb.ErrSyntax)
+	f(t, `[]`, arb.ErrSyntax)
+	f(t, `{"a": "b"`, arb.ErrSyntax)
+	f(t, `{"a": "b"} {}`, arb.ErrSyntax)
+	f(t, `{"a": 1}`, arb.ErrInvalidValue)
+	f(t, `{"@@locale": 1}`, arb.ErrInvalidValue)
+	f(t, `{"a": "b", "@a": "c"}`, arb.ErrInvalidMetadata)
+	f(t, `{"a": "b", "@c": {}}`, arb.ErrOrphanMetadata)
+	f(t, `{"a": "b", "a": "c"}`, arb.ErrDuplicateKey)
+}
+
+func TestValidate(t *testing.T) {
+	f, err := arb.Read(strings.NewReader(`{
+		"@@locale": "en",
+		"ok": "{n, plural, one {# file} other {# files}} by {user} on {d, date}",
+		"@ok": {"placeholders": {
+			"n": {"type": "int"}, "user": {"type": "String"}, "d": {"type": "DateTime"}
+		}},
+		"inferred": "{n, plural, other {#}}",
+		"broken": "{n, plural, one {#}}",
+		"unused": "Hello",
+		"@unused": {"placeholders": {"name": {}}},
+		"undeclared": "{a} {b} {b}",
+		"@undeclared": {"placeholders": {"a": {}}},
+		"type": "{n, plural, other {#}} {g, select, other {x}} {d, time}",
+		"@type": {"placeholders": {
+			"n": {"type": "String"}, "g": {"type": "Object"}, "d": {"type": "int"}
+		}},
+		"meta": "x",
+		"@meta": {"placeholders": []}
+	}`))
+	test.RequireNoErr(t, err)
+
+	var actual []string
+	for _, p := range f.Validate(language.Und) {
+		actual = append(actual, p.Error())
+	}
+	test.RequireDeepEqual(t, []string{
+		"broken: missing the mandatory 'other' option",
+		`unused: placeholder declared but not used: "name"`,
+		`undeclared: argument not declared as placeholder: "b"`,
+		`type: placeholder type mismatch: declared "String" but used as num: "n"`,
+		`type: placeholder type mismatch: declared "int" but used as DateTime: "d"`,
+		"meta: invalid metadata",
+	}, actual)
+
+	problems := f.Validate(language.Und)
+	test.RequireErrIs(t, icumsg.ErrMissingOptionOther, problems[0])
+	test.RequireErrIs(t, arb.ErrPlaceholderType, problems[3])
+
+	// Japanese has no plural category "one".
+	problems = f.Validate(language.Japanese)
+	test.RequireErrIs(t, icumsg.ErrUnsupportedPluralRule, problems[0])
+}
+
+func TestInferType(t *testing.T) {
+	test.RequireEqual(t, "num", arb.InferType(icumsg.TokenTypePlural))
+	test.RequireEqual(t, "num", arb.InferType(icumsg.TokenTypeArgTypeNumber))
+	test.RequireEqual(t, "DateTime", arb.InferType(icumsg.TokenTypeArgTypeTime))
+	test.RequireEqual(t, "String", arb.InferType(icumsg.TokenTypeSelect))
+	test.RequireEqual(t, "Object", arb.InferType(icumsg.TokenTypeSimpleArg))
+}
@@ -0,0 +1,123 @@
+package icumsg
+
+import "iter"
+
+// Argument is an argument of an ICU message.
+type Argument struct {
+	// Name is the name of the argument.
+	Name string
+
+	// Index is the index of the argument token in the token buffer.
+	Index int
+
+	// Type is either of:
+	//
+	//   - TokenTypeSimpleArg for simple arguments without type ({name})
+	//   - TokenTypeArgTypeNumber to TokenTypeArgTypeDuration for typed simple arguments
+	//   - TokenTypePlural, TokenTypeSelect or TokenTypeSelectOrdinal
+	Type TokenType
+
+	// Style is the argument style token type (TokenTypeArgStyleShort to
+	// TokenTypeArgStyleSkeleton) or 0 if there's no style.
+	Style TokenType
+}
+
+// ArgumentClass is the class of values an argument accepts.
+type ArgumentClass int8
+
+const (
+	// ArgumentClassAny is accepted by simple arguments without type.
+	ArgumentClassAny ArgumentClass = iota
+
+	// ArgumentClassNumber is accepted by number, spellout, ordinal and
+	// duration arguments as well as plural and selectordinal.
+	ArgumentClassNumber
+
+	// ArgumentClassDate is accepted by date and time arguments.
+	ArgumentClassDate
+
+	// ArgumentClassString is accepted by select.
+	ArgumentClassString
+)
+
+// String returns "any", "number", "date" or "string".
+func (c ArgumentClass) String() string {
+	switch c {
+	case ArgumentClassNumber:
+		return "number"
+	case ArgumentClassDate:
+		return "date"
+	case ArgumentClassString:
+		return "string"
+	}
+	return "any"
+}
+
+// Class returns the class of values the argument accepts.
+func (a Argument) Class() ArgumentClass {
+	switch a.Type {
+	case TokenTypeArgTypeNumber,
+		TokenTypeArgTypeSpellout,
+		TokenTypeArgTypeOrdinal,
+		TokenTypeArgTypeDuration,
+		TokenTypePlural,
+		TokenTypeSelectOrdinal:
+		return ArgumentClassNumber
+	case TokenTypeArgTypeDate, TokenTypeArgTypeTime:
+		return ArgumentClassDate
+	case TokenTypeSelect:
+		return ArgumentClassString
+	}
+	return ArgumentClassAny
+}
+
+// SimpleArgEnd returns the index of the last token of the simple argument
+// at buffer[index], which is its name, type or style. Like IndexEnd of
+// complex tokens it allows skipping the argument:
+//
+//	i = SimpleArgEnd(buffer, i)
+func SimpleArgEnd(buffer []Token, index int) int {
+	end := index + 1 // The argument name.
+	for end+1 < len(buffer) && buffer[end+1].Type >= TokenTypeArgTypeNumber &&
+		buffer[end+1].Type <= TokenTypeArgStyleSkeleton {
+		end++
+	}
+	return end
+}
+
+// Arguments returns an iterator iterating over all arguments of the message
+// in the order of appearance including arguments nested in options.
+// The same argument name may appear more than once.
+func Arguments(src string, buffer []Token) iter.Seq[Argument] {
+	return func(yield func(Argument) bool) {
+		for i, t := range buffer {
+			switch t.Type {
+			case TokenTypeSimpleArg:
+				a := Argument{
+					Name:  buffer[i+1].String(src, buffer),
+					Index: i,
+					Type:  TokenTypeSimpleArg,
+				}
+				if i+2 < len(buffer) && buffer[i+2].Type >= TokenTypeArgTypeNumber &&
+					buffer[i+2].Type <= TokenTypeArgTypeDuration {
+					a.Type = buffer[i+2].Type
+					if i+3 < len(buffer) && buffer[i+3].Type >= TokenTypeArgStyleShort &&
+						buffer[i+3].Type <= TokenTypeArgStyleSkeleton {
+						a.Style = buffer[i+3].Type
+					}
+				}
+				if !yield(a) {
+					return
+				}
+			case TokenTypePlural, TokenTypeSelect, TokenTypeSelectOrdinal:
+				if !yield(Argument{
+					Name:  buffer[i+1].String(src, buffer),
+					Index: i,
+					Type:  t.Type,
+				}) {
+					return
+				}
+			}
+		}
+	}
+}
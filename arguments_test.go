@@ -0,0 +1,98 @@
+package icumsg_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestArguments(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+
+	f := func(t *testing.T, input string, expect ...icumsg.Argument) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(language.English, nil, input)
+		test.RequireNoErr(t, err)
+		test.RequireDeepEqual(t, expect,
+			slices.Collect(icumsg.Arguments(input, buffer)))
+	}
+
+	f(t, "No arguments")
+	f(t, "Hello {name}!", icumsg.Argument{
+		Name: "name", Index: 1, Type: icumsg.TokenTypeSimpleArg,
+	})
+	f(t, "{n, number} {d, date, short} {x, number, ::currency/EUR}",
+		icumsg.Argument{Name: "n", Index: 0, Type: icumsg.TokenTypeArgTypeNumber},
+		icumsg.Argument{
+			Name: "d", Index: 4,
+			Type:  icumsg.TokenTypeArgTypeDate,
+			Style: icumsg.TokenTypeArgStyleShort,
+		},
+		icumsg.Argument{
+			Name: "x", Index: 9,
+			Type:  icumsg.TokenTypeArgTypeNumber,
+			Style: icumsg.TokenTypeArgStyleSkeleton,
+		})
+	f(t, "{g, select, male{{n, plural, other{# {x}}}} other{{p, selectordinal, other{#}}}}",
+		icumsg.Argument{Name: "g", Index: 0, Type: icumsg.TokenTypeSelect},
+		icumsg.Argument{Name: "n", Index: 4, Type: icumsg.TokenTypePlural},
+		icumsg.Argument{Name: "x", Index: 8, Type: icumsg.TokenTypeSimpleArg},
+		icumsg.Argument{Name: "p", Index: 14, Type: icumsg.TokenTypeSelectOrdinal})
+
+	// Break.
+	input := "{a} {b} {c}"
+	buffer, err := tokenizer.Tokenize(language.English, nil, input)
+	test.RequireNoErr(t, err)
+	n := 0
+	for range icumsg.Arguments(input, buffer) {
+		n++
+		break
+	}
+	test.RequireEqual(t, 1, n)
+}
+
+func TestSimpleArgEnd(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+
+	f := func(t *testing.T, input string, index int, expect string) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(language.English, nil, input)
+		test.RequireNoErr(t, err)
+		end := icumsg.SimpleArgEnd(buffer, index)
+		test.RequireEqual(t, expect, buffer[end].String(input, buffer))
+	}
+
+	f(t, "{name}", 0, "name")
+	f(t, "Hello {name}!", 1, "name")
+	f(t, "{n, number} {x}", 0, "number")
+	f(t, "{d, date, short}!", 0, "short")
+	f(t, "{x, number, ::currency/EUR}", 0, "::currency/EUR")
+	f(t, "{g, select, other{{n, number, integer}}}", 3, "integer")
+	f(t, "{g, select, other{{n}}}", 3, "n")
+}
+
+func TestArgumentClass(t *testing.T) {
+	f := func(t *testing.T, expect icumsg.ArgumentClass, tp icumsg.TokenType) {
+		t.Helper()
+		test.RequireEqual(t, expect, icumsg.Argument{Type: tp}.Class())
+	}
+
+	f(t, icumsg.ArgumentClassAny, icumsg.TokenTypeSimpleArg)
+	f(t, icumsg.ArgumentClassNumber, icumsg.TokenTypeArgTypeNumber)
+	f(t, icumsg.ArgumentClassNumber, icumsg.TokenTypeArgTypeSpellout)
+	f(t, icumsg.ArgumentClassNumber, icumsg.TokenTypeArgTypeOrdinal)
+	f(t, icumsg.ArgumentClassNumber, icumsg.TokenTypeArgTypeDuration)
+	f(t, icumsg.ArgumentClassNumber, icumsg.TokenTypePlural)
+	f(t, icumsg.ArgumentClassNumber, icumsg.TokenTypeSelectOrdinal)
+	f(t, icumsg.ArgumentClassDate, icumsg.TokenTypeArgTypeDate)
+	f(t, icumsg.ArgumentClassDate, icumsg.TokenTypeArgTypeTime)
+	f(t, icumsg.ArgumentClassString, icumsg.TokenTypeSelect)
+
+	test.RequireEqual(t, "any", icumsg.ArgumentClassAny.String())
+	test.RequireEqual(t, "number", icumsg.ArgumentClassNumber.String())
+	test.RequireEqual(t, "date", icumsg.ArgumentClassDate.String())
+	test.RequireEqual(t, "string", icumsg.ArgumentClassString.String())
+}
@@ -0,0 +1,49 @@
+package icumsg
+
+import "slices"
+
+// AutoComplete returns a copy of the ICU message src tokenized into buffer
+// with all missing plural categories and missing select keys listed in report
+// inserted. The contents of each inserted option are cloned from option "other"
+// of the same choice and prefixed with marker (for example "TODO: ")
+// which is escaped as a literal. report must be produced by
+// NewCompletenessReport for the same src and buffer.
+// The returned message is printed in canonical form (see Format).
+func AutoComplete(
+	src string, buffer []Token, report CompletenessReport, marker string,
+) string {
+	missing := make(map[int][]string, len(report.Choices))
+	for _, c := range report.Choices {
+		if m := slices.Concat(c.MissingCategories, c.MissingKeys); len(m) > 0 {
+			missing[c.Index] = m
+		}
+	}
+	if len(missing) == 0 {
+		return Format(src, buffer)
+	}
+	marker = EscapeLiteral(marker)
+	p := printer{src: src, buffer: buffer}
+	p.afterOptions = func(index int) {
+		names := missing[index]
+		if len(names) == 0 {
+			return
+		}
+		other := -1
+		for j := range Options(buffer, index) {
+			if buffer[j].Type == TokenTypeOptionOther {
+				other = j
+				break
+			}
+		}
+		for _, name := range names {
+			p.b.WriteByte(' ')
+			p.b.WriteString(name)
+			p.b.WriteString(" {")
+			p.b.WriteString(marker)
+			p.printOptionContents(other)
+			p.b.WriteByte('}')
+		}
+	}
+	p.print(0, len(buffer))
+	return p.b.String()
+}
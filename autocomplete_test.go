@@ -0,0 +1,61 @@
+package icumsg_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestAutoComplete(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+
+	f := func(
+		t *testing.T, locale language.Tag, input string,
+		options map[string][]string, marker, expect string,
+	) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(locale, nil, input)
+		test.RequireNoErr(t, err)
+		selectOptions := func(argName string) (
+			[]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy,
+		) {
+			return options[argName], icumsg.OptionsPresencePolicyRequired, 0
+		}
+		report := icumsg.NewCompletenessReport(input, buffer, locale, nil, selectOptions)
+		actual := icumsg.AutoComplete(input, buffer, report, marker)
+		test.RequireEqual(t, expect, actual)
+
+		// The result must be valid and complete.
+		buffer, err = tokenizer.Tokenize(locale, nil, actual)
+		test.RequireNoErr(t, err)
+		report = icumsg.NewCompletenessReport(actual, buffer, locale, nil, selectOptions)
+		test.RequireEqual(t, 0, report.Incomplete)
+	}
+
+	f(t, language.English, "Complete", nil, "TODO: ", "Complete")
+	f(t, language.English, "{n, plural, one{# a} other{# b}}", nil, "TODO: ",
+		"{n, plural, one {# a} other {# b}}")
+	f(t, language.Polish,
+		"Masz {n, plural, one{# plik} other{# pliku}}.", nil, "TODO: ",
+		"Masz {n, plural, one {# plik} other {# pliku}"+
+			" few {TODO: # pliku} many {TODO: # pliku}}.")
+	f(t, language.Polish,
+		"{n, plural, =0{zero} other{# x}}", nil, "",
+		"{n, plural, =0 {zero} other {# x} one {# x} few {# x} many {# x}}")
+	f(t, language.English,
+		"{g, select, female{she} other{they}}",
+		map[string][]string{"g": {"male", "female"}}, "{TODO} ",
+		"{g, select, female {she} other {they} male {'{'TODO'}' they}}")
+	f(t, language.English,
+		"{p, selectordinal, other{#th}}", nil, "!",
+		"{p, selectordinal, other {#th} one {!#th} two {!#th} few {!#th}}")
+
+	// Nested incomplete choices are completed in the clones too.
+	f(t, language.Ukrainian,
+		"{g, select, other{{n, plural, one{# a} other{# b}}}}",
+		map[string][]string{"g": {"male"}}, "",
+		"{g, select, other {{n, plural, one {# a} other {# b} few {# b} many {# b}}}"+
+			" male {{n, plural, one {# a} other {# b} few {# b} many {# b}}}}")
+}
@@ -0,0 +1,240 @@
+package catalog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"unsafe"
+
+	"github.com/romshark/icumsg"
+	"golang.org/x/text/language"
+)
+
+// BinaryVersion is the version of the binary catalog encoding.
+const BinaryVersion = 1
+
+// binaryMagic starts every binary catalog.
+const binaryMagic = "ICUC"
+
+var (
+	// ErrBinaryFormat is returned by DecodeBinary for data that isn't
+	// a binary catalog or is corrupt.
+	ErrBinaryFormat = errors.New("invalid binary catalog")
+
+	// ErrBinaryVersion is returned by DecodeBinary for binary catalogs
+	// of an encoding version other than BinaryVersion.
+	ErrBinaryVersion = errors.New("unsupported binary catalog version")
+
+	// ErrCLDRVersion is returned by DecodeBinary for binary catalogs
+	// encoded with plural rules of a CLDR version other than
+	// icumsg.CLDRVersion, regardless of the plural rule provider.
+	ErrCLDRVersion = errors.New("CLDR version mismatch")
+)
+
+// AppendBinary implements encoding.BinaryAppender. It appends the
+// binary encoding of the catalog to b for loading it with DecodeBinary
+// without tokenizing the messages again. The encoding is stable for
+// equal catalogs. An error is returned if any message is invalid.
+//
+// The encoding starts with "ICUC", the encoding version as uint16
+// little endian and the CLDR version, followed by the source locale and
+// the locales sorted by their string form. Each locale holds the
+// number of messages and tokens, followed by the messages sorted by ID
+// with their tokens. Strings are prefixed by their length, integers are
+// unsigned varints.
+func (c *Catalog) AppendBinary(b []byte) ([]byte, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	b = append(b, binaryMagic...)
+	b = binary.LittleEndian.AppendUint16(b, BinaryVersion)
+	b = appendString(b, icumsg.CLDRVersion)
+	b = appendString(b, c.source.String())
+
+	locales := slices.SortedFunc(maps.Keys(c.locales), func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	b = binary.AppendUvarint(b, uint64(len(locales)))
+	for _, loc := range locales {
+		l := c.locales[loc]
+		b = appendString(b, loc.String())
+		b = binary.AppendUvarint(b, uint64(len(l.entries)))
+		var tokens int
+		for _, e := range l.entries {
+			tokens += len(e.Tokens)
+		}
+		b = binary.AppendUvarint(b, uint64(tokens))
+		for _, id := range slices.Sorted(maps.Keys(l.entries)) {
+			e := l.entries[id]
+			if e.Err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", loc, id, e.Err)
+			}
+			b = appendString(b, id)
+			b = appendString(b, e.Message)
+			b = binary.AppendUvarint(b, uint64(len(e.Tokens)))
+			for _, t := range e.Tokens {
+				b = append(b, byte(t.Type))
+				b = binary.AppendUvarint(b, uint64(t.IndexStart))
+				b = binary.AppendUvarint(b, uint64(t.IndexEnd))
+			}
+		}
+	}
+	return b, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. See AppendBinary.
+func (c *Catalog) MarshalBinary() ([]byte, error) { return c.AppendBinary(nil) }
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// DecodeBinary creates a catalog from data encoded by AppendBinary.
+// Message IDs and messages aren't copied and refer to data, which must
+// not be modified afterwards, such as data embedded with go:embed.
+// The tokens of all messages are checked with icumsg.ValidateBuffer.
+// The plural rule provider plurals is used for messages added later,
+// see New.
+//
+// Binary catalogs are tied to the built-in plural rules: data encoded
+// with another icumsg.CLDRVersion is rejected with ErrCLDRVersion
+// whatever provider plurals is, including providers that don't use
+// icumsg.CLDRPluralRules, since the provider the messages were
+// tokenized with isn't recorded. Encode the catalog again after
+// updating icumsg.
+func DecodeBinary(data []byte, plurals icumsg.PluralRuleProvider) (*Catalog, error) {
+	d := decoder{data: data}
+	if d.string(len(binaryMagic)) != binaryMagic {
+		return nil, ErrBinaryFormat
+	}
+	if v := d.uint16(); d.err == nil && v != BinaryVersion {
+		return nil, fmt.Errorf("%w: %d", ErrBinaryVersion, v)
+	}
+	if v := d.string(d.length()); d.err == nil && v != icumsg.CLDRVersion {
+		return nil, fmt.Errorf("%w: %q, want %q", ErrCLDRVersion, v, icumsg.CLDRVersion)
+	}
+	c := New(d.tag(), plurals)
+
+	for range d.length() {
+		loc := d.tag()
+		n := d.length()
+		tokens := d.length()
+		if d.err != nil {
+			break
+		}
+		l := &locale{
+			entries: make(map[string]*Entry, n),
+			buffer:  make([]icumsg.Token, 0, tokens),
+		}
+		c.locales[loc] = l
+		for range n {
+			e := &Entry{Locale: loc, buffered: true}
+			e.ID = d.string(d.length())
+			e.Message = d.string(d.length())
+			start := len(l.buffer)
+			for range d.length() {
+				l.buffer = append(l.buffer, icumsg.Token{
+					Type:       icumsg.TokenType(d.byte()),
+					IndexStart: d.int(),
+					IndexEnd:   d.int(),
+				})
+			}
+			if d.err != nil {
+				break
+			}
+			e.Tokens = l.buffer[start:len(l.buffer):len(l.buffer)]
+			if err := icumsg.ValidateBuffer(e.Message, e.Tokens); err != nil {
+				return nil, fmt.Errorf("%w: %s: %s: %w: %w",
+					ErrBinaryFormat, loc, e.ID, ErrInvalidTokens, err)
+			}
+			l.set(e)
+		}
+	}
+	if d.err == nil && len(d.data) > 0 {
+		d.err = ErrBinaryFormat
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return c, nil
+}
+
+// decoder reads binary catalogs. Reads after an error return zero values.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail() { d.err, d.data = ErrBinaryFormat, nil }
+
+func (d *decoder) byte() byte {
+	if len(d.data) < 1 {
+		d.fail()
+		return 0
+	}
+	v := d.data[0]
+	d.data = d.data[1:]
+	return v
+}
+
+func (d *decoder) uint16() uint16 {
+	if len(d.data) < 2 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint16(d.data)
+	d.data = d.data[2:]
+	return v
+}
+
+func (d *decoder) int() int {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 || v > math.MaxInt32 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return int(v)
+}
+
+// length reads a length. Every element takes at least a byte,
+// so lengths exceeding the remaining data are invalid.
+func (d *decoder) length() int {
+	v := d.int()
+	if v > len(d.data) {
+		d.fail()
+		return 0
+	}
+	return v
+}
+
+// string returns the next n bytes as a string referring to data.
+func (d *decoder) string(n int) string {
+	if n > len(d.data) {
+		d.fail()
+		return ""
+	}
+	if n == 0 {
+		return ""
+	}
+	s := unsafe.String(&d.data[0], n)
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *decoder) tag() language.Tag {
+	s := d.string(d.length())
+	if d.err != nil {
+		return language.Und
+	}
+	t, err := language.Parse(s)
+	if err != nil {
+		d.err, d.data = fmt.Errorf("%w: %w", ErrBinaryFormat, err), nil
+	}
+	return t
+}
@@ -0,0 +1,130 @@
+package catalog_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func newBinaryCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "greeting", "Hello {name}!"))
+	test.RequireNoErr(t, c.Add(en, "files", "{n, plural, one {# file} other {# files}}"))
+	test.RequireNoErr(t, c.Add(en, "empty", ""))
+	test.RequireNoErr(t, c.Add(de, "greeting", "Hallo {name}!"))
+	test.RequireNoErr(t, c.Add(de, "files",
+		"{g, select, other {{n, plural, one {# Datei} other {# Dateien}}}}"))
+	test.RequireNoErr(t, c.Add(deAT, "greeting", "Servus {name}!"))
+	return c
+}
+
+func TestBinary(t *testing.T) {
+	c := newBinaryCatalog(t)
+	data, err := c.MarshalBinary()
+	test.RequireNoErr(t, err)
+
+	// The encoding is stable.
+	data2, err := newBinaryCatalog(t).AppendBinary([]byte("prefix"))
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, data, data2[len("prefix"):])
+
+	d, err := catalog.DecodeBinary(data, nil)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, en, d.Source())
+	test.RequireDeepEqual(t, c.Locales(), d.Locales())
+	for _, loc := range c.Locales() {
+		test.RequireDeepEqual(t, c.IDs(loc), d.IDs(loc))
+		for _, id := range c.IDs(loc) {
+			test.RequireDeepEqual(t, c.Entry(loc, id), d.Entry(loc, id))
+			e := d.Entry(loc, id)
+			test.RequireEqual(t, len(e.Tokens), cap(e.Tokens))
+		}
+	}
+	e, ok := d.Lookup(deAT, "files")
+	test.RequireEqual(t, true, ok)
+	test.RequireEqual(t, de, e.Locale)
+
+	// Messages can be added after decoding.
+	test.RequireNoErr(t, d.Add(en, "new", "{n, plural, other {#}}"))
+	test.RequireEqual(t, icumsg.TokenTypePlural, d.Entry(en, "new").Tokens[0].Type)
+}
+
+func TestBinaryInvalidMessage(t *testing.T) {
+	c := catalog.New(en, nil)
+	_ = c.Add(en, "broken", "{n, plural, one {# file}}")
+	_, err := c.MarshalBinary()
+	test.RequireErrIs(t, icumsg.ErrMissingOptionOther, err)
+}
+
+func TestDecodeBinaryErr(t *testing.T) {
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "files", "{n, plural, one {# file} other {# files}}"))
+	data, err := c.MarshalBinary()
+	test.RequireNoErr(t, err)
+
+	f := func(t *testing.T, expect error, data []byte) {
+		t.Helper()
+		_, err := catalog.DecodeBinary(data, nil)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, catalog.ErrBinaryFormat, nil)
+	f(t, catalog.ErrBinaryFormat, []byte("ICU"))
+	f(t, catalog.ErrBinaryFormat, []byte("{\"@@locale\": \"en\"}"))
+	for i := range data {
+		f(t, catalog.ErrBinaryFormat, data[:i]) // Truncated.
+	}
+	f(t, catalog.ErrBinaryFormat, append(bytes.Clone(data), 0)) // Trailing data.
+
+	version := bytes.Clone(data)
+	binary.LittleEndian.PutUint16(version[4:], catalog.BinaryVersion+1)
+	f(t, catalog.ErrBinaryVersion, version)
+
+	cldr := bytes.Clone(data)
+	cldr[7] = 'X' // First byte of the CLDR version following its length.
+	f(t, catalog.ErrCLDRVersion, cldr)
+	// Custom plural rule providers don't skip the check.
+	_, err = catalog.DecodeBinary(cldr, icumsg.NewPluralRuleRegistry(nil))
+	test.RequireErrIs(t, catalog.ErrCLDRVersion, err)
+
+	// Corrupt the link from the plural token to its terminator
+	// keeping the encoding valid.
+	tokens := bytes.Clone(data)
+	plural := bytes.Index(tokens, []byte{byte(icumsg.TokenTypePlural), 0})
+	test.RequireEqual(t, true, plural > 0)
+	tokens[plural+2]--
+	f(t, catalog.ErrBinaryFormat, tokens)
+
+	types := bytes.Clone(data)
+	types[plural] = 0xff
+	f(t, catalog.ErrBinaryFormat, types)
+
+	locale := bytes.Clone(data)
+	locale[bytes.Index(locale, []byte("\x02en"))+1] = '!'
+	f(t, catalog.ErrBinaryFormat, locale)
+}
+
+func BenchmarkDecodeBinary(b *testing.B) {
+	c := catalog.New(en, nil)
+	for i := range 1000 {
+		for _, loc := range []language.Tag{en, de} {
+			_ = c.Add(loc, string(rune('a'+i%26))+string(rune('a'+i/26)),
+				"{gender, select, female {{n, plural, one {She has # message} "+
+					"other {She has # messages}}} other {{n, plural, one {# message} "+
+					"other {# messages}}}}")
+		}
+	}
+	data, err := c.MarshalBinary()
+	test.RequireNoErr(b, err)
+	for b.Loop() {
+		if _, err := catalog.DecodeBinary(data, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+// Package catalog manages ICU messages across locales.
+package catalog
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/msgfile"
+	"golang.org/x/text/language"
+)
+
+// Entry is a message of a locale.
+// Entries are immutable and safe for concurrent use.
+type Entry struct {
+	ID     string
+	Locale language.Tag
+
+	// Message is the ICU message.
+	Message string
+
+	// Tokens are the tokens of Message and must not be modified.
+	// Tokens is nil if Err != nil.
+	Tokens []icumsg.Token
+
+	// Err is the tokenizer error if Message is invalid.
+	Err error
+
+	// buffered is true if Tokens are part of the buffer of the locale.
+	buffered bool
+}
+
+// Catalog is a set of messages across locales.
+// Tokens of all messages of a locale are stored in a shared buffer,
+// which is compacted once most of it belongs to replaced messages.
+// Catalog is safe for concurrent use.
+type Catalog struct {
+	source language.Tag
+
+	lock      sync.RWMutex
+	tokenizer icumsg.Tokenizer
+	locales   map[language.Tag]*locale
+}
+
+type locale struct {
+	entries map[string]*Entry
+	buffer  []icumsg.Token
+
+	// garbage is the number of tokens in buffer of replaced entries.
+	garbage int
+}
+
+// set adds e replacing any existing entry with the same ID.
+func (l *locale) set(e *Entry) {
+	if old := l.entries[e.ID]; old != nil && old.buffered {
+		l.garbage += len(old.Tokens)
+	}
+	l.entries[e.ID] = e
+	if l.garbage > 0 && l.garbage*2 >= len(l.buffer) {
+		l.compact()
+	}
+}
+
+// compact copies the tokens of all buffered entries to a new buffer
+// dropping the tokens of replaced entries. Entries are immutable,
+// so buffered entries are replaced by copies referring to the new buffer.
+func (l *locale) compact() {
+	buffer := make([]icumsg.Token, 0, len(l.buffer)-l.garbage)
+	for id, e := range l.entries {
+		if !e.buffered {
+			continue
+		}
+		start := len(buffer)
+		buffer = append(buffer, e.Tokens...)
+		c := *e
+		c.Tokens = buffer[start:len(buffer):len(buffer)]
+		l.entries[id] = &c
+	}
+	l.buffer, l.garbage = buffer, 0
+}
+
+// New creates a new empty catalog. source is the locale messages are
+// authored in and the locale Lookup falls back to.
+// plurals provides the plural rules used for tokenization,
+// icumsg.CLDRPluralRules is used if nil.
+func New(source language.Tag, plurals icumsg.PluralRuleProvider) *Catalog {
+	return &Catalog{
+		source:    source,
+		tokenizer: icumsg.Tokenizer{Plurals: plurals},
+		locales:   map[language.Tag]*locale{},
+	}
+}
+
+// Source returns the source locale.
+func (c *Catalog) Source() language.Tag { return c.source }
+
+// Add tokenizes message and adds it to the catalog replacing any existing
+// message with the same locale and ID. Invalid messages are added too
+// and reported by Validate, in which case the tokenizer error is returned.
+func (c *Catalog) Add(loc language.Tag, id, message string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.add(loc, id, message)
+}
+
+func (c *Catalog) add(loc language.Tag, id, message string) error {
+	l := c.locales[loc]
+	if l == nil {
+		l = &locale{entries: map[string]*Entry{}}
+		c.locales[loc] = l
+	}
+	e := &Entry{ID: id, Locale: loc, Message: message}
+	start := len(l.buffer)
+	var err error
+	l.buffer, err = c.tokenizer.Tokenize(loc, l.buffer, message)
+	if err != nil {
+		l.buffer = l.buffer[:start]
+		e.Err = err
+	} else {
+		// Limit the capacity to protect the shared buffer from appends.
+		e.Tokens = l.buffer[start:len(l.buffer):len(l.buffer)]
+		e.buffered = true
+		// Complex tokens and terminators refer to buffer indexes,
+		// rebase them to make the tokens of the entry self-contained.
+		for i := range e.Tokens {
+			switch t := &e.Tokens[i]; {
+			case t.Type > icumsg.TokenTypeOptionNumber: // Terminators.
+				t.IndexStart -= start
+			case t.Type >= icumsg.TokenTypePlural: // Complex tokens.
+				t.IndexEnd -= start
+			}
+		}
+	}
+	l.set(e)
+	return e.Err
+}
+
+// ErrInvalidTokens is returned by AddTokens for tokens that aren't
+// a well-formed token buffer of the message, see icumsg.ValidateBuffer.
+var ErrInvalidTokens = errors.New("inconsistent tokens")
+
+// AddTokens adds message tokenized into tokens, replacing any existing
+// message with the same locale and ID, such as messages tokenized at
+// build time. tokens must be self-contained like Entry.Tokens and are
+// referenced, not copied. The tokens are checked with
+// icumsg.ValidateBuffer, the message isn't tokenized again.
+func (c *Catalog) AddTokens(loc language.Tag, id, message string, tokens []icumsg.Token) error {
+	if err := icumsg.ValidateBuffer(message, tokens); err != nil {
+		return fmt.Errorf("%s: %s: %w: %w", loc, id, ErrInvalidTokens, err)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	l := c.locales[loc]
+	if l == nil {
+		l = &locale{entries: map[string]*Entry{}}
+		c.locales[loc] = l
+	}
+	l.set(&Entry{
+		ID: id, Locale: loc, Message: message,
+		Tokens: tokens[:len(tokens):len(tokens)],
+	})
+	return nil
+}
+
+// AddFile adds all messages of a JSON catalog (.json or .arb).
+// Nested keys are joined by "." and the locale is determined from
+// the "@@locale" key or the file name unless loc is specified.
+// Invalid messages are added too and reported by Validate.
+func (c *Catalog) AddFile(name string, data []byte, loc language.Tag) error {
+	if !msgfile.IsCatalog(name) {
+		return fmt.Errorf("%s: %w", name, ErrUnsupportedFile)
+	}
+	f, err := msgfile.Parse(name, data, loc)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, m := range f.Messages {
+		_ = c.add(f.Locale, m.ID, m.Text) // Reported by Validate.
+	}
+	return nil
+}
+
+var ErrUnsupportedFile = errors.New("unsupported file type")
+
+// LoadFS adds all JSON catalogs (.json and .arb) in fsys.
+func (c *Catalog) LoadFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !msgfile.IsCatalog(p) {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return c.AddFile(path.Base(p), data, language.Und)
+	})
+}
+
+// Locales returns all locales of the catalog sorted by their string form.
+func (c *Catalog) Locales() []language.Tag {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return slices.SortedFunc(maps.Keys(c.locales), func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	})
+}
+
+// IDs returns the sorted IDs of all messages of the locale.
+func (c *Catalog) IDs(loc language.Tag) []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	l := c.locales[loc]
+	if l == nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(l.entries))
+}
+
+// Entry returns the message of exactly the locale or nil if there's none.
+func (c *Catalog) Entry(loc language.Tag, id string) *Entry {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.entry(loc, id)
+}
+
+func (c *Catalog) entry(loc language.Tag, id string) *Entry {
+	if l := c.locales[loc]; l != nil {
+		return l.entries[id]
+	}
+	return nil
+}
+
+// Fallbacks returns the locales Lookup tries in order:
+// the locale, its parents and finally the source locale.
+// For example: de-AT, de, en.
+func (c *Catalog) Fallbacks(loc language.Tag) []language.Tag {
+	var chain []language.Tag
+	for t := loc; t != language.Und; t = t.Parent() {
+		chain = append(chain, t)
+	}
+	if !slices.Contains(chain, c.source) {
+		chain = append(chain, c.source)
+	}
+	return chain
+}
+
+// Lookup returns the first valid message found for the locales
+// provided by Fallbacks and false if there's none.
+func (c *Catalog) Lookup(loc language.Tag, id string) (*Entry, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, t := range c.Fallbacks(loc) {
+		if e := c.entry(t, id); e != nil && e.Err == nil {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+var (
+	ErrMissingTranslation = errors.New("missing translation")
+	ErrUntracked          = errors.New("message not found in source locale")
+	ErrArgumentUnknown    = errors.New("argument not found in source message")
+	ErrArgumentMissing    = errors.New("argument of source message missing")
+	ErrArgumentType       = errors.New("argument type differs from source message")
+)
+
+// Issue is a problem with a message.
+type Issue struct {
+	Locale language.Tag
+	ID     string
+
+	// Arg is the name of the argument for argument related issues.
+	Arg string
+
+	// Err is either the tokenizer error for invalid messages or
+	// one of ErrMissingTranslation, ErrUntracked, ErrArgumentUnknown,
+	// ErrArgumentMissing and ErrArgumentType.
+	Err error
+}
+
+func (i Issue) Error() string {
+	if i.Arg != "" {
+		return fmt.Sprintf("%s: %s: %v: %q", i.Locale, i.ID, i.Err, i.Arg)
+	}
+	return fmt.Sprintf("%s: %s: %v", i.Locale, i.ID, i.Err)
+}
+
+func (i Issue) Unwrap() error { return i.Err }
+
+func compareIssues(a, b Issue) int {
+	return cmp.Or(
+		strings.Compare(a.Locale.String(), b.Locale.String()),
+		strings.Compare(a.ID, b.ID),
+		strings.Compare(a.Arg, b.Arg),
+	)
+}
+
+// Validate returns an issue for every invalid message sorted by locale and ID.
+func (c *Catalog) Validate() []Issue {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var issues []Issue
+	for _, l := range c.locales {
+		for _, e := range l.entries {
+			if e.Err != nil {
+				issues = append(issues, Issue{Locale: e.Locale, ID: e.ID, Err: e.Err})
+			}
+		}
+	}
+	slices.SortFunc(issues, compareIssues)
+	return issues
+}
+
+// Check checks all locales for consistency with the source locale and
+// returns the issues found sorted by locale, ID and argument:
+//
+//   - ErrMissingTranslation: a source message has no message in the locale.
+//   - ErrUntracked: a message has no source message.
+//   - ErrArgumentUnknown: a message uses an argument the source message doesn't.
+//   - ErrArgumentMissing: a message lacks an argument of the source message.
+//   - ErrArgumentType: an argument accepts a different kind of value
+//     (number, date or string) than in the source message.
+//
+// Invalid messages are reported by Validate and skipped.
+func (c *Catalog) Check() []Issue {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var issues []Issue
+	src := c.locales[c.source]
+	if src == nil {
+		src = &locale{}
+	}
+	for loc, l := range c.locales {
+		if loc == c.source {
+			continue
+		}
+		for id := range src.entries {
+			if _, ok := l.entries[id]; !ok {
+				issues = append(issues, Issue{Locale: loc, ID: id, Err: ErrMissingTranslation})
+			}
+		}
+		for id, e := range l.entries {
+			s, ok := src.entries[id]
+			if !ok {
+				issues = append(issues, Issue{Locale: loc, ID: id, Err: ErrUntracked})
+				continue
+			}
+			if e.Err != nil || s.Err != nil {
+				continue
+			}
+			issues = checkArguments(issues, s, e)
+		}
+	}
+	slices.SortFunc(issues, compareIssues)
+	return issues
+}
+
+// checkArguments compares the arguments of translation e with source s.
+func checkArguments(issues []Issue, s, e *Entry) []Issue {
+	srcArgs := arguments(s)
+	args := arguments(e)
+	for name, class := range args {
+		srcClass, ok := srcArgs[name]
+		switch {
+		case !ok:
+			issues = append(issues, Issue{
+				Locale: e.Locale, ID: e.ID, Arg: name, Err: ErrArgumentUnknown,
+			})
+		case class != icumsg.ArgumentClassAny && srcClass != icumsg.ArgumentClassAny &&
+			class != srcClass:
+			issues = append(issues, Issue{
+				Locale: e.Locale, ID: e.ID, Arg: name, Err: ErrArgumentType,
+			})
+		}
+	}
+	for name := range srcArgs {
+		if _, ok := args[name]; !ok {
+			issues = append(issues, Issue{
+				Locale: e.Locale, ID: e.ID, Arg: name, Err: ErrArgumentMissing,
+			})
+		}
+	}
+	return issues
+}
+
+// arguments maps argument names of e to the class of values they accept.
+func arguments(e *Entry) map[string]icumsg.ArgumentClass {
+	args := map[string]icumsg.ArgumentClass{}
+	for a := range icumsg.Arguments(e.Message, e.Tokens) {
+		if _, ok := args[a.Name]; !ok || a.Class() != icumsg.ArgumentClassAny {
+			args[a.Name] = a.Class()
+		}
+	}
+	return args
+}
@@ -0,0 +1,220 @@
+package catalog_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+var (
+	de   = language.German
+	deAT = language.MustParse("de-AT")
+	en   = language.English
+)
+
+func TestAdd(t *testing.T) {
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "greeting", "Hello {name}!"))
+	test.RequireNoErr(t, c.Add(en, "files", "{n, plural, one {# file} other {# files}}"))
+	err := c.Add(en, "broken", "{n, plural, one {# file}}")
+	test.RequireErrIs(t, icumsg.ErrMissingOptionOther, err)
+
+	e := c.Entry(en, "greeting")
+	test.RequireEqual(t, "Hello {name}!", e.Message)
+	test.RequireEqual(t, 4, len(e.Tokens))
+	test.RequireEqual(t, "name", e.Tokens[2].String(e.Message, e.Tokens))
+	// Tokens are a capped window into the shared buffer.
+	test.RequireEqual(t, len(e.Tokens), cap(e.Tokens))
+
+	e = c.Entry(en, "files")
+	test.RequireEqual(t, icumsg.TokenTypePlural, e.Tokens[0].Type)
+	test.RequireEqual(t, "{n, plural, one {# file} other {# files}}",
+		e.Tokens[0].String(e.Message, e.Tokens))
+
+	e = c.Entry(en, "broken")
+	test.RequireErrIs(t, icumsg.ErrMissingOptionOther, e.Err)
+	test.RequireEqual(t, 0, len(e.Tokens))
+
+	test.RequireEqual(t, (*catalog.Entry)(nil), c.Entry(de, "greeting"))
+	test.RequireDeepEqual(t, []string{"broken", "files", "greeting"}, c.IDs(en))
+	test.RequireDeepEqual(t, []string(nil), c.IDs(de))
+}
+
+func TestAddReplace(t *testing.T) {
+	const msg = "{n, plural, one {# file} other {# files}}"
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "greeting", "Hello {name}!"))
+	held := c.Entry(en, "greeting")
+	for i := range 1000 {
+		test.RequireNoErr(t, c.Add(en, "files", fmt.Sprintf("%d %s", i, msg)))
+	}
+	e := c.Entry(en, "files")
+	// Tokens of replaced messages are dropped from the buffer.
+	live := len(e.Tokens) + len(held.Tokens)
+	if n := catalog.BufferLen(c, en); n > 2*live {
+		t.Fatalf("buffer length %d exceeds twice the %d live tokens", n, live)
+	}
+	test.RequireEqual(t, "999 "+msg, e.Message)
+	test.RequireEqual(t, msg, e.Tokens[1].String(e.Message, e.Tokens))
+
+	// Entries are immutable and remain valid after compaction.
+	test.RequireEqual(t, "name", held.Tokens[2].String(held.Message, held.Tokens))
+	e = c.Entry(en, "greeting")
+	test.RequireEqual(t, "name", e.Tokens[2].String(e.Message, e.Tokens))
+}
+
+func TestAddTokens(t *testing.T) {
+	src := "{n, plural, one {# file} other {# files}}"
+	var tokenizer icumsg.Tokenizer
+	tokens, err := tokenizer.Tokenize(en, nil, src)
+	test.RequireNoErr(t, err)
+
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.AddTokens(en, "files", src, tokens))
+	e, ok := c.Lookup(de, "files")
+	test.RequireEqual(t, true, ok)
+	test.RequireEqual(t, src, e.Message)
+	test.RequireEqual(t, &tokens[0], &e.Tokens[0]) // Not copied.
+	test.RequireEqual(t, len(e.Tokens), cap(e.Tokens))
+
+	f := func(t *testing.T, src string, tokens ...icumsg.Token) {
+		t.Helper()
+		err := c.AddTokens(en, "invalid", src, tokens)
+		test.RequireErrIs(t, catalog.ErrInvalidTokens, err)
+		test.RequireEqual(t, (*catalog.Entry)(nil), c.Entry(en, "invalid"))
+	}
+	lit := icumsg.TokenTypeLiteral
+	f(t, "abc", icumsg.Token{Type: lit, IndexStart: 0, IndexEnd: 4})
+	f(t, "abc", icumsg.Token{Type: lit, IndexStart: 2, IndexEnd: 1})
+	f(t, "abc", icumsg.Token{Type: 0, IndexStart: 0, IndexEnd: 1})
+	f(t, "{a, select, other {x}}",
+		icumsg.Token{Type: icumsg.TokenTypeSelect, IndexEnd: 5})
+	f(t, "{a, select, other {x}}",
+		icumsg.Token{Type: icumsg.TokenTypeComplexArgTerm, IndexStart: 0})
+}
+
+func TestLookup(t *testing.T) {
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "a", "A"))
+	test.RequireNoErr(t, c.Add(en, "b", "B"))
+	test.RequireNoErr(t, c.Add(en, "c", "C"))
+	test.RequireNoErr(t, c.Add(de, "a", "A de"))
+	test.RequireNoErr(t, c.Add(de, "b", "B de"))
+	test.RequireNoErr(t, c.Add(deAT, "a", "A de-AT"))
+	_ = c.Add(deAT, "b", "{invalid")
+
+	f := func(t *testing.T, loc language.Tag, id, expect string) {
+		t.Helper()
+		e, ok := c.Lookup(loc, id)
+		if expect == "" {
+			test.RequireEqual(t, false, ok)
+			return
+		}
+		test.RequireEqual(t, true, ok)
+		test.RequireEqual(t, expect, e.Message)
+	}
+
+	f(t, deAT, "a", "A de-AT")
+	f(t, deAT, "b", "B de") // Invalid messages are skipped.
+	f(t, deAT, "c", "C")
+	f(t, de, "a", "A de")
+	f(t, language.French, "a", "A")
+	f(t, deAT, "x", "")
+
+	test.RequireDeepEqual(t, []language.Tag{deAT, de, en}, c.Fallbacks(deAT))
+	test.RequireDeepEqual(t, []language.Tag{en}, c.Fallbacks(en))
+	test.RequireDeepEqual(t, []language.Tag{language.AmericanEnglish, en},
+		c.Fallbacks(language.AmericanEnglish))
+}
+
+func TestLoadFS(t *testing.T) {
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.LoadFS(fstest.MapFS{
+		"locales/en.json":      {Data: []byte(`{"nav": {"home": "Home"}, "x": "{"}`)},
+		"locales/app_de.arb":   {Data: []byte(`{"@@locale": "de", "nav.home": "Start"}`)},
+		"locales/README.md":    {Data: []byte(`# Locales`)},
+		"locales/unknown.json": {Data: []byte(`{"@@locale": "fr", "nav.home": "Accueil"}`)},
+	}))
+	test.RequireDeepEqual(t, []language.Tag{de, en, language.French}, c.Locales())
+	e, ok := c.Lookup(deAT, "nav.home")
+	test.RequireEqual(t, true, ok)
+	test.RequireEqual(t, "Start", e.Message)
+
+	test.RequireEqual(t, 1, len(c.Validate()))
+	test.RequireErrIs(t, icumsg.ErrUnexpectedEOF, c.Validate()[0])
+	test.RequireEqual(t, "en: x: unexpected EOF", c.Validate()[0].Error())
+
+	err := c.AddFile("welcome.en.icu", []byte("Welcome"), language.Und)
+	test.RequireErrIs(t, catalog.ErrUnsupportedFile, err)
+}
+
+func TestCheck(t *testing.T) {
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "greeting", "Hello {name}!"))
+	test.RequireNoErr(t, c.Add(en, "files", "{n, plural, one {# file} other {# files}}"))
+	test.RequireNoErr(t, c.Add(en, "date", "Due {due, date}"))
+	test.RequireNoErr(t, c.Add(en, "broken", "{x}"))
+
+	test.RequireNoErr(t, c.Add(de, "greeting", "Hallo {nme}!"))
+	test.RequireNoErr(t, c.Add(de, "files", "{n, plural, one {# Datei} other {# Dateien}}"))
+	test.RequireNoErr(t, c.Add(de, "date", "Fällig {due, number}"))
+	test.RequireNoErr(t, c.Add(de, "extra", "Extra"))
+	_ = c.Add(de, "broken", "{") // Reported by Validate only.
+
+	test.RequireNoErr(t, c.Add(deAT, "greeting", "Servus {name}, {name, select, other {du}}"))
+
+	var actual []string
+	for _, issue := range c.Check() {
+		actual = append(actual, issue.Error())
+	}
+	test.RequireDeepEqual(t, []string{
+		`de: date: argument type differs from source message: "due"`,
+		`de: extra: message not found in source locale`,
+		`de: greeting: argument of source message missing: "name"`,
+		`de: greeting: argument not found in source message: "nme"`,
+		`de-AT: broken: missing translation`,
+		`de-AT: date: missing translation`,
+		`de-AT: files: missing translation`,
+	}, actual)
+	test.RequireErrIs(t, catalog.ErrArgumentType, c.Check()[0])
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "greeting", "Hello {name}!"))
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				_ = c.Add(de, fmt.Sprintf("m%d_%d", i, j), "{n, plural, one {#} other {#}}")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				e, ok := c.Lookup(deAT, "greeting")
+				if !ok || e.Tokens[2].String(e.Message, e.Tokens) != "name" {
+					t.Error("unexpected lookup result")
+					return
+				}
+				_ = c.Check()
+			}
+		}()
+	}
+	wg.Wait()
+	test.RequireEqual(t, 8*100, len(c.IDs(de)))
+	for _, id := range c.IDs(de) {
+		e := c.Entry(de, id)
+		test.RequireEqual(t, icumsg.TokenTypePlural, e.Tokens[0].Type)
+		test.RequireEqual(t, len(e.Tokens), e.Tokens[0].IndexEnd+1)
+	}
+}
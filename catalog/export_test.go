@@ -0,0 +1,10 @@
+package catalog
+
+import "golang.org/x/text/language"
+
+// BufferLen returns the length of the token buffer of loc.
+func BufferLen(c *Catalog, loc language.Tag) int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.locales[loc].buffer)
+}
@@ -0,0 +1,209 @@
+package cldr
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/cldr"
+	"golang.org/x/text/language"
+)
+
+// Category is a CLDR plural category.
+type Category uint8
+
+const (
+	_ Category = iota
+
+	CategoryZero
+	CategoryOne
+	CategoryTwo
+	CategoryFew
+	CategoryMany
+	CategoryOther
+)
+
+// ErrUnknownCategory is returned by ParseCategory for unknown category names.
+var ErrUnknownCategory = errors.New("unknown plural category")
+
+func (c Category) String() string {
+	switch c {
+	case CategoryZero:
+		return "zero"
+	case CategoryOne:
+		return "one"
+	case CategoryTwo:
+		return "two"
+	case CategoryFew:
+		return "few"
+	case CategoryMany:
+		return "many"
+	case CategoryOther:
+		return "other"
+	}
+	return "unknown"
+}
+
+// ParseCategory parses a CLDR plural category name such as "few".
+func ParseCategory(s string) (Category, error) {
+	switch s {
+	case "zero":
+		return CategoryZero, nil
+	case "one":
+		return CategoryOne, nil
+	case "two":
+		return CategoryTwo, nil
+	case "few":
+		return CategoryFew, nil
+	case "many":
+		return CategoryMany, nil
+	case "other":
+		return CategoryOther, nil
+	}
+	return 0, ErrUnknownCategory
+}
+
+// TokenType returns the option token type of c,
+// for example icumsg.TokenTypeOptionFew for CategoryFew.
+// Returns 0 if c is invalid.
+func (c Category) TokenType() icumsg.TokenType {
+	switch c {
+	case CategoryZero:
+		return icumsg.TokenTypeOptionZero
+	case CategoryOne:
+		return icumsg.TokenTypeOptionOne
+	case CategoryTwo:
+		return icumsg.TokenTypeOptionTwo
+	case CategoryFew:
+		return icumsg.TokenTypeOptionFew
+	case CategoryMany:
+		return icumsg.TokenTypeOptionMany
+	case CategoryOther:
+		return icumsg.TokenTypeOptionOther
+	}
+	return 0
+}
+
+// CategoryOf returns the category of option token type t.
+// Returns false if t is neither of icumsg.TokenTypeOptionZero
+// to icumsg.TokenTypeOptionOther.
+func CategoryOf(t icumsg.TokenType) (Category, bool) {
+	switch t {
+	case icumsg.TokenTypeOptionZero:
+		return CategoryZero, true
+	case icumsg.TokenTypeOptionOne:
+		return CategoryOne, true
+	case icumsg.TokenTypeOptionTwo:
+		return CategoryTwo, true
+	case icumsg.TokenTypeOptionFew:
+		return CategoryFew, true
+	case icumsg.TokenTypeOptionMany:
+		return CategoryMany, true
+	case icumsg.TokenTypeOptionOther:
+		return CategoryOther, true
+	}
+	return 0, false
+}
+
+// Categories is a set of CLDR plural categories.
+type Categories uint8
+
+// NewCategories creates a set of the given categories.
+func NewCategories(c ...Category) Categories {
+	var s Categories
+	for _, c := range c {
+		s = s.With(c)
+	}
+	return s
+}
+
+// CategoriesOf returns the set of categories supported by r.
+func CategoriesOf(r PluralRules) Categories {
+	var s Categories
+	for i, ok := range [...]bool{r.Zero, r.One, r.Two, r.Few, r.Many, r.Other} {
+		if ok {
+			s = s.With(CategoryZero + Category(i))
+		}
+	}
+	return s
+}
+
+// LocaleCategories returns the cardinal and ordinal plural categories for locale.
+func LocaleCategories(locale language.Tag) (cardinal, ordinal Categories) {
+	c, o := LocalePluralRules(locale)
+	return CategoriesOf(c), CategoriesOf(o)
+}
+
+// PluralRules returns s as PluralRules.
+func (s Categories) PluralRules() PluralRules {
+	return PluralRules{
+		Zero:  s.Has(CategoryZero),
+		One:   s.Has(CategoryOne),
+		Two:   s.Has(CategoryTwo),
+		Few:   s.Has(CategoryFew),
+		Many:  s.Has(CategoryMany),
+		Other: s.Has(CategoryOther),
+	}
+}
+
+func bit(c Category) Categories {
+	if c < CategoryZero || c > CategoryOther {
+		return 0
+	}
+	return 1 << (c - 1)
+}
+
+// With returns a copy of s including c.
+func (s Categories) With(c Category) Categories { return s | bit(c) }
+
+// Has returns true if c is in s.
+func (s Categories) Has(c Category) bool { return bit(c) != 0 && s&bit(c) != 0 }
+
+// Len returns the number of categories in s.
+func (s Categories) Len() (n int) {
+	for range s.All() {
+		n++
+	}
+	return n
+}
+
+// Missing returns the categories of s that aren't in other.
+func (s Categories) Missing(other Categories) Categories { return s &^ other }
+
+// All iterates over all categories in s in CLDR order (zero to other).
+func (s Categories) All() iter.Seq[Category] {
+	return func(yield func(Category) bool) {
+		for c := CategoryZero; c <= CategoryOther; c++ {
+			if s.Has(c) && !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+// String returns a comma-separated list of categories, for example "one,other".
+func (s Categories) String() string {
+	var b strings.Builder
+	for c := range s.All() {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(c.String())
+	}
+	return b.String()
+}
+
+// Locales returns all locales known to the CLDR plural rules data
+// sorted by their string representation.
+func Locales() []language.Tag {
+	l := make([]language.Tag, 0, len(cldr.PluralRulesByTag))
+	for t := range cldr.PluralRulesByTag {
+		l = append(l, t)
+	}
+	slices.SortFunc(l, func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return l
+}
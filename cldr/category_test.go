@@ -0,0 +1,102 @@
+package cldr_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestCategoryString(t *testing.T) {
+	f := func(t *testing.T, expect string, c cldr.Category) {
+		t.Helper()
+		test.RequireEqual(t, expect, c.String())
+		if c == 0 {
+			return
+		}
+		parsed, err := cldr.ParseCategory(expect)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, c, parsed)
+	}
+
+	f(t, "unknown", 0)
+	f(t, "zero", cldr.CategoryZero)
+	f(t, "one", cldr.CategoryOne)
+	f(t, "two", cldr.CategoryTwo)
+	f(t, "few", cldr.CategoryFew)
+	f(t, "many", cldr.CategoryMany)
+	f(t, "other", cldr.CategoryOther)
+
+	_, err := cldr.ParseCategory("=1")
+	test.RequireErrIs(t, cldr.ErrUnknownCategory, err)
+}
+
+func TestCategoryTokenType(t *testing.T) {
+	f := func(t *testing.T, c cldr.Category, tp icumsg.TokenType) {
+		t.Helper()
+		test.RequireEqual(t, tp, c.TokenType())
+		actual, ok := cldr.CategoryOf(tp)
+		test.RequireEqual(t, true, ok)
+		test.RequireEqual(t, c, actual)
+	}
+
+	f(t, cldr.CategoryZero, icumsg.TokenTypeOptionZero)
+	f(t, cldr.CategoryOne, icumsg.TokenTypeOptionOne)
+	f(t, cldr.CategoryTwo, icumsg.TokenTypeOptionTwo)
+	f(t, cldr.CategoryFew, icumsg.TokenTypeOptionFew)
+	f(t, cldr.CategoryMany, icumsg.TokenTypeOptionMany)
+	f(t, cldr.CategoryOther, icumsg.TokenTypeOptionOther)
+
+	test.RequireEqual(t, icumsg.TokenType(0), cldr.Category(0).TokenType())
+	_, ok := cldr.CategoryOf(icumsg.TokenTypeOptionNumber)
+	test.RequireEqual(t, false, ok)
+	_, ok = cldr.CategoryOf(icumsg.TokenTypeOption)
+	test.RequireEqual(t, false, ok)
+}
+
+func TestCategories(t *testing.T) {
+	s := cldr.NewCategories(cldr.CategoryOther, cldr.CategoryOne, cldr.CategoryFew)
+	test.RequireEqual(t, "one,few,other", s.String())
+	test.RequireEqual(t, 3, s.Len())
+	test.RequireEqual(t, true, s.Has(cldr.CategoryOne))
+	test.RequireEqual(t, false, s.Has(cldr.CategoryMany))
+	test.RequireEqual(t, false, s.Has(0))
+	test.RequireDeepEqual(t,
+		[]cldr.Category{cldr.CategoryOne, cldr.CategoryFew, cldr.CategoryOther},
+		slices.Collect(s.All()))
+
+	present := cldr.NewCategories(cldr.CategoryOne, cldr.CategoryOther)
+	test.RequireEqual(t, cldr.NewCategories(cldr.CategoryFew), s.Missing(present))
+	test.RequireEqual(t, cldr.Categories(0), present.Missing(s))
+	test.RequireEqual(t, "", cldr.Categories(0).String())
+
+	test.RequireEqual(t, cldr.PluralRules{One: true, Few: true, Other: true},
+		s.PluralRules())
+	test.RequireEqual(t, s, cldr.CategoriesOf(s.PluralRules()))
+}
+
+func TestLocaleCategories(t *testing.T) {
+	cardinal, ordinal := cldr.LocaleCategories(language.Polish)
+	test.RequireEqual(t, "one,few,many,other", cardinal.String())
+	test.RequireEqual(t, "other", ordinal.String())
+
+	cardinal, ordinal = cldr.LocaleCategories(language.Arabic)
+	test.RequireEqual(t, "zero,one,two,few,many,other", cardinal.String())
+	test.RequireEqual(t, "other", ordinal.String())
+}
+
+func TestLocales(t *testing.T) {
+	l := cldr.Locales()
+	test.RequireEqual(t, true, len(l) > 200)
+	test.RequireEqual(t, true, slices.Contains(l, language.English))
+	test.RequireEqual(t, true, slices.Contains(l, language.Und))
+	test.RequireEqual(t, true, slices.IsSortedFunc(l, func(a, b language.Tag) int {
+		if a.String() < b.String() {
+			return -1
+		}
+		return 1
+	}))
+}
@@ -1,18 +1,14 @@
 package cldr
 
 import (
-	"github.com/romshark/icumsg/internal/cldr"
+	"github.com/romshark/icumsg"
 	"golang.org/x/text/language"
 )
 
-type PluralRules struct{ Zero, One, Two, Few, Many, Other bool }
+// PluralRules defines which CLDR plural categories are supported.
+type PluralRules = icumsg.PluralRules
 
 // LocalePluralRules returns cardinal and ordinal plural rules for locale.
 func LocalePluralRules(locale language.Tag) (cardinal, ordinal PluralRules) {
-	r, ok := cldr.PluralRulesByTag[locale]
-	if !ok {
-		base, _ := locale.Base()
-		r = cldr.PluralRulesByBase[base]
-	}
-	return PluralRules(r.Cardinal), PluralRules(r.Ordinal)
+	return icumsg.CLDRPluralRules{}.PluralRules(locale)
 }
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/msgfile"
+	"github.com/romshark/icumsg/lint"
+	"golang.org/x/text/language"
+)
+
+// document is an open text document.
+type document struct {
+	uri     string
+	version int
+	text    string
+
+	// file is nil if err != nil.
+	file *msgfile.File
+	err  error
+
+	// messages holds the tokenized messages of file in the same order.
+	messages []tokenized
+}
+
+type tokenized struct {
+	buffer []icumsg.Token
+	err    error
+	errPos int // Byte offset in the message text.
+}
+
+// fileName returns the file name of a document URI.
+func fileName(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return uri
+}
+
+func newDocument(uri string, version int, text string, defaultLocale language.Tag) *document {
+	d := &document{uri: uri, version: version, text: text}
+	name := fileName(uri)
+	d.file, d.err = msgfile.Parse(name, []byte(text), language.Und)
+	if errors.Is(d.err, msgfile.ErrUnknownLocale) && defaultLocale != language.Und {
+		d.file, d.err = msgfile.Parse(name, []byte(text), defaultLocale)
+	}
+	if d.err != nil {
+		return d
+	}
+	var tokenizer icumsg.Tokenizer
+	d.messages = make([]tokenized, len(d.file.Messages))
+	for i, m := range d.file.Messages {
+		t := &d.messages[i]
+		t.buffer, t.err = tokenizer.Tokenize(d.file.Locale, nil, m.Text)
+		t.errPos = tokenizer.Pos()
+	}
+	return d
+}
+
+// position returns the LSP position of a byte offset in the document.
+func (d *document) position(offset int) position {
+	p := d.file.Position(offset)
+	return position{Line: p.Line - 1, Character: d.file.UTF16Column(offset)}
+}
+
+// rangeOf returns the LSP range of m.Text[start:end].
+func (d *document) rangeOf(m *msgfile.Message, start, end int) lspRange {
+	return lspRange{
+		Start: d.position(m.FileOffset(start)),
+		End:   d.position(m.FileOffset(end)),
+	}
+}
+
+// errorRange returns the range of the position of a file parser error.
+func errorRange(text string, err error) lspRange {
+	var e *msgfile.Error
+	if !errors.As(err, &e) {
+		return lspRange{}
+	}
+	lineStart := strings.LastIndexByte(text[:e.Pos.Offset], '\n') + 1
+	p := position{Line: e.Pos.Line - 1, Character: utf16Len(text[lineStart:e.Pos.Offset])}
+	return lspRange{Start: p, End: p}
+}
+
+func utf16Len(s string) (n int) {
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+func lintSeverity(s lint.Severity) int {
+	switch s {
+	case lint.SeverityError:
+		return severityError
+	case lint.SeverityWarning:
+		return severityWarning
+	}
+	return severityInformation
+}
+
+func (d *document) diagnostics(linter *lint.Linter) []diagnostic {
+	diagnostics := []diagnostic{}
+	if errors.Is(d.err, msgfile.ErrUnknownLocale) {
+		return append(diagnostics, diagnostic{
+			Severity: severityWarning, Source: "icumsg", Code: "unknown-locale",
+			Message: "unknown locale: name the file after its locale " +
+				`(for example "de.json") or add "@@locale" to the catalog`,
+		})
+	} else if d.err != nil {
+		msg := d.err.Error()
+		if e := (*msgfile.Error)(nil); errors.As(d.err, &e) {
+			msg = e.Msg // The position is provided by the range.
+		}
+		return append(diagnostics, diagnostic{
+			Range:    errorRange(d.text, d.err),
+			Severity: severityError, Source: "icumsg", Code: "syntax",
+			Message: msg,
+		})
+	}
+	for i := range d.file.Messages {
+		m, t := &d.file.Messages[i], d.messages[i]
+		if t.err != nil {
+			end := min(t.errPos+1, len(m.Text))
+			diagnostics = append(diagnostics, diagnostic{
+				Range:    d.rangeOf(m, t.errPos, end),
+				Severity: severityError, Source: "icumsg", Code: "syntax",
+				Message: t.err.Error(),
+			})
+			continue
+		}
+		r := icumsg.NewCompletenessReport(m.Text, t.buffer, d.file.Locale, nil,
+			func(string) ([]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy) {
+				return nil, 0, 0
+			})
+		for _, c := range r.Choices {
+			if c.Complete {
+				continue
+			}
+			diagnostics = append(diagnostics, diagnostic{
+				Range:    d.rangeOf(m, c.Start, c.End),
+				Severity: severityWarning, Source: "icumsg", Code: "incomplete",
+				Message: fmt.Sprintf("incomplete %s argument %q: missing %s",
+					c.Kind, c.Arg, strings.Join(c.MissingCategories, ", ")),
+			})
+		}
+		for _, l := range linter.Lint(lint.Message{
+			ID: m.ID, Locale: d.file.Locale, Source: m.Text,
+			Buffer: t.buffer, Comment: m.Comment,
+		}) {
+			diagnostics = append(diagnostics, diagnostic{
+				Range:    d.rangeOf(m, l.Start, l.End),
+				Severity: lintSeverity(l.Severity), Source: "icumsg", Code: l.Rule,
+				Message: l.Message,
+			})
+		}
+	}
+	return diagnostics
+}
+
+// at returns the message at p, its tokens and the byte offset in its text.
+func (d *document) at(p position) (*msgfile.Message, []icumsg.Token, int, bool) {
+	if d.err != nil {
+		return nil, nil, 0, false
+	}
+	offset := d.file.OffsetOf(p.Line, p.Character)
+	for i := range d.file.Messages {
+		m := &d.file.Messages[i]
+		o, ok := m.TextOffset(offset)
+		if !ok {
+			continue
+		}
+		if d.messages[i].err != nil {
+			break
+		}
+		return m, d.messages[i].buffer, o, true
+	}
+	return nil, nil, 0, false
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/internal/msgfile"
+)
+
+// span returns the byte range of the complex token at buffer[i].
+func span(buffer []icumsg.Token, i int) (start, end int) {
+	return buffer[i].IndexStart, buffer[buffer[i].IndexEnd].IndexEnd
+}
+
+// optionContent returns the byte range of the contents of the option
+// at buffer[i] between its brackets.
+func optionContent(src string, buffer []icumsg.Token, i int) (start, end int) {
+	start, end = span(buffer, i)
+	// Option names never contain '{'.
+	return start + strings.IndexByte(src[start:], '{') + 1, end - 1
+}
+
+// pluralChoiceAt returns the index of the innermost plural or selectordinal
+// containing offset or -1 if there's none.
+func pluralChoiceAt(buffer []icumsg.Token, offset int) int {
+	choice := -1
+	for i, t := range buffer {
+		if t.Type != icumsg.TokenTypePlural && t.Type != icumsg.TokenTypeSelectOrdinal {
+			continue
+		}
+		if start, end := span(buffer, i); offset >= start && offset < end {
+			choice = i
+		}
+	}
+	return choice
+}
+
+// categories returns the plural categories of the locale applicable
+// to the plural or selectordinal at buffer[choice] and those present.
+func categories(
+	f *msgfile.File, buffer []icumsg.Token, choice int,
+) (kind string, all, present cldr.Categories) {
+	cardinal, ordinal := cldr.LocaleCategories(f.Locale)
+	kind, all = "cardinal", cardinal
+	if buffer[choice].Type == icumsg.TokenTypeSelectOrdinal {
+		kind, all = "ordinal", ordinal
+	}
+	for o := range icumsg.Options(buffer, choice) {
+		if c, ok := cldr.CategoryOf(buffer[o].Type); ok {
+			present = present.With(c)
+		}
+	}
+	return kind, all, present
+}
+
+func (d *document) hover(p position) *hover {
+	m, buffer, offset, ok := d.at(p)
+	if !ok {
+		return nil
+	}
+	choice := pluralChoiceAt(buffer, offset)
+	if choice == -1 {
+		return nil
+	}
+	kind, all, present := categories(d.file, buffer, choice)
+	for o := range icumsg.Options(buffer, choice) {
+		name := icumsg.OptionName(m.Text, buffer, o)
+		nameStart := buffer[o].IndexStart
+		nameEnd := nameStart + len(name)
+		if offset >= nameStart && offset <= nameEnd {
+			c, ok := cldr.CategoryOf(buffer[o].Type)
+			if !ok {
+				return &hover{
+					Contents: markupContent{Kind: "markdown", Value: fmt.Sprintf(
+						"`%s`: exact match, takes precedence over plural categories", name,
+					)},
+					Range: d.rangeOf(m, nameStart, nameEnd),
+				}
+			}
+			return &hover{
+				Contents: markupContent{Kind: "markdown", Value: fmt.Sprintf(
+					"`%s`: %s plural category of `%s`\n\nCategories: %s",
+					c, kind, d.file.Locale, all,
+				)},
+				Range: d.rangeOf(m, nameStart, nameEnd),
+			}
+		}
+		if _, end := span(buffer, o); offset > nameEnd && offset < end {
+			return nil // Contents of the option.
+		}
+	}
+	name := buffer[choice+1]
+	value := fmt.Sprintf("`%s`: %s plural categories of `%s`: %s",
+		name.String(m.Text, buffer), kind, d.file.Locale, all)
+	if missing := all.Missing(present); missing.Len() > 0 {
+		value += fmt.Sprintf("\n\nMissing: %s", missing)
+	}
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: value},
+		Range:    d.rangeOf(m, name.IndexStart, name.IndexEnd),
+	}
+}
+
+// completion suggests the missing plural categories of the innermost plural
+// or selectordinal at p unless p is inside the contents of an option.
+// The contents of suggested options are copied from option other.
+func (d *document) completion(p position) []completionItem {
+	items := []completionItem{}
+	m, buffer, offset, ok := d.at(p)
+	if !ok {
+		return items
+	}
+	choice := pluralChoiceAt(buffer, offset)
+	if choice == -1 {
+		return items
+	}
+	var other string
+	for o := range icumsg.Options(buffer, choice) {
+		start, end := optionContent(m.Text, buffer, o)
+		if offset >= start && offset <= end {
+			return items // Inside the contents of an option.
+		}
+		if buffer[o].Type == icumsg.TokenTypeOptionOther {
+			other = m.Text[start:end]
+		}
+	}
+	kind, all, present := categories(d.file, buffer, choice)
+	for c := range all.Missing(present).All() {
+		text := c.String() + " {" + other + "}"
+		if msgfile.IsCatalog(d.file.Name) {
+			text = jsonEscape(text)
+		}
+		items = append(items, completionItem{
+			Label:      c.String(),
+			Kind:       completionItemKindKeyword,
+			Detail:     fmt.Sprintf("missing %s plural category of %s", kind, d.file.Locale),
+			InsertText: text,
+		})
+	}
+	return items
+}
+
+// jsonEscape returns s escaped for use in a JSON string without quotes.
+func jsonEscape(s string) string {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	_ = e.Encode(s)                         // Encoding a string never fails.
+	return string(b.Bytes()[1 : b.Len()-2]) // Trim quotes and newline.
+}
+
+// Semantic token types, indexes of semanticTokenTypes.
+const (
+	semanticVariable = iota
+	semanticKeyword
+	semanticType
+	semanticModifier
+	semanticEnumMember
+	semanticNumber
+	semanticString
+)
+
+var semanticTokenTypes = []string{
+	semanticVariable:   "variable",
+	semanticKeyword:    "keyword",
+	semanticType:       "type",
+	semanticModifier:   "modifier",
+	semanticEnumMember: "enumMember",
+	semanticNumber:     "number",
+	semanticString:     "string",
+}
+
+// semanticTokenTypeOf returns the semantic token type of t or -1 if it has none.
+func semanticTokenTypeOf(t icumsg.TokenType) int {
+	switch {
+	case t == icumsg.TokenTypeLiteral:
+		return semanticString
+	case t == icumsg.TokenTypeArgName:
+		return semanticVariable
+	case t == icumsg.TokenTypePluralOffset:
+		return semanticNumber
+	case t == icumsg.TokenTypeOptionName:
+		return semanticEnumMember
+	case t >= icumsg.TokenTypeArgTypeNumber && t <= icumsg.TokenTypeArgTypeDuration:
+		return semanticType
+	case t >= icumsg.TokenTypeArgStyleShort && t <= icumsg.TokenTypeArgStyleSkeleton:
+		return semanticModifier
+	}
+	return -1
+}
+
+// choiceKeywordEnd returns the end of the keyword ("plural", "select" or
+// "selectordinal") following the argument name ending at i and its start.
+func choiceKeywordEnd(src string, i int) (start, end int) {
+	for i < len(src) && (src[i] == ',' || icumsg.IsWhitespace(src[i])) {
+		i++
+	}
+	start = i
+	for i < len(src) && src[i] >= 'a' && src[i] <= 'z' {
+		i++
+	}
+	return start, i
+}
+
+// semanticTokens returns the relative encoded semantic tokens of the document.
+func (d *document) semanticTokens() []uint32 {
+	type tokenSpan struct{ start, end, tokenType int } // Byte offsets in the file.
+	var spans []tokenSpan
+	for i := range d.messages {
+		m, buffer := &d.file.Messages[i], d.messages[i].buffer
+		if d.messages[i].err != nil {
+			continue
+		}
+		add := func(start, end, tokenType int) {
+			if start < end {
+				spans = append(spans, tokenSpan{m.FileOffset(start), m.FileOffset(end), tokenType})
+			}
+		}
+		for j, t := range buffer {
+			switch t.Type {
+			case icumsg.TokenTypePlural, icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+				start, end := choiceKeywordEnd(m.Text, buffer[j+1].IndexEnd)
+				add(start, end, semanticKeyword)
+			case icumsg.TokenTypeOptionZero, icumsg.TokenTypeOptionOne,
+				icumsg.TokenTypeOptionTwo, icumsg.TokenTypeOptionFew,
+				icumsg.TokenTypeOptionMany, icumsg.TokenTypeOptionOther:
+				name := icumsg.OptionName(m.Text, buffer, j)
+				add(t.IndexStart, t.IndexStart+len(name), semanticEnumMember)
+			default:
+				if st := semanticTokenTypeOf(t.Type); st != -1 {
+					add(t.IndexStart, t.IndexEnd, st)
+				}
+			}
+		}
+	}
+	slices.SortFunc(spans, func(a, b tokenSpan) int { return a.start - b.start })
+
+	data := []uint32{}
+	var prevLine, prevChar int
+	for _, s := range spans {
+		// Semantic tokens must not span multiple lines.
+		for start := s.start; start < s.end; {
+			end := s.end
+			if nl := strings.IndexByte(d.text[start:end], '\n'); nl != -1 {
+				end = start + nl
+			}
+			if text := strings.TrimSuffix(d.text[start:end], "\r"); text != "" {
+				p := d.position(start)
+				deltaChar := p.Character
+				if p.Line == prevLine {
+					deltaChar -= prevChar
+				}
+				data = append(data,
+					uint32(p.Line-prevLine), uint32(deltaChar),
+					uint32(utf16Len(text)), uint32(s.tokenType), 0)
+				prevLine, prevChar = p.Line, p.Character
+			}
+			start = end + 1 // Skip the line break.
+		}
+	}
+	return data
+}
+
+// format returns the edits formatting all valid messages canonically.
+func (d *document) format() []textEdit {
+	edits := []textEdit{}
+	for i := range d.messages {
+		m, t := &d.file.Messages[i], d.messages[i]
+		if t.err != nil {
+			continue
+		}
+		formatted := icumsg.Format(m.Text, t.buffer)
+		if formatted == m.Text {
+			continue
+		}
+		if msgfile.IsCatalog(d.file.Name) {
+			formatted = jsonEscape(formatted)
+		}
+		edits = append(edits, textEdit{
+			Range:   d.rangeOf(m, 0, len(m.Text)),
+			NewText: formatted,
+		})
+	}
+	return edits
+}
@@ -0,0 +1,28 @@
+// Command icumsg-lsp is a Language Server Protocol server for ICU message
+// files (.icu) and JSON catalogs (.json, .arb) communicating over stdio.
+//
+// The server provides:
+//
+//   - diagnostics: syntax errors, incomplete plurals and lint rule violations
+//   - hover: the CLDR plural categories of the file's locale
+//   - completion: the missing plural categories of a plural or selectordinal
+//   - semantic tokens: argument names, types, styles, option names and literals
+//   - formatting: canonical formatting of all valid messages
+//
+// The locale of a file is determined from the catalog's "@@locale" key or
+// the file name. The initialization options are optional:
+//
+//	{"defaultLocale": "en", "lintConfig": "/path/to/icumsg-lint.json"}
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := newServer(os.Stdout).serve(os.Stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/romshark/icumsg/lint"
+	"golang.org/x/text/language"
+)
+
+// server is a language server communicating over a single connection.
+// Messages are handled sequentially in the order they're received.
+type server struct {
+	w io.Writer
+
+	initialized   bool
+	shutdown      bool
+	defaultLocale language.Tag
+	linter        *lint.Linter
+	documents     map[string]*document
+
+	// err is the first error writing a notification.
+	err error
+}
+
+func newServer(w io.Writer) *server {
+	return &server{w: w, documents: map[string]*document{}}
+}
+
+// errExitWithoutShutdown is returned by serve when the client sent "exit"
+// without requesting "shutdown" first.
+var errExitWithoutShutdown = errors.New("exit without shutdown")
+
+// serve handles messages read from r until the client sends "exit"
+// or r is closed.
+func (s *server) serve(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		body, err := readMessage(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		var m message
+		if err := json.Unmarshal(body, &m); err != nil {
+			if err := s.reply(nil, nil, &responseError{
+				Code: codeParseError, Message: err.Error(),
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.Method == "exit" {
+			if !s.shutdown {
+				return errExitWithoutShutdown
+			}
+			return nil
+		}
+		result, rErr := s.handle(m)
+		if s.err != nil {
+			return s.err
+		}
+		if m.ID == nil {
+			continue // Notifications are never replied to.
+		}
+		if err := s.reply(m.ID, result, rErr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) reply(id *json.RawMessage, result any, rErr *responseError) error {
+	m := message{ID: id, Error: rErr}
+	if id == nil {
+		m.ID = new(json.RawMessage)
+		*m.ID = json.RawMessage("null")
+	}
+	if rErr == nil {
+		var err error
+		if m.Result, err = json.Marshal(result); err != nil {
+			return err
+		}
+	}
+	return writeMessage(s.w, m)
+}
+
+// notify sends a notification. Errors are recorded in s.err.
+func (s *server) notify(method string, params any) {
+	if s.err != nil {
+		return
+	}
+	p, err := json.Marshal(params)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.err = writeMessage(s.w, message{Method: method, Params: p})
+}
+
+// handle handles a request or notification and returns the result.
+func (s *server) handle(m message) (any, *responseError) {
+	if !s.initialized && m.Method != "initialize" {
+		return nil, &responseError{Code: codeServerNotReady, Message: "server not initialized"}
+	}
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shut down"}
+	}
+	decode := func(v any) *responseError {
+		if err := json.Unmarshal(m.Params, v); err != nil {
+			return &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return nil
+	}
+
+	switch m.Method {
+	case "initialize":
+		var p initializeParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		return s.initialize(p)
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		s.open(p.TextDocument.URI, p.TextDocument.Version, p.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if len(p.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// Full synchronization, the last change contains the full text.
+		text := p.ContentChanges[len(p.ContentChanges)-1].Text
+		s.open(p.TextDocument.URI, p.TextDocument.Version, text)
+		return nil, nil
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		delete(s.documents, p.TextDocument.URI)
+		s.publish(p.TextDocument.URI, 0, []diagnostic{})
+		return nil, nil
+	case "textDocument/hover":
+		var p textDocumentPositionParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if d := s.documents[p.TextDocument.URI]; d != nil {
+			if h := d.hover(p.Position); h != nil {
+				return h, nil
+			}
+		}
+		return nil, nil
+	case "textDocument/completion":
+		var p textDocumentPositionParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if d := s.documents[p.TextDocument.URI]; d != nil {
+			return d.completion(p.Position), nil
+		}
+		return []completionItem{}, nil
+	case "textDocument/semanticTokens/full":
+		var p documentParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if d := s.documents[p.TextDocument.URI]; d != nil {
+			return semanticTokens{Data: d.semanticTokens()}, nil
+		}
+		return semanticTokens{Data: []uint32{}}, nil
+	case "textDocument/formatting":
+		var p documentParams
+		if err := decode(&p); err != nil {
+			return nil, err
+		}
+		if d := s.documents[p.TextDocument.URI]; d != nil {
+			return d.format(), nil
+		}
+		return []textEdit{}, nil
+	}
+	return nil, &responseError{
+		Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %q", m.Method),
+	}
+}
+
+func (s *server) initialize(p initializeParams) (any, *responseError) {
+	o := p.InitializationOptions
+	if o.DefaultLocale != "" {
+		var err error
+		if s.defaultLocale, err = language.Parse(o.DefaultLocale); err != nil {
+			return nil, &responseError{
+				Code: codeInvalidParams, Message: fmt.Sprintf("invalid defaultLocale: %v", err),
+			}
+		}
+	}
+	var config lint.Config
+	if o.LintConfig != "" {
+		var err error
+		if config, err = lint.LoadConfig(o.LintConfig); err != nil {
+			return nil, &responseError{
+				Code: codeInvalidParams, Message: fmt.Sprintf("loading lintConfig: %v", err),
+			}
+		}
+	}
+	var err error
+	if s.linter, err = lint.New(config); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	s.initialized = true
+
+	var r initializeResult
+	r.ServerInfo.Name = "icumsg-lsp"
+	r.Capabilities = serverCapabilities{
+		TextDocumentSync:           textDocumentSyncFull,
+		HoverProvider:              true,
+		DocumentFormattingProvider: true,
+	}
+	r.Capabilities.SemanticTokensProvider.Legend.TokenTypes = semanticTokenTypes
+	r.Capabilities.SemanticTokensProvider.Legend.TokenModifiers = []string{}
+	r.Capabilities.SemanticTokensProvider.Full = true
+	return r, nil
+}
+
+// open analyzes the document and publishes its diagnostics.
+func (s *server) open(uri string, version int, text string) {
+	d := newDocument(uri, version, text, s.defaultLocale)
+	s.documents[uri] = d
+	s.publish(uri, version, d.diagnostics(s.linter))
+}
+
+func (s *server) publish(uri string, version int, diagnostics []diagnostic) {
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI: uri, Version: version, Diagnostics: diagnostics,
+	})
+}
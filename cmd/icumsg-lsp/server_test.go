@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+)
+
+// session collects client messages to run the server on.
+type session struct {
+	t      *testing.T
+	in     bytes.Buffer
+	nextID int
+}
+
+func (s *session) request(method string, params any) int {
+	s.t.Helper()
+	s.nextID++
+	id := json.RawMessage(strconv.Itoa(s.nextID))
+	s.send(message{ID: &id, Method: method, Params: marshal(s.t, params)})
+	return s.nextID
+}
+
+func (s *session) notify(method string, params any) {
+	s.t.Helper()
+	s.send(message{Method: method, Params: marshal(s.t, params)})
+}
+
+func (s *session) send(m message) {
+	s.t.Helper()
+	test.RequireNoErr(s.t, writeMessage(&s.in, m))
+}
+
+func marshal(t *testing.T, v any) json.RawMessage {
+	t.Helper()
+	b, err := json.Marshal(v)
+	test.RequireNoErr(t, err)
+	return b
+}
+
+// run runs the server and returns the results of requests by ID
+// and the notifications sent by the server.
+func (s *session) run(expectErr error) (
+	results map[int]json.RawMessage, errs map[int]*responseError, notifications []message,
+) {
+	s.t.Helper()
+	var out bytes.Buffer
+	err := newServer(&out).serve(&s.in)
+	test.RequireErrIs(s.t, expectErr, err)
+
+	results, errs = map[int]json.RawMessage{}, map[int]*responseError{}
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			break
+		}
+		var m message
+		test.RequireNoErr(s.t, json.Unmarshal(body, &m))
+		if m.ID == nil {
+			notifications = append(notifications, m)
+			continue
+		}
+		id, _ := strconv.Atoi(string(*m.ID))
+		if m.Error != nil {
+			errs[id] = m.Error
+		} else {
+			results[id] = m.Result
+		}
+	}
+	return results, errs, notifications
+}
+
+func newSession(t *testing.T, initOptions map[string]string) *session {
+	t.Helper()
+	s := &session{t: t}
+	s.request("initialize", map[string]any{"initializationOptions": initOptions})
+	s.notify("initialized", struct{}{})
+	return s
+}
+
+func (s *session) open(uri, text string) {
+	s.t.Helper()
+	s.notify("textDocument/didOpen", didOpenParams{
+		TextDocument: textDocumentItem{URI: uri, Version: 1, Text: text},
+	})
+}
+
+func (s *session) shutdown() {
+	s.t.Helper()
+	s.request("shutdown", nil)
+	s.notify("exit", nil)
+}
+
+func at(uri string, line, character int) textDocumentPositionParams {
+	return textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     position{Line: line, Character: character},
+	}
+}
+
+func unmarshal[T any](t *testing.T, data json.RawMessage) (v T) {
+	t.Helper()
+	test.RequireNoErr(t, json.Unmarshal(data, &v))
+	return v
+}
+
+func TestInitialize(t *testing.T) {
+	s := newSession(t, nil)
+	s.shutdown()
+	results, errs, _ := s.run(nil)
+	test.RequireEqual(t, 0, len(errs))
+	r := unmarshal[initializeResult](t, results[1])
+	test.RequireEqual(t, "icumsg-lsp", r.ServerInfo.Name)
+	test.RequireEqual(t, textDocumentSyncFull, r.Capabilities.TextDocumentSync)
+	test.RequireDeepEqual(t, semanticTokenTypes,
+		r.Capabilities.SemanticTokensProvider.Legend.TokenTypes)
+}
+
+func TestNotInitialized(t *testing.T) {
+	s := &session{t: t}
+	s.request("textDocument/hover", at("file:///en.icu", 0, 0))
+	s.notify("exit", nil)
+	_, errs, _ := s.run(errExitWithoutShutdown)
+	test.RequireEqual(t, codeServerNotReady, errs[1].Code)
+}
+
+func TestMethodNotFound(t *testing.T) {
+	s := newSession(t, nil)
+	s.request("workspace/symbol", struct{}{})
+	s.notify("$/cancelRequest", struct{}{}) // Unknown notifications are ignored.
+	s.shutdown()
+	_, errs, notifications := s.run(nil)
+	test.RequireEqual(t, codeMethodNotFound, errs[2].Code)
+	test.RequireEqual(t, 0, len(notifications))
+}
+
+func TestDiagnostics(t *testing.T) {
+	s := newSession(t, nil)
+	s.open("file:///locales/pl.json", "{\n"+
+		`  "ok": "Cześć {name}",`+"\n"+
+		`  "files": "{n, plural, one {# plik} other {# pliku}}",`+"\n"+
+		`  "bad": "{n, plural, other {# x}",`+"\n"+
+		`  "style": "{user_name}"`+"\n"+
+		"}")
+	s.open("file:///locales/messages.json", `{"x": "y"}`)
+	s.notify("textDocument/didClose", didCloseParams{
+		TextDocument: textDocumentIdentifier{URI: "file:///locales/pl.json"},
+	})
+	s.shutdown()
+	_, _, notifications := s.run(nil)
+	test.RequireEqual(t, 3, len(notifications))
+
+	p := unmarshal[publishDiagnosticsParams](t, notifications[0].Params)
+	test.RequireEqual(t, "file:///locales/pl.json", p.URI)
+	test.RequireDeepEqual(t, []diagnostic{
+		{
+			Range: lspRange{
+				Start: position{Line: 2, Character: 12},
+				End:   position{Line: 2, Character: 53},
+			},
+			Severity: severityWarning, Source: "icumsg", Code: "incomplete",
+			Message: `incomplete plural argument "n": missing few, many`,
+		},
+		{
+			Range: lspRange{
+				Start: position{Line: 3, Character: 33},
+				End:   position{Line: 3, Character: 33},
+			},
+			Severity: severityError, Source: "icumsg", Code: "syntax",
+			Message: "unexpected EOF",
+		},
+		{
+			Range: lspRange{
+				Start: position{Line: 4, Character: 13},
+				End:   position{Line: 4, Character: 22},
+			},
+			Severity: severityWarning, Source: "icumsg", Code: "arg-name-case",
+			Message: `argument name "user_name" is not camel case`,
+		},
+	}, p.Diagnostics)
+
+	p = unmarshal[publishDiagnosticsParams](t, notifications[1].Params)
+	test.RequireEqual(t, 1, len(p.Diagnostics))
+	test.RequireEqual(t, "unknown-locale", p.Diagnostics[0].Code)
+
+	p = unmarshal[publishDiagnosticsParams](t, notifications[2].Params)
+	test.RequireEqual(t, "file:///locales/pl.json", p.URI)
+	test.RequireEqual(t, 0, len(p.Diagnostics))
+}
+
+func TestDiagnosticsDefaultLocale(t *testing.T) {
+	s := newSession(t, map[string]string{"defaultLocale": "en"})
+	s.open("file:///messages.json", `{"x": "{n, plural, other {#}}"}`)
+	s.shutdown()
+	_, _, notifications := s.run(nil)
+	p := unmarshal[publishDiagnosticsParams](t, notifications[0].Params)
+	test.RequireEqual(t, 1, len(p.Diagnostics))
+	test.RequireEqual(t, `incomplete plural argument "n": missing one`,
+		p.Diagnostics[0].Message)
+}
+
+func TestDiagnosticsCatalogSyntaxError(t *testing.T) {
+	s := newSession(t, nil)
+	s.open("file:///de.json", "{\n  \"x\" \"y\"}")
+	s.shutdown()
+	_, _, notifications := s.run(nil)
+	p := unmarshal[publishDiagnosticsParams](t, notifications[0].Params)
+	test.RequireDeepEqual(t, []diagnostic{{
+		Range: lspRange{
+			Start: position{Line: 1, Character: 6},
+			End:   position{Line: 1, Character: 6},
+		},
+		Severity: severityError, Source: "icumsg", Code: "syntax",
+		Message: `expected ':'`,
+	}}, p.Diagnostics)
+}
+
+func TestHover(t *testing.T) {
+	const uri = "file:///welcome.pl.icu"
+	s := newSession(t, nil)
+	s.open(uri, "Masz {n, plural, one {# plik} =5 {pięć} other {# pliku}}")
+	hoverArg := s.request("textDocument/hover", at(uri, 0, 6))
+	hoverCategory := s.request("textDocument/hover", at(uri, 0, 18))
+	hoverExact := s.request("textDocument/hover", at(uri, 0, 31))
+	hoverContent := s.request("textDocument/hover", at(uri, 0, 24))
+	hoverLiteral := s.request("textDocument/hover", at(uri, 0, 1))
+	s.shutdown()
+	results, _, _ := s.run(nil)
+
+	test.RequireDeepEqual(t, hover{
+		Contents: markupContent{
+			Kind: "markdown",
+			Value: "`n`: cardinal plural categories of `pl`: one,few,many,other\n\n" +
+				"Missing: few,many",
+		},
+		Range: lspRange{Start: position{0, 6}, End: position{0, 7}},
+	}, unmarshal[hover](t, results[hoverArg]))
+
+	test.RequireDeepEqual(t, hover{
+		Contents: markupContent{
+			Kind: "markdown",
+			Value: "`one`: cardinal plural category of `pl`\n\n" +
+				"Categories: one,few,many,other",
+		},
+		Range: lspRange{Start: position{0, 17}, End: position{0, 20}},
+	}, unmarshal[hover](t, results[hoverCategory]))
+
+	test.RequireEqual(t,
+		"`=5`: exact match, takes precedence over plural categories",
+		unmarshal[hover](t, results[hoverExact]).Contents.Value)
+	test.RequireEqual(t, "null", string(results[hoverContent]))
+	test.RequireEqual(t, "null", string(results[hoverLiteral]))
+}
+
+func TestCompletion(t *testing.T) {
+	const uri = "file:///pl.json"
+	s := newSession(t, nil)
+	s.open(uri, `{"x": "{n, plural, one {# plik} other {# \"pliku\"}}"}`)
+	between := s.request("textDocument/completion", at(uri, 0, 32))
+	inside := s.request("textDocument/completion", at(uri, 0, 26))
+	s.shutdown()
+	results, _, _ := s.run(nil)
+
+	test.RequireDeepEqual(t, []completionItem{
+		{
+			Label: "few", Kind: completionItemKindKeyword,
+			Detail:     "missing cardinal plural category of pl",
+			InsertText: `few {# \"pliku\"}`,
+		},
+		{
+			Label: "many", Kind: completionItemKindKeyword,
+			Detail:     "missing cardinal plural category of pl",
+			InsertText: `many {# \"pliku\"}`,
+		},
+	}, unmarshal[[]completionItem](t, results[between]))
+	test.RequireEqual(t, "[]", string(results[inside]))
+}
+
+func TestSemanticTokens(t *testing.T) {
+	const uri = "file:///en.icu"
+	s := newSession(t, nil)
+	s.open(uri, "Hi {name}!\n{n, plural, offset:1 one {#\nx} other {y}} {d, date, short}")
+	id := s.request("textDocument/semanticTokens/full",
+		documentParams{TextDocument: textDocumentIdentifier{URI: uri}})
+	s.shutdown()
+	results, _, _ := s.run(nil)
+
+	test.RequireDeepEqual(t, []uint32{
+		0, 0, 3, semanticString, 0, // "Hi "
+		0, 4, 4, semanticVariable, 0, // name
+		0, 5, 1, semanticString, 0, // "!" without the line break
+		1, 1, 1, semanticVariable, 0, // n
+		0, 3, 6, semanticKeyword, 0, // plural
+		0, 15, 1, semanticNumber, 0, // 1 of offset:1
+		0, 2, 3, semanticEnumMember, 0, // one
+		0, 5, 1, semanticString, 0, // "#"
+		1, 0, 1, semanticString, 0, // "x"
+		0, 3, 5, semanticEnumMember, 0, // other
+		0, 7, 1, semanticString, 0, // "y"
+		0, 3, 1, semanticString, 0, // " "
+		0, 2, 1, semanticVariable, 0, // d
+		0, 3, 4, semanticType, 0, // date
+		0, 6, 5, semanticModifier, 0, // short
+	}, unmarshal[semanticTokens](t, results[id]).Data)
+}
+
+func TestFormatting(t *testing.T) {
+	s := newSession(t, nil)
+	s.open("file:///en.icu", "{n,plural,one{# file}other{# files}}")
+	s.open("file:///de.json", "{\n"+
+		`  "a": "{n,plural,one{\"#\" Datei}other{# Dateien}}",`+"\n"+
+		`  "b": "{name}",`+"\n"+
+		`  "c": "{"`+"\n"+
+		"}")
+	icu := s.request("textDocument/formatting",
+		documentParams{TextDocument: textDocumentIdentifier{URI: "file:///en.icu"}})
+	catalog := s.request("textDocument/formatting",
+		documentParams{TextDocument: textDocumentIdentifier{URI: "file:///de.json"}})
+	s.shutdown()
+	results, _, _ := s.run(nil)
+
+	test.RequireDeepEqual(t, []textEdit{{
+		Range:   lspRange{Start: position{0, 0}, End: position{0, 36}},
+		NewText: "{n, plural, one {# file} other {# files}}",
+	}}, unmarshal[[]textEdit](t, results[icu]))
+	test.RequireDeepEqual(t, []textEdit{{
+		Range:   lspRange{Start: position{1, 8}, End: position{1, 51}},
+		NewText: `{n, plural, one {\"#\" Datei} other {# Dateien}}`,
+	}}, unmarshal[[]textEdit](t, results[catalog]))
+}
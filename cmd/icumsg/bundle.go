@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/romshark/icumsg/catalog"
+	"golang.org/x/text/language"
+)
+
+const usageBundle = `usage: icumsg bundle [flags] <file or directory>...
+
+Tokenizes the messages of JSON catalogs (.json, .arb) and writes them
+as a binary catalog for catalog.DecodeBinary, for example to embed it
+with go:embed. Directories are searched recursively for catalogs.
+The locale of each catalog is determined from its "@@locale" key
+or the file name.
+
+If any message is invalid the problems are reported on stderr and no
+binary catalog is written.
+
+Exit codes: 0 no problems, 1 problems found, 2 invalid usage or input failure.
+
+flags:
+`
+
+func runBundle(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprint(stderr, usageBundle)
+		fset.PrintDefaults()
+	}
+	fSource := fset.String("source", "en", "source locale of the catalog")
+	fOut := fset.String("o", "", "output file, stdout if empty")
+	if err := fset.Parse(args); err != nil {
+		return exitFailure
+	}
+	if fset.NArg() < 1 {
+		fset.Usage()
+		return exitFailure
+	}
+	c, code := loadCatalog(*fSource, fset.Args(), stderr)
+	if code != exitOK {
+		return code
+	}
+
+	data, err := c.MarshalBinary()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	if *fOut == "" {
+		_, err = stdout.Write(data)
+	} else {
+		err = os.WriteFile(*fOut, data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// loadCatalog loads the catalogs of args with source locale source.
+// Invalid messages are reported on stderr.
+func loadCatalog(source string, args []string, stderr io.Writer) (*catalog.Catalog, int) {
+	tag, err := language.Parse(source)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid source locale %q: %v\n", source, err)
+		return nil, exitFailure
+	}
+	c := catalog.New(tag, nil)
+	for _, arg := range args {
+		if err := addCatalog(c, arg); err != nil {
+			fmt.Fprintln(stderr, err)
+			return nil, exitFailure
+		}
+	}
+	if issues := c.Validate(); len(issues) > 0 {
+		for _, i := range issues {
+			fmt.Fprintln(stderr, i)
+		}
+		return nil, exitProblem
+	}
+	return c, exitOK
+}
+
+// addCatalog adds the catalog file or all catalogs in directory p to c.
+func addCatalog(c *catalog.Catalog, p string) error {
+	info, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return c.LoadFS(os.DirFS(p))
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	return c.AddFile(filepath.Base(p), data, language.Und)
+}
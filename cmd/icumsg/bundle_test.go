@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestBundle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"locales/en.json":   `{"greeting": "Hello {name}!", "nav": {"home": "Home"}}`,
+		"locales/de.arb":    `{"@@locale": "de", "greeting": "Hallo {name}!"}`,
+		"locales/notes.txt": `ignored`,
+	})
+	out := filepath.Join(dir, "messages.bin")
+	code, stdout, stderr := runCmd(t, "bundle", "-o", out, filepath.Join(dir, "locales"))
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, "", stdout)
+	test.RequireEqual(t, exitOK, code)
+
+	data, err := os.ReadFile(out)
+	test.RequireNoErr(t, err)
+	c, err := catalog.DecodeBinary(data, nil)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.English, c.Source())
+	test.RequireDeepEqual(t, []string{"greeting", "nav.home"}, c.IDs(language.English))
+	e, ok := c.Lookup(language.German, "greeting")
+	test.RequireEqual(t, true, ok)
+	test.RequireEqual(t, "Hallo {name}!", e.Message)
+
+	code, stdout, _ = runCmd(t, "bundle", filepath.Join(dir, "locales", "de.arb"))
+	test.RequireEqual(t, exitOK, code)
+	c, err = catalog.DecodeBinary([]byte(stdout), nil)
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, []language.Tag{language.German}, c.Locales())
+}
+
+func TestBundleProblems(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": `{"a": "{n, plural, one {#}}", "b": "OK"}`,
+	})
+	out := filepath.Join(dir, "messages.bin")
+	code, _, stderr := runCmd(t, "bundle", "-o", out, dir)
+	test.RequireEqual(t, exitProblem, code)
+	test.RequireEqual(t, "en: a: missing the mandatory 'other' option\n", stderr)
+	_, err := os.Stat(out)
+	test.RequireEqual(t, true, os.IsNotExist(err))
+}
+
+func TestBundleUsageErr(t *testing.T) {
+	code, _, _ := runCmd(t, "bundle")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, _ = runCmd(t, "bundle", "-source", "???", ".")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, _ = runCmd(t, "bundle", filepath.Join(t.TempDir(), "missing"))
+	test.RequireEqual(t, exitFailure, code)
+
+	dir := writeFiles(t, map[string]string{"welcome.icu": "Hi"})
+	code, _, _ = runCmd(t, "bundle", filepath.Join(dir, "welcome.icu"))
+	test.RequireEqual(t, exitFailure, code)
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/arb"
+	"golang.org/x/text/language"
+)
+
+// Rule IDs of the extraction checks.
+const (
+	RuleCallConstant = "call-constant"
+	RuleCallArgument = "call-argument"
+	RuleDuplicate    = "message-duplicate"
+)
+
+const usageExtract = `usage: icumsg extract [flags] <package directory>...
+
+Extracts messages from calls to translation functions in Go packages
+and writes them as a JSON catalog of the source locale.
+A directory followed by "/..." includes all packages below it.
+
+Translation functions are called with the message ID, the default
+ICU message and the arguments as pairs of name and value:
+
+  T("files", "{n, plural, one {# file} other {# files}}", "n", count)
+
+The ID, the message and the argument names must be constants.
+Functions are specified by name ("T"), by the full name of the package
+function ("example.com/i18n.T") or of the method
+("(*example.com/i18n.Printer).T"). Bare names match all functions
+and methods with that name.
+
+Default messages are validated and the argument names passed must match
+the arguments of the message. If any problems are found they're reported
+on stderr and no catalog is written.
+
+Exit codes: 0 no problems, 1 problems found, 2 invalid usage or input failure.
+
+flags:
+`
+
+type extractor struct {
+	locale   language.Tag
+	funcs    []string
+	fset     *token.FileSet
+	tokenize icumsg.Tokenizer
+	buffer   []icumsg.Token
+
+	diagnostics []Diagnostic
+
+	// messages maps message IDs to extracted messages.
+	messages map[string]extracted
+}
+
+type extracted struct {
+	Text string
+	Pos  token.Position
+}
+
+func runExtract(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("extract", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprint(stderr, usageExtract)
+		fset.PrintDefaults()
+	}
+	fLocale := fset.String("locale", "en", "locale of the default messages")
+	fFunc := fset.String("func", "T", "comma-separated translation functions")
+	fOut := fset.String("o", "", "output file, stdout if empty")
+	if err := fset.Parse(args); err != nil {
+		return exitFailure
+	}
+	if fset.NArg() < 1 {
+		fset.Usage()
+		return exitFailure
+	}
+
+	e := extractor{
+		fset:     token.NewFileSet(),
+		messages: map[string]extracted{},
+	}
+	var err error
+	if e.locale, err = language.Parse(*fLocale); err != nil {
+		fmt.Fprintf(stderr, "invalid locale %q: %v\n", *fLocale, err)
+		return exitFailure
+	}
+	for f := range strings.SplitSeq(*fFunc, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			e.funcs = append(e.funcs, f)
+		}
+	}
+
+	dirs, err := collectPackageDirs(fset.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	for _, dir := range dirs {
+		if err := e.extractDir(dir); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+	}
+
+	if len(e.diagnostics) > 0 {
+		slices.SortStableFunc(e.diagnostics, func(a, b Diagnostic) int {
+			return cmp.Or(
+				strings.Compare(a.File, b.File),
+				cmp.Compare(a.Line, b.Line),
+				cmp.Compare(a.Column, b.Column),
+			)
+		})
+		_ = writeText(stderr, e.diagnostics)
+		return exitProblem
+	}
+
+	f := &arb.File{Locale: e.locale}
+	for _, id := range slices.Sorted(maps.Keys(e.messages)) {
+		f.Messages = append(f.Messages, arb.Message{ID: id, Text: e.messages[id].Text})
+	}
+	w := stdout
+	if *fOut != "" {
+		out, err := os.Create(*fOut)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		defer out.Close()
+		w = out
+	}
+	if err := arb.Write(w, f); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// collectPackageDirs expands arguments ending with "/..." to the
+// directories below them. Directories named "testdata" or "vendor"
+// and those starting with "." or "_" are skipped.
+func collectPackageDirs(args []string) ([]string, error) {
+	var dirs []string
+	for _, arg := range args {
+		root, ok := strings.CutSuffix(filepath.ToSlash(arg), "/...")
+		if !ok {
+			dirs = append(dirs, arg)
+			continue
+		}
+		if root == "" {
+			root = "."
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != root && (name == "testdata" || name == "vendor" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// extractDir parses and type-checks the Go package in dir, ignoring
+// test files and files excluded by build constraints, and extracts its
+// messages. Packages are type-checked with their name as path and
+// imports are loaded from source. Type errors are ignored, calls that
+// can't be resolved are matched by name only.
+func (e *extractor) extractDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	packages := map[string][]*ast.File{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(dir, name); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		f, err := parser.ParseFile(e.fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		packages[f.Name.Name] = append(packages[f.Name.Name], f)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(packages)) {
+		files := packages[name]
+		info := &types.Info{
+			Types: map[ast.Expr]types.TypeAndValue{},
+			Uses:  map[*ast.Ident]types.Object{},
+		}
+		conf := types.Config{
+			Importer: importer.ForCompiler(e.fset, "source", nil),
+			Error:    func(error) {},
+		}
+		_, _ = conf.Check(name, e.fset, files, info)
+		for _, f := range files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				if call, ok := n.(*ast.CallExpr); ok && e.isTranslation(info, call) {
+					e.extractCall(info, call)
+				}
+				return true
+			})
+		}
+	}
+	return nil
+}
+
+// isTranslation reports whether call calls one of the translation functions.
+func (e *extractor) isTranslation(info *types.Info, call *ast.CallExpr) bool {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	case *ast.IndexExpr:
+		// Instantiation of a generic function.
+		return e.isTranslation(info, &ast.CallExpr{Fun: fun.X})
+	default:
+		return false
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	for _, name := range e.funcs {
+		if fn != nil && fn.Origin().FullName() == name {
+			return true
+		}
+		if !strings.ContainsAny(name, "./()*") && id.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *extractor) report(pos token.Pos, rule, id, format string, args ...any) {
+	p := e.fset.Position(pos)
+	e.diagnostics = append(e.diagnostics, Diagnostic{
+		File:      p.Filename,
+		Line:      p.Line,
+		Column:    p.Column,
+		Severity:  SeverityError,
+		Rule:      rule,
+		MessageID: id,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// constantString returns the value of the string constant x.
+func constantString(info *types.Info, x ast.Expr) (string, bool) {
+	if tv, ok := info.Types[x]; ok && tv.Value != nil {
+		if tv.Value.Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(tv.Value), true
+	}
+	// Type checking may have failed, fall back to literals.
+	lit, ok := ast.Unparen(x).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	v := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+	if v.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(v), true
+}
+
+func (e *extractor) extractCall(info *types.Info, call *ast.CallExpr) {
+	if len(call.Args) < 2 {
+		e.report(call.Pos(), RuleCallArgument, "",
+			"translation call without message ID and default message")
+		return
+	}
+	id, ok := constantString(info, call.Args[0])
+	if !ok {
+		e.report(call.Args[0].Pos(), RuleCallConstant, "", "message ID is not a string constant")
+		return
+	}
+	text, ok := constantString(info, call.Args[1])
+	if !ok {
+		e.report(call.Args[1].Pos(), RuleCallConstant, id,
+			"default message is not a string constant")
+		return
+	}
+
+	var err error
+	e.buffer, err = e.tokenize.Tokenize(e.locale, e.buffer[:0], text)
+	if err != nil {
+		e.report(textPos(call.Args[1], e.tokenize.Pos()), RuleSyntax, id, "%v", err)
+		return
+	}
+
+	pos := e.fset.Position(call.Args[0].Pos())
+	if m, ok := e.messages[id]; ok && m.Text != text {
+		e.report(call.Args[1].Pos(), RuleDuplicate, id,
+			"default message differs from the one at %s", m.Pos)
+	} else if !ok {
+		e.messages[id] = extracted{Text: text, Pos: pos}
+	}
+
+	if call.Ellipsis.IsValid() {
+		e.report(call.Ellipsis, RuleCallArgument, id,
+			"arguments passed as a slice can't be verified")
+		return
+	}
+	args := call.Args[2:]
+	if len(args)%2 != 0 {
+		e.report(args[len(args)-1].Pos(), RuleCallArgument, id,
+			"argument %d has no value", len(args)/2+1)
+		args = args[:len(args)-1]
+	}
+
+	expected := map[string]struct{}{}
+	for a := range icumsg.Arguments(text, e.buffer) {
+		expected[a.Name] = struct{}{}
+	}
+	passed := map[string]struct{}{}
+	for i := 0; i < len(args); i += 2 {
+		name, ok := constantString(info, args[i])
+		if !ok {
+			e.report(args[i].Pos(), RuleCallConstant, id, "argument name is not a string constant")
+			continue
+		}
+		if _, ok := passed[name]; ok {
+			e.report(args[i].Pos(), RuleCallArgument, id, "argument %q passed twice", name)
+			continue
+		}
+		passed[name] = struct{}{}
+		if _, ok := expected[name]; !ok {
+			e.report(args[i].Pos(), RuleCallArgument, id,
+				"argument %q not used by the message", name)
+		}
+	}
+	var missing []string
+	for name := range expected {
+		if _, ok := passed[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		e.report(call.Rparen, RuleCallArgument, id,
+			"missing arguments: %s", strings.Join(missing, ", "))
+	}
+}
+
+// textPos returns the position of offset in the message of the string
+// literal x. The position of x is returned for interpreted
+// string literals and expressions other than literals.
+func textPos(x ast.Expr, offset int) token.Pos {
+	lit, ok := ast.Unparen(x).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING || lit.Value[0] != '`' ||
+		strings.Contains(lit.Value, "\r") {
+		return x.Pos()
+	}
+	return lit.Pos() + 1 + token.Pos(offset)
+}
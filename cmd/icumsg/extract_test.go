@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+)
+
+const extractSource = `package app
+
+import "fmt"
+
+const argName = "name"
+
+func T(id, message string, args ...any) string { return fmt.Sprint(id, message, args) }
+
+type Printer struct{}
+
+func (*Printer) T(id, message string, args ...any) string { return "" }
+
+func Pages(p *Printer, name string, n int) {
+	T("greeting", "Hello {name}!", argName, name)
+	T("greeting", "Hello {name}!", "name", name)
+	p.T("files", ` + "`" + `{n, plural,
+		one {# file}
+		other {# files}
+	}` + "`" + `, "n", n)
+	Other("ignored", "{")
+}
+
+func Other(id, message string) {}
+`
+
+func TestExtract(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/app.go":        extractSource,
+		"app/app_test.go":   `package app; func init() { T("test", "{") }`,
+		"app/sub/sub.go":    `package sub; func T(string, string) {}; func f() { T("sub", "Sub") }`,
+		"app/testdata/x.go": `package x; func T(string, string) {}; func f() { T("x", "{") }`,
+	})
+	code, stdout, stderr := runCmd(t, "extract", filepath.Join(dir, "app"))
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, exitOK, code)
+	test.RequireEqual(t, `{
+  "@@locale": "en",
+  "files": "{n, plural,\n\t\tone {# file}\n\t\tother {# files}\n\t}",
+  "greeting": "Hello {name}!"
+}
+`, stdout)
+
+	// Only the method of Printer.
+	out := filepath.Join(dir, "en.json")
+	code, _, stderr = runCmd(t, "extract", "-locale", "en-US",
+		"-func", "(*app.Printer).T", "-o", out, filepath.Join(dir, "app")+"/...")
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, exitOK, code)
+	data, err := os.ReadFile(out)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, `{
+  "@@locale": "en-US",
+  "files": "{n, plural,\n\t\tone {# file}\n\t\tother {# files}\n\t}"
+}
+`, string(data))
+
+	// Packages below the directory.
+	code, stdout, _ = runCmd(t, "extract", "-func", "app.T,T", filepath.Join(dir, "app")+"/...")
+	test.RequireEqual(t, exitOK, code)
+	test.RequireEqual(t, `{
+  "@@locale": "en",
+  "files": "{n, plural,\n\t\tone {# file}\n\t\tother {# files}\n\t}",
+  "greeting": "Hello {name}!",
+  "sub": "Sub"
+}
+`, stdout)
+}
+
+func TestExtractProblems(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app.go": `package app
+
+func T(id, message string, args ...any) string { return "" }
+
+func f(id string, args []any) {
+	T(id, "x")
+	T("a", "{x} {y}", "x", 1, "z", 2, "x", 3)
+	T("a", "other")
+	T("b", "{n, plural, one {#} other {#}", "n")
+	T("c", ` + "`{n, plural,\n one {#} few {#} other {#}}`" + `, "n", 1)
+	T("d", "{x}", args...)
+	T("e", "x", id, 1)
+	T("f")
+}
+`,
+	})
+	code, stdout, stderr := runCmd(t, "extract", dir)
+	test.RequireEqual(t, "", stdout)
+	test.RequireEqual(t, exitProblem, code)
+	p := filepath.Join(dir, "app.go")
+	test.RequireEqual(t, ""+
+		p+`:6:4: error: message ID is not a string constant (call-constant)`+"\n"+
+		p+`:7:28: error: a: argument "z" not used by the message (call-argument)`+"\n"+
+		p+`:7:36: error: a: argument "x" passed twice (call-argument)`+"\n"+
+		p+`:7:42: error: a: missing arguments: y (call-argument)`+"\n"+
+		p+`:8:9: error: a: default message differs from the one at `+p+`:7:4 (message-duplicate)`+"\n"+
+		p+`:9:9: error: b: unexpected EOF (syntax)`+"\n"+
+		p+`:11:10: error: c: plural rule unsupported for locale (syntax)`+"\n"+
+		p+`:12:20: error: d: arguments passed as a slice can't be verified (call-argument)`+"\n"+
+		p+`:13:14: error: e: argument name is not a string constant (call-constant)`+"\n"+
+		p+`:14:2: error: translation call without message ID and default message (call-argument)`+"\n",
+		stderr)
+}
+
+func TestExtractUsageErr(t *testing.T) {
+	code, _, _ := runCmd(t, "extract")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, stderr := runCmd(t, "extract", "-locale", "???", ".")
+	test.RequireEqual(t, exitFailure, code)
+	test.RequireEqual(t, true, stderr != "")
+
+	code, _, _ = runCmd(t, "extract", filepath.Join(t.TempDir(), "missing"))
+	test.RequireEqual(t, exitFailure, code)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+)
+
+const usageGenerate = `usage: icumsg generate [flags] <file or directory>...
+
+Tokenizes the messages of JSON catalogs (.json, .arb) and writes Go
+source code containing the messages and their tokens, so messages don't
+need to be tokenized at runtime. The generated function NewCatalog
+returns a catalog of the messages. Directories are searched recursively
+for catalogs.
+
+If any message fails to tokenize for its locale the problems are
+reported on stderr and no code is generated, failing go generate:
+
+  //go:generate go run github.com/romshark/icumsg/cmd/icumsg generate -pkg messages -o messages_gen.go ./locales
+
+Exit codes: 0 no problems, 1 problems found, 2 invalid usage or input failure.
+
+flags:
+`
+
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprint(stderr, usageGenerate)
+		fset.PrintDefaults()
+	}
+	fSource := fset.String("source", "en", "source locale of the catalog")
+	fPkg := fset.String("pkg", "messages", "package name of the generated code")
+	fOut := fset.String("o", "", "output file, stdout if empty")
+	if err := fset.Parse(args); err != nil {
+		return exitFailure
+	}
+	if fset.NArg() < 1 || !token.IsIdentifier(*fPkg) {
+		fset.Usage()
+		return exitFailure
+	}
+	c, code := loadCatalog(*fSource, fset.Args(), stderr)
+	if code != exitOK {
+		return code
+	}
+
+	var b bytes.Buffer
+	generate(&b, *fPkg, c)
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	if *fOut == "" {
+		_, err = stdout.Write(src)
+	} else {
+		err = os.WriteFile(*fOut, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// generate writes the unformatted Go source code of package pkg
+// containing the messages of c.
+func generate(w io.Writer, pkg string, c *catalog.Catalog) {
+	writef := func(format string, args ...any) {
+		fmt.Fprintf(w, format, args...)
+	}
+
+	writef("// Code generated by icumsg generate. DO NOT EDIT.\n\n")
+	writef("package %s\n\n", pkg)
+	writef("import (\n")
+	writef("\"fmt\"\n\n")
+	writef("\"github.com/romshark/icumsg\"\n")
+	writef("\"github.com/romshark/icumsg/catalog\"\n")
+	writef("\"golang.org/x/text/language\"\n")
+	writef(")\n\n")
+
+	writef("// cldrVersion is the CLDR version of the plural rules the messages\n")
+	writef("// were tokenized with.\n")
+	writef("const cldrVersion = %q\n\n", icumsg.CLDRVersion)
+
+	writef("// NewCatalog returns a catalog of the pre-tokenized messages.\n")
+	writef("// plurals is used for messages added later, see catalog.New.\n")
+	writef("// NewCatalog panics if icumsg uses plural rules of another CLDR version,\n")
+	writef("// in which case the code must be generated again.\n")
+	writef("func NewCatalog(plurals icumsg.PluralRuleProvider) *catalog.Catalog {\n")
+	writef("if icumsg.CLDRVersion != cldrVersion {\n")
+	writef("panic(fmt.Errorf(\"%%w: %%q, want %%q\", catalog.ErrCLDRVersion, cldrVersion, icumsg.CLDRVersion))\n")
+	writef("}\n")
+	writef("c := catalog.New(language.MustParse(%q), plurals)\n", c.Source())
+	writef("for _, m := range messages {\n")
+	writef("if err := c.AddTokens(locales[m.locale], m.id, m.message, m.tokens); err != nil {\n")
+	writef("panic(err)\n")
+	writef("}\n}\n")
+	writef("return c\n}\n\n")
+
+	locales := c.Locales()
+	writef("var locales = [...]language.Tag{\n")
+	for _, loc := range locales {
+		writef("language.MustParse(%q),\n", loc)
+	}
+	writef("}\n\n")
+
+	writef("var messages = [...]struct {\n")
+	writef("locale int\n")
+	writef("id, message string\n")
+	writef("tokens []icumsg.Token\n")
+	writef("}{\n")
+	for i, loc := range locales {
+		for _, id := range c.IDs(loc) {
+			e := c.Entry(loc, id)
+			writef("{%d, %q, %q, []icumsg.Token{\n", i, e.ID, e.Message)
+			for _, t := range e.Tokens {
+				writef("{Type: %s, IndexStart: %d, IndexEnd: %d},\n",
+					tokenTypeExpr(t.Type), t.IndexStart, t.IndexEnd)
+			}
+			writef("}},\n")
+		}
+	}
+	writef("}\n")
+}
+
+// tokenTypeExpr returns the Go expression of token type t, the name of
+// its constant or a conversion for token types without name.
+func tokenTypeExpr(t icumsg.TokenType) string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return "icumsg." + name
+	}
+	return fmt.Sprintf("icumsg.TokenType(%d)", t)
+}
+
+// tokenTypeNames maps token types to the names of their constants.
+var tokenTypeNames = map[icumsg.TokenType]string{
+	icumsg.TokenTypeLiteral:          "TokenTypeLiteral",
+	icumsg.TokenTypeSimpleArg:        "TokenTypeSimpleArg",
+	icumsg.TokenTypePluralOffset:     "TokenTypePluralOffset",
+	icumsg.TokenTypeArgName:          "TokenTypeArgName",
+	icumsg.TokenTypeArgTypeNumber:    "TokenTypeArgTypeNumber",
+	icumsg.TokenTypeArgTypeDate:      "TokenTypeArgTypeDate",
+	icumsg.TokenTypeArgTypeTime:      "TokenTypeArgTypeTime",
+	icumsg.TokenTypeArgTypeSpellout:  "TokenTypeArgTypeSpellout",
+	icumsg.TokenTypeArgTypeOrdinal:   "TokenTypeArgTypeOrdinal",
+	icumsg.TokenTypeArgTypeDuration:  "TokenTypeArgTypeDuration",
+	icumsg.TokenTypeArgStyleShort:    "TokenTypeArgStyleShort",
+	icumsg.TokenTypeArgStyleMedium:   "TokenTypeArgStyleMedium",
+	icumsg.TokenTypeArgStyleLong:     "TokenTypeArgStyleLong",
+	icumsg.TokenTypeArgStyleFull:     "TokenTypeArgStyleFull",
+	icumsg.TokenTypeArgStyleInteger:  "TokenTypeArgStyleInteger",
+	icumsg.TokenTypeArgStyleCurrency: "TokenTypeArgStyleCurrency",
+	icumsg.TokenTypeArgStylePercent:  "TokenTypeArgStylePercent",
+	icumsg.TokenTypeArgStyleCustom:   "TokenTypeArgStyleCustom",
+	icumsg.TokenTypeArgStyleSkeleton: "TokenTypeArgStyleSkeleton",
+	icumsg.TokenTypeOptionName:       "TokenTypeOptionName",
+	icumsg.TokenTypePlural:           "TokenTypePlural",
+	icumsg.TokenTypeSelect:           "TokenTypeSelect",
+	icumsg.TokenTypeSelectOrdinal:    "TokenTypeSelectOrdinal",
+	icumsg.TokenTypeOption:           "TokenTypeOption",
+	icumsg.TokenTypeOptionZero:       "TokenTypeOptionZero",
+	icumsg.TokenTypeOptionOne:        "TokenTypeOptionOne",
+	icumsg.TokenTypeOptionTwo:        "TokenTypeOptionTwo",
+	icumsg.TokenTypeOptionFew:        "TokenTypeOptionFew",
+	icumsg.TokenTypeOptionMany:       "TokenTypeOptionMany",
+	icumsg.TokenTypeOptionOther:      "TokenTypeOptionOther",
+	icumsg.TokenTypeOptionNumber:     "TokenTypeOptionNumber",
+	icumsg.TokenTypeOptionTerm:       "TokenTypeOptionTerm",
+	icumsg.TokenTypeComplexArgTerm:   "TokenTypeComplexArgTerm",
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"go/parser"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": `{"greeting": "Hello {name}!"}`,
+		"de.json": `{"greeting": "Hallo \"{name}\"!"}`,
+	})
+	out := filepath.Join(dir, "messages_gen.go")
+	code, _, stderr := runCmd(t, "generate", "-pkg", "i18n", "-o", out, dir)
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, exitOK, code)
+	src, err := os.ReadFile(out)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, `// Code generated by icumsg generate. DO NOT EDIT.
+
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"golang.org/x/text/language"
+)
+
+// cldrVersion is the CLDR version of the plural rules the messages
+// were tokenized with.
+const cldrVersion = "`+icumsg.CLDRVersion+`"
+
+// NewCatalog returns a catalog of the pre-tokenized messages.
+// plurals is used for messages added later, see catalog.New.
+// NewCatalog panics if icumsg uses plural rules of another CLDR version,
+// in which case the code must be generated again.
+func NewCatalog(plurals icumsg.PluralRuleProvider) *catalog.Catalog {
+	if icumsg.CLDRVersion != cldrVersion {
+		panic(fmt.Errorf("%w: %q, want %q", catalog.ErrCLDRVersion, cldrVersion, icumsg.CLDRVersion))
+	}
+	c := catalog.New(language.MustParse("en"), plurals)
+	for _, m := range messages {
+		if err := c.AddTokens(locales[m.locale], m.id, m.message, m.tokens); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+var locales = [...]language.Tag{
+	language.MustParse("de"),
+	language.MustParse("en"),
+}
+
+var messages = [...]struct {
+	locale      int
+	id, message string
+	tokens      []icumsg.Token
+}{
+	{0, "greeting", "Hallo \"{name}\"!", []icumsg.Token{
+		{Type: icumsg.TokenTypeLiteral, IndexStart: 0, IndexEnd: 7},
+		{Type: icumsg.TokenTypeSimpleArg, IndexStart: 7, IndexEnd: 13},
+		{Type: icumsg.TokenTypeArgName, IndexStart: 8, IndexEnd: 12},
+		{Type: icumsg.TokenTypeLiteral, IndexStart: 13, IndexEnd: 15},
+	}},
+	{1, "greeting", "Hello {name}!", []icumsg.Token{
+		{Type: icumsg.TokenTypeLiteral, IndexStart: 0, IndexEnd: 6},
+		{Type: icumsg.TokenTypeSimpleArg, IndexStart: 6, IndexEnd: 12},
+		{Type: icumsg.TokenTypeArgName, IndexStart: 7, IndexEnd: 11},
+		{Type: icumsg.TokenTypeLiteral, IndexStart: 12, IndexEnd: 13},
+	}},
+}
+`, string(src))
+}
+
+func TestGenerateTokenTypeExpr(t *testing.T) {
+	for i := range 256 {
+		tp := icumsg.TokenType(i)
+		expr := tokenTypeExpr(tp)
+		if _, err := parser.ParseExpr(expr); err != nil {
+			t.Errorf("token type %d: invalid expression %q: %v", i, expr, err)
+		}
+		_, named := tokenTypeNames[tp]
+		if known := tp.String() != "unknown"; known != named {
+			t.Errorf("token type %d (%s): has name: %t", i, tp, named)
+		}
+	}
+	test.RequireEqual(t, "icumsg.TokenTypeLiteral", tokenTypeExpr(icumsg.TokenTypeLiteral))
+	test.RequireEqual(t, "icumsg.TokenType(0)", tokenTypeExpr(0))
+	test.RequireEqual(t, "icumsg.TokenType(255)", tokenTypeExpr(255))
+}
+
+func TestGenerateProblems(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": `{"a": "OK"}`,
+		"de.json": `{"a": "{n, plural, few {#} other {#}}"}`,
+	})
+	out := filepath.Join(dir, "messages_gen.go")
+	code, _, stderr := runCmd(t, "generate", "-o", out, dir)
+	test.RequireEqual(t, exitProblem, code)
+	test.RequireEqual(t, "de: a: plural rule unsupported for locale\n", stderr)
+	_, err := os.Stat(out)
+	test.RequireEqual(t, true, os.IsNotExist(err))
+}
+
+func TestGenerateUsageErr(t *testing.T) {
+	code, _, _ := runCmd(t, "generate")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, _ = runCmd(t, "generate", "-pkg", "not-an-identifier", ".")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, _ = runCmd(t, "generate", filepath.Join(t.TempDir(), "missing"))
+	test.RequireEqual(t, exitFailure, code)
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/msgfile"
+	"github.com/romshark/icumsg/lint"
+	"golang.org/x/text/language"
+)
+
+// Severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Rule IDs of the built-in checks.
+const (
+	RuleSyntax           = "syntax"
+	RuleIncomplete       = "incomplete"
+	RuleArgumentType     = "argument-type"
+	RuleArgumentUnknown  = "argument-unknown"
+	RuleArgumentMissing  = "argument-missing"
+	RuleMessageUntracked = "message-untracked"
+)
+
+// Diagnostic is a problem found by lint.
+type Diagnostic struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Severity  string `json:"severity"`
+	Rule      string `json:"rule"`
+	MessageID string `json:"messageId,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d:%d: %s: ", d.File, d.Line, d.Column, d.Severity)
+	if d.MessageID != "" {
+		fmt.Fprintf(&b, "%s: ", d.MessageID)
+	}
+	fmt.Fprintf(&b, "%s (%s)", d.Message, d.Rule)
+	return b.String()
+}
+
+const usageLint = `usage: icumsg lint [flags] <file or directory>...
+
+Checks ICU message files (.icu, .icu.txt) and JSON catalogs (.json, .arb).
+Directories are searched recursively for files of these extensions,
+other files are checked as ICU message files only if named explicitly.
+The locale of each file is determined from the catalog's "@@locale" key
+or the file name (for example "de-AT.json" or "welcome.fr.icu")
+unless -locale is specified.
+
+In addition to the built-in checks, the rules of package lint are run.
+Rules are configured with a JSON file passed with -config and can be
+suppressed per message by adding "icumsg-lint-ignore" or
+"icumsg-lint-ignore: rule-a, rule-b" to the "description" of the
+message metadata ("@" followed by the message key).
+
+Exit codes: 0 no problems, 1 problems found, 2 invalid usage or input failure.
+
+flags:
+`
+
+type linter struct {
+	locale   language.Tag
+	source   language.Tag
+	strict   bool
+	tokenize icumsg.Tokenizer
+	buffer   []icumsg.Token
+	rules    *lint.Linter
+
+	diagnostics []Diagnostic
+
+	// sourceArgs maps message IDs to argument names of the source locale.
+	sourceArgs map[string]map[string]struct{}
+}
+
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	fset.Usage = func() {
+		fmt.Fprint(stderr, usageLint)
+		fset.PrintDefaults()
+	}
+	fLocale := fset.String("locale", "",
+		"locale of all files, overrides the locale determined automatically")
+	fSource := fset.String("source", "",
+		"source locale to check the arguments of translations against")
+	fFormat := fset.String("format", "text", "output format: text, json or sarif")
+	fStrict := fset.Bool("strict", false, "exit with code 1 on warnings")
+	fConfig := fset.String("config", "", "lint rule configuration file (JSON)")
+	if err := fset.Parse(args); err != nil {
+		return exitFailure
+	}
+	if fset.NArg() < 1 {
+		fset.Usage()
+		return exitFailure
+	}
+
+	var l linter
+	l.strict = *fStrict
+	for _, f := range [...]struct {
+		Value string
+		Dst   *language.Tag
+	}{{*fLocale, &l.locale}, {*fSource, &l.source}} {
+		if f.Value == "" {
+			continue
+		}
+		var err error
+		if *f.Dst, err = language.Parse(f.Value); err != nil {
+			fmt.Fprintf(stderr, "invalid locale %q: %v\n", f.Value, err)
+			return exitFailure
+		}
+	}
+
+	var config lint.Config
+	if *fConfig != "" {
+		var err error
+		if config, err = lint.LoadConfig(*fConfig); err != nil {
+			fmt.Fprintf(stderr, "loading config: %v\n", err)
+			return exitFailure
+		}
+	}
+	var err error
+	if l.rules, err = lint.New(config); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+
+	var write func(io.Writer, []Diagnostic) error
+	switch *fFormat {
+	case "text":
+		write = writeText
+	case "json":
+		write = writeJSON
+	case "sarif":
+		write = func(w io.Writer, d []Diagnostic) error {
+			return writeSARIF(w, d, l.rules.Rules())
+		}
+	default:
+		fmt.Fprintf(stderr, "unsupported format: %q\n", *fFormat)
+		return exitFailure
+	}
+
+	files, err := collectFiles(fset.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+
+	parsed := make([]*msgfile.File, 0, len(files))
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		f, err := msgfile.Parse(name, data, l.locale)
+		if err != nil {
+			if e := (*msgfile.Error)(nil); errors.As(err, &e) {
+				l.diagnostics = append(l.diagnostics, Diagnostic{
+					File: name, Line: e.Pos.Line, Column: e.Pos.Column,
+					Severity: SeverityError, Rule: RuleSyntax, Message: e.Msg,
+				})
+				continue
+			}
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		parsed = append(parsed, f)
+	}
+
+	l.collectSourceArgs(parsed)
+	for _, f := range parsed {
+		l.lintFile(f)
+	}
+
+	slices.SortStableFunc(l.diagnostics, func(a, b Diagnostic) int {
+		return cmp.Or(
+			strings.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column),
+		)
+	})
+	if err := write(stdout, l.diagnostics); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	for _, d := range l.diagnostics {
+		if d.Severity == SeverityError || (l.strict && d.Severity == SeverityWarning) {
+			return exitProblem
+		}
+	}
+	return exitOK
+}
+
+// collectFiles expands directories to the message files they contain.
+func collectFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isMessageFile(path) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// isMessageFile returns true if the file at path is an ICU message file
+// (.icu, .icu.txt) or a JSON catalog (.json, .arb).
+func isMessageFile(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	if msgfile.IsCatalog(name) {
+		return true
+	}
+	return strings.HasSuffix(name, ".icu") || strings.HasSuffix(name, ".icu.txt")
+}
+
+func (l *linter) report(
+	f *msgfile.File, m *msgfile.Message, offset int, severity, rule, msg string,
+) {
+	pos := f.Position(m.FileOffset(offset))
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		File:      f.Name,
+		Line:      pos.Line,
+		Column:    pos.Column,
+		Severity:  severity,
+		Rule:      rule,
+		MessageID: m.ID,
+		Message:   msg,
+	})
+}
+
+// collectSourceArgs collects the arguments of all messages of the source locale.
+func (l *linter) collectSourceArgs(files []*msgfile.File) {
+	if l.source == language.Und {
+		return
+	}
+	l.sourceArgs = map[string]map[string]struct{}{}
+	for _, f := range files {
+		if f.Locale != l.source {
+			continue
+		}
+		for _, m := range f.Messages {
+			var err error
+			l.buffer, err = l.tokenize.Tokenize(f.Locale, l.buffer[:0], m.Text)
+			if err != nil {
+				continue // Reported by lintFile.
+			}
+			args := map[string]struct{}{}
+			for a := range icumsg.Arguments(m.Text, l.buffer) {
+				args[a.Name] = struct{}{}
+			}
+			l.sourceArgs[m.ID] = args
+		}
+	}
+}
+
+func (l *linter) lintFile(f *msgfile.File) {
+	for i := range f.Messages {
+		m := &f.Messages[i]
+		var err error
+		l.buffer, err = l.tokenize.Tokenize(f.Locale, l.buffer[:0], m.Text)
+		if err != nil {
+			l.report(f, m, l.tokenize.Pos(), SeverityError, RuleSyntax, err.Error())
+			continue
+		}
+		l.checkCompleteness(f, m)
+		l.checkArgumentTypes(f, m)
+		l.checkSourceArgs(f, m)
+		l.checkRules(f, m)
+	}
+}
+
+// checkRules runs the rules of package lint.
+func (l *linter) checkRules(f *msgfile.File, m *msgfile.Message) {
+	for _, d := range l.rules.Lint(lint.Message{
+		ID:      m.ID,
+		Locale:  f.Locale,
+		Source:  m.Text,
+		Buffer:  l.buffer,
+		Comment: m.Comment,
+	}) {
+		l.report(f, m, d.Start, d.Severity.String(), d.Rule, d.Message)
+	}
+}
+
+func (l *linter) checkCompleteness(f *msgfile.File, m *msgfile.Message) {
+	r := icumsg.NewCompletenessReport(m.Text, l.buffer, f.Locale, nil,
+		func(string) ([]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy) {
+			return nil, 0, 0
+		})
+	for _, c := range r.Choices {
+		if c.Complete {
+			continue
+		}
+		l.report(f, m, c.Start, SeverityWarning, RuleIncomplete, fmt.Sprintf(
+			"incomplete %s argument %q: missing %s",
+			c.Kind, c.Arg, strings.Join(c.MissingCategories, ", "),
+		))
+	}
+}
+
+func (l *linter) checkArgumentTypes(f *msgfile.File, m *msgfile.Message) {
+	classes := map[string]icumsg.ArgumentClass{}
+	for a := range icumsg.Arguments(m.Text, l.buffer) {
+		class := a.Class()
+		if class == icumsg.ArgumentClassAny {
+			continue
+		}
+		if c, ok := classes[a.Name]; !ok {
+			classes[a.Name] = class
+		} else if c != class {
+			l.report(f, m, l.buffer[a.Index].IndexStart, SeverityError, RuleArgumentType,
+				fmt.Sprintf("argument %q used as %s but previously as %s",
+					a.Name, class, c))
+		}
+	}
+}
+
+func (l *linter) checkSourceArgs(f *msgfile.File, m *msgfile.Message) {
+	if l.sourceArgs == nil || f.Locale == l.source || m.ID == "" {
+		// Single-message files can't be matched with source messages.
+		return
+	}
+	sourceArgs, ok := l.sourceArgs[m.ID]
+	if !ok {
+		l.report(f, m, 0, SeverityWarning, RuleMessageUntracked,
+			fmt.Sprintf("message not found in source locale %s", l.source))
+		return
+	}
+	seen := map[string]struct{}{}
+	for a := range icumsg.Arguments(m.Text, l.buffer) {
+		seen[a.Name] = struct{}{}
+		if _, ok := sourceArgs[a.Name]; !ok {
+			l.report(f, m, l.buffer[a.Index].IndexStart, SeverityError,
+				RuleArgumentUnknown, fmt.Sprintf(
+					"argument %q not found in source locale %s", a.Name, l.source,
+				))
+		}
+	}
+	var missing []string
+	for name := range sourceArgs {
+		if _, ok := seen[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		l.report(f, m, 0, SeverityWarning, RuleArgumentMissing, fmt.Sprintf(
+			"missing arguments of source locale %s: %s",
+			l.source, strings.Join(missing, ", "),
+		))
+	}
+}
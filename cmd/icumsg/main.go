@@ -0,0 +1,59 @@
+// Command icumsg provides tooling for ICU messages.
+//
+// Usage:
+//
+//	icumsg <command> [flags] [arguments]
+//
+// Commands:
+//
+//	lint     check message files and catalogs
+//	extract  extract messages from Go source code
+//	bundle   write catalogs as a binary catalog
+//	generate generate Go code of pre-tokenized messages
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes.
+const (
+	exitOK      = 0 // No problems found.
+	exitProblem = 1 // Problems were found.
+	exitFailure = 2 // Invalid usage or failure reading input.
+)
+
+func main() { os.Exit(run(os.Args[1:], os.Stdout, os.Stderr)) }
+
+const usage = `usage: icumsg <command> [flags] [arguments]
+
+commands:
+  lint     check message files and catalogs
+  extract  extract messages from Go source code
+  bundle   write catalogs as a binary catalog
+  generate generate Go code of pre-tokenized messages
+`
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return exitFailure
+	}
+	switch args[0] {
+	case "lint":
+		return runLint(args[1:], stdout, stderr)
+	case "extract":
+		return runExtract(args[1:], stdout, stderr)
+	case "bundle":
+		return runBundle(args[1:], stdout, stderr)
+	case "generate":
+		return runGenerate(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "unknown command: %q\n\n%s", args[0], usage)
+	return exitFailure
+}
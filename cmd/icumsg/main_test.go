@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/lint"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		test.RequireNoErr(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		test.RequireNoErr(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func runCmd(t *testing.T, args ...string) (exitCode int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	exitCode = run(args, &out, &errOut)
+	return exitCode, out.String(), errOut.String()
+}
+
+func TestRunUsage(t *testing.T) {
+	code, _, stderr := runCmd(t)
+	test.RequireEqual(t, exitFailure, code)
+	test.RequireEqual(t, usage, stderr)
+
+	code, stdout, _ := runCmd(t, "help")
+	test.RequireEqual(t, exitOK, code)
+	test.RequireEqual(t, usage, stdout)
+
+	code, _, stderr = runCmd(t, "unknown")
+	test.RequireEqual(t, exitFailure, code)
+	test.RequireEqual(t, "unknown command: \"unknown\"\n\n"+usage, stderr)
+}
+
+func TestLintOK(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": `{"greeting": "Hello {name}!",
+			"files": "{n, plural, one{# file} other{# files}}"}`,
+		"welcome.de.icu": "Willkommen {name}!",
+	})
+	code, stdout, stderr := runCmd(t, "lint", dir)
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, "", stdout)
+	test.RequireEqual(t, exitOK, code)
+}
+
+func TestLintText(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": "{\n" +
+			`  "greeting": "Hello {name}!",` + "\n" +
+			`  "files": "{n, plural, one{# file} other{# files}}",` + "\n" +
+			`  "broken": "{n, plural, one{# file} few{# files} other{# files}}"` + "\n" +
+			"}",
+		"pl.json": "{\n" +
+			`  "greeting": "Cześć {name} {extra}!",` + "\n" +
+			`  "files": "{n, plural, one{# plik} other{# pliki}}",` + "\n" +
+			`  "new": "Nowy"` + "\n" +
+			"}",
+		"welcome.de.icu": "{d, date} und\n{d, number}",
+		"invalid.json":   `{"a": "b" "c"}`,
+	})
+	code, stdout, stderr := runCmd(t, "lint", "-source", "en", dir)
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, exitProblem, code)
+
+	rel := func(name string) string { return filepath.Join(dir, name) }
+	test.RequireEqual(t, ""+
+		rel("en.json")+`:4:38: error: broken: plural rule unsupported for locale (syntax)`+"\n"+
+		rel("invalid.json")+`:1:11: error: expected ',' (syntax)`+"\n"+
+		rel("pl.json")+`:2:29: error: greeting: argument "extra" not found in source locale en (argument-unknown)`+"\n"+
+		rel("pl.json")+`:3:13: warning: files: incomplete plural argument "n": missing few, many (incomplete)`+"\n"+
+		rel("pl.json")+`:4:11: warning: new: message not found in source locale en (message-untracked)`+"\n"+
+		rel("welcome.de.icu")+`:2:1: error: argument "d" used as number but previously as date (argument-type)`+"\n",
+		stdout)
+}
+
+func TestLintMixedFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json":                 `{"a": "{n, plural, other{#}"}`,
+		"app_en.arb":              `{"b": "{n, plural, other{#}"}`,
+		"c.en.icu":                "{n, plural, other{#}",
+		"d.en.icu.txt":            "{n, plural, other{#}",
+		"nested/E.EN.ICU":         "{n, plural, other{#}",
+		"notes.en.txt":            "{ not a message",
+		"README.md":               "{ not a message",
+		"main.go":                 "package main",
+		"nested/messages.en.yaml": "{ not a message",
+	})
+	code, stdout, stderr := runCmd(t, "lint", dir)
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, exitProblem, code)
+
+	rel := func(name string) string { return filepath.Join(dir, name) }
+	test.RequireEqual(t, ""+
+		rel("app_en.arb")+`:1:28: error: b: unexpected EOF (syntax)`+"\n"+
+		rel("c.en.icu")+`:1:21: error: unexpected EOF (syntax)`+"\n"+
+		rel("d.en.icu.txt")+`:1:21: error: unexpected EOF (syntax)`+"\n"+
+		rel("en.json")+`:1:28: error: a: unexpected EOF (syntax)`+"\n"+
+		rel("nested/E.EN.ICU")+`:1:21: error: unexpected EOF (syntax)`+"\n",
+		stdout)
+
+	// Files named explicitly are checked regardless of their extension.
+	code, stdout, _ = runCmd(t, "lint", rel("notes.en.txt"))
+	test.RequireEqual(t, exitProblem, code)
+	test.RequireEqual(t, true, stdout != "")
+}
+
+func TestLintWarningsStrict(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"pl.json": `{"files": "{n, plural, one{# plik} other{# pliki}}"}`,
+	})
+	code, _, _ := runCmd(t, "lint", dir)
+	test.RequireEqual(t, exitOK, code)
+
+	code, _, _ = runCmd(t, "lint", "-strict", dir)
+	test.RequireEqual(t, exitProblem, code)
+}
+
+func TestLintLocaleOverride(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"messages.json": `{"files": "{n, plural, one{# file} other{# files}}"}`,
+	})
+	code, _, stderr := runCmd(t, "lint", dir)
+	test.RequireEqual(t, exitFailure, code)
+	test.RequireEqual(t,
+		filepath.Join(dir, "messages.json")+": unable to determine locale\n", stderr)
+
+	code, stdout, stderr := runCmd(t, "lint", "-locale", "en", dir)
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, "", stdout)
+	test.RequireEqual(t, exitOK, code)
+}
+
+func TestLintJSON(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": `{"x": "{n, plural, other{# files}}"}`,
+	})
+	code, stdout, _ := runCmd(t, "lint", "-format", "json", dir)
+	test.RequireEqual(t, exitOK, code)
+
+	var d []Diagnostic
+	test.RequireNoErr(t, json.Unmarshal([]byte(stdout), &d))
+	test.RequireDeepEqual(t, []Diagnostic{{
+		File: filepath.Join(dir, "en.json"), Line: 1, Column: 8,
+		Severity: SeverityWarning, Rule: RuleIncomplete, MessageID: "x",
+		Message: `incomplete plural argument "n": missing one`,
+	}}, d)
+
+	dir = writeFiles(t, map[string]string{"en.json": `{"x": "ok"}`})
+	code, stdout, _ = runCmd(t, "lint", "-format", "json", dir)
+	test.RequireEqual(t, exitOK, code)
+	test.RequireEqual(t, "[]\n", stdout)
+}
+
+func TestLintSARIF(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": `{"x": "{n, plural, other{# files}"}`,
+	})
+	code, stdout, _ := runCmd(t, "lint", "-format", "sarif", dir)
+	test.RequireEqual(t, exitProblem, code)
+
+	var log sarifLog
+	test.RequireNoErr(t, json.Unmarshal([]byte(stdout), &log))
+	test.RequireEqual(t, "2.1.0", log.Version)
+	test.RequireEqual(t, 1, len(log.Runs))
+	test.RequireEqual(t, "icumsg", log.Runs[0].Tool.Driver.Name)
+	test.RequireEqual(t, len(ruleDescriptions)+len(lint.DefaultRules()),
+		len(log.Runs[0].Tool.Driver.Rules))
+	for _, r := range log.Runs[0].Tool.Driver.Rules {
+		// Built-in and package lint rules are described alike.
+		d := r.ShortDescription.Text
+		if d == "" || d[0] < 'A' || d[0] > 'Z' || !strings.HasSuffix(d, ".") {
+			t.Errorf("rule %s: description %q isn't a capitalized sentence", r.ID, d)
+		}
+	}
+	test.RequireDeepEqual(t, []sarifResult{{
+		RuleID:  RuleSyntax,
+		Level:   SeverityError,
+		Message: sarifMessage{Text: "x: unexpected EOF"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{
+				URI: filepath.ToSlash(filepath.Join(dir, "en.json")),
+			},
+			Region: sarifRegion{StartLine: 1, StartColumn: 34},
+		}}},
+	}}, log.Runs[0].Results)
+}
+
+func TestLintRules(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"en.json": "{\n" +
+			`  "a": "{user_name} has {n, plural, =1 {a file} one {# file} other {# files}}",` + "\n" +
+			`  "b": "{user_name}",` + "\n" +
+			`  "@b": {"description": "icumsg-lint-ignore: arg-name-case"}` + "\n" +
+			"}",
+		"icumsg-lint.json": `{"rules": {"prefer-one": {"severity": "error"}}}`,
+	})
+	code, stdout, stderr := runCmd(t, "lint", filepath.Join(dir, "en.json"))
+	test.RequireEqual(t, "", stderr)
+	test.RequireEqual(t, exitOK, code)
+	p := filepath.Join(dir, "en.json")
+	test.RequireEqual(t, ""+
+		p+`:2:10: warning: a: argument name "user_name" is not camel case (arg-name-case)`+"\n"+
+		p+`:2:37: warning: a: use "one" instead of "=1" (prefer-one)`+"\n",
+		stdout)
+
+	code, stdout, _ = runCmd(t, "lint",
+		"-config", filepath.Join(dir, "icumsg-lint.json"), filepath.Join(dir, "en.json"))
+	test.RequireEqual(t, exitProblem, code)
+	test.RequireEqual(t, ""+
+		p+`:2:10: warning: a: argument name "user_name" is not camel case (arg-name-case)`+"\n"+
+		p+`:2:37: error: a: use "one" instead of "=1" (prefer-one)`+"\n",
+		stdout)
+}
+
+func TestLintUsageErr(t *testing.T) {
+	code, _, _ := runCmd(t, "lint")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, stderr := runCmd(t, "lint", "-format", "xml", ".")
+	test.RequireEqual(t, exitFailure, code)
+	test.RequireEqual(t, "unsupported format: \"xml\"\n", stderr)
+
+	code, _, stderr = runCmd(t, "lint", "-locale", "???", ".")
+	test.RequireEqual(t, exitFailure, code)
+
+	code, _, _ = runCmd(t, "lint", filepath.Join(t.TempDir(), "missing.json"))
+	test.RequireEqual(t, exitFailure, code)
+
+	dir := writeFiles(t, map[string]string{
+		"config.json": `{"rules": {"unknown": {"severity": "off"}}}`,
+	})
+	code, _, stderr = runCmd(t, "lint", "-config", filepath.Join(dir, "config.json"), ".")
+	test.RequireEqual(t, exitFailure, code)
+	test.RequireEqual(t, "unknown rule: \"unknown\"\n", stderr)
+}
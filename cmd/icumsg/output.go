@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/romshark/icumsg/lint"
+)
+
+func writeText(w io.Writer, diagnostics []Diagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, diagnostics []Diagnostic) error {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(diagnostics)
+}
+
+// ruleDescriptions describes the rules for the SARIF output.
+var ruleDescriptions = map[string]string{
+	RuleSyntax:           "The message or catalog is syntactically invalid.",
+	RuleIncomplete:       "A plural or selectordinal lacks CLDR categories of its locale.",
+	RuleArgumentType:     "An argument is used with incompatible types.",
+	RuleArgumentUnknown:  "A translation uses an argument the source message doesn't have.",
+	RuleArgumentMissing:  "A translation lacks arguments of the source message.",
+	RuleMessageUntracked: "A translation has no message in the source locale.",
+}
+
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+	}
+)
+
+// sarifLevel returns the SARIF level of a severity.
+func sarifLevel(severity string) string {
+	if severity == SeverityInfo {
+		return "note"
+	}
+	return severity
+}
+
+// writeSARIF writes diagnostics in the SARIF 2.1.0 format.
+// rules are described in addition to the built-in checks.
+func writeSARIF(w io.Writer, diagnostics []Diagnostic, rules []lint.Rule) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "icumsg",
+			InformationURI: "https://github.com/romshark/icumsg",
+		}},
+		Results: []sarifResult{},
+	}
+	descriptions := maps.Clone(ruleDescriptions)
+	for _, r := range rules {
+		descriptions[r.ID()] = r.Description()
+	}
+	for _, id := range slices.Sorted(maps.Keys(descriptions)) {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID: id, ShortDescription: sarifMessage{Text: descriptions[id]},
+		})
+	}
+	for _, d := range diagnostics {
+		msg := d.Message
+		if d.MessageID != "" {
+			msg = d.MessageID + ": " + msg
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifMessage{Text: msg},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(d.File)},
+				Region:           sarifRegion{StartLine: d.Line, StartColumn: d.Column},
+			}}},
+		})
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,346 @@
+package icumsg
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// OptionsPresencePolicy defines treatment of known options.
+type OptionsPresencePolicy int8
+
+const (
+	// OptionsPresencePolicyOptional does not require all select options to be present
+	// for the ICU message to be considered complete.
+	OptionsPresencePolicyOptional = iota
+
+	// OptionsPresencePolicyRequired requires all select options to be present
+	// for the ICU message to be considered complete.
+	OptionsPresencePolicyRequired
+)
+
+// OptionUnknownPolicy defines treatment of unknown select options.
+type OptionUnknownPolicy int8
+
+const (
+	// OptionUnknownPolicyIgnore ignores unknown select options.
+	OptionUnknownPolicyIgnore OptionUnknownPolicy = iota
+	// OptionUnknownPolicyReject rejects unknown select options.
+	OptionUnknownPolicyReject
+)
+
+// Completeness returns the total number of choices in src.
+// The contents of every option of every choice are checked recursively.
+// onIncomplete is invoked when an incomplete, select, plural or selectordinal
+// is encountered.
+// onRejected is invoked when an unknown select option was encountered.
+// selectOptions is invoked when a select is encountered and if it returns
+// a slice then those will be the expected options the presence of which
+// will define whether the select is complete (depending on the policies returned).
+// selectOptions is not invoked for plural and selectordinal, instead locale is used
+// to determine what options are required.
+// The plural rules of locale are taken from CLDRPluralRules,
+// use Tokenizer.Completeness for custom plural rules.
+func Completeness(
+	src string,
+	buffer []Token,
+	locale language.Tag,
+	selectOptions func(argName string) (
+		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
+	),
+	onIncomplete func(index int),
+	onRejected func(index int),
+) (total int) {
+	return completeness(src, buffer, locale, nil,
+		selectOptions, onIncomplete, onRejected)
+}
+
+// Completeness is like the function Completeness but takes the plural
+// rules of locale from t.Plurals.
+func (t *Tokenizer) Completeness(
+	src string,
+	buffer []Token,
+	locale language.Tag,
+	selectOptions func(argName string) (
+		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
+	),
+	onIncomplete func(index int),
+	onRejected func(index int),
+) (total int) {
+	return completeness(src, buffer, locale, t.Plurals,
+		selectOptions, onIncomplete, onRejected)
+}
+
+func completeness(
+	src string,
+	buffer []Token,
+	locale language.Tag,
+	plurals PluralRuleProvider,
+	selectOptions func(argName string) (
+		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
+	),
+	onIncomplete func(index int),
+	onRejected func(index int),
+) (total int) {
+	c := completenessChecker{
+		src:           src,
+		buffer:        buffer,
+		selectOptions: selectOptions,
+		onIncomplete:  onIncomplete,
+		onRejected:    onRejected,
+	}
+	c.cardinal, c.ordinal = pluralRules(plurals, locale)
+	return c.check(0, len(buffer))
+}
+
+// CompletenessReport is a machine-readable result of a completeness check.
+type CompletenessReport struct {
+	// Total is the total number of choices (select, plural and selectordinal).
+	Total int `json:"total"`
+
+	// Incomplete is the number of incomplete choices.
+	Incomplete int `json:"incomplete"`
+
+	// Percent is the share of complete choices in percent (0-100)
+	// rounded to two decimal places. A message without choices is 100% complete.
+	Percent float64 `json:"percent"`
+
+	// Choices lists all choices in the order of appearance.
+	Choices []ChoiceReport `json:"choices"`
+}
+
+// ChoiceReport describes the completeness of a single
+// select, plural or selectordinal argument.
+type ChoiceReport struct {
+	// Index is the index of the choice token in the token buffer.
+	Index int `json:"index"`
+
+	// Start and End are the byte offsets of the choice in the source string.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// Kind is either "select", "plural" or "selectordinal".
+	Kind string `json:"kind"`
+
+	// Arg is the name of the argument.
+	Arg string `json:"arg"`
+
+	// Path is the path of options leading to this choice.
+	// Path is empty for top-level choices.
+	Path Path `json:"path"`
+
+	// Options lists the names of all present options in the order of appearance,
+	// including "=n" options.
+	Options []string `json:"options"`
+
+	// MissingCategories lists the CLDR plural categories that are required
+	// by the locale but missing. Always empty for select.
+	MissingCategories []string `json:"missingCategories,omitempty"`
+
+	// MissingKeys lists the required select options that are missing.
+	// Always empty for plural and selectordinal.
+	MissingKeys []string `json:"missingKeys,omitempty"`
+
+	// RejectedKeys lists the unknown select options that were rejected.
+	// Always empty for plural and selectordinal.
+	RejectedKeys []string `json:"rejectedKeys,omitempty"`
+
+	// Complete is true when no categories or keys are missing.
+	Complete bool `json:"complete"`
+
+	// Percent is the share of the required options present in percent (0-100)
+	// rounded to two decimal places.
+	Percent float64 `json:"percent"`
+}
+
+// PathElement is a single option of a choice argument on a Path.
+type PathElement struct {
+	Arg    string `json:"arg"`
+	Option string `json:"option"`
+}
+
+// Path is a path of options through nested choice arguments.
+type Path []PathElement
+
+// String returns the path in the form "arg1[option1],arg2[option2]".
+func (p Path) String() string {
+	var b strings.Builder
+	for i, e := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(e.Arg)
+		b.WriteByte('[')
+		b.WriteString(e.Option)
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+// NewCompletenessReport checks the completeness of src the same way
+// Completeness does and returns a detailed report.
+func NewCompletenessReport(
+	src string,
+	buffer []Token,
+	locale language.Tag,
+	plurals PluralRuleProvider,
+	selectOptions func(argName string) (
+		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
+	),
+) CompletenessReport {
+	r := CompletenessReport{Choices: []ChoiceReport{}}
+	c := completenessChecker{
+		src:           src,
+		buffer:        buffer,
+		selectOptions: selectOptions,
+		onIncomplete:  func(int) {},
+		onRejected:    func(int) {},
+		report:        &r,
+	}
+	c.cardinal, c.ordinal = pluralRules(plurals, locale)
+	r.Total = c.check(0, len(buffer))
+	for _, c := range r.Choices {
+		if !c.Complete {
+			r.Incomplete++
+		}
+	}
+	r.Percent = percent(r.Total-r.Incomplete, r.Total)
+	return r
+}
+
+// percent returns n/total in percent rounded to two decimal places.
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return math.Round(float64(n)/float64(total)*100_00) / 100
+}
+
+type completenessChecker struct {
+	src               string
+	buffer            []Token
+	cardinal, ordinal PluralRules
+	selectOptions     func(argName string) (
+		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
+	)
+	onIncomplete func(index int)
+	onRejected   func(index int)
+
+	// report is optional and nil for Completeness.
+	report *CompletenessReport
+	path   Path
+}
+
+// beginChoice reserves a choice report for the choice at buffer[index]
+// and returns its index in the report or -1 if there's no report.
+func (c *completenessChecker) beginChoice(index int, kind string) int {
+	if c.report == nil {
+		return -1
+	}
+	t := c.buffer[index]
+	ch := ChoiceReport{
+		Index:   index,
+		Start:   t.IndexStart,
+		End:     c.buffer[t.IndexEnd].IndexEnd,
+		Kind:    kind,
+		Arg:     c.buffer[index+1].String(c.src, c.buffer),
+		Path:    append(Path{}, c.path...),
+		Options: []string{},
+	}
+	for j := range Options(c.buffer, index) {
+		ch.Options = append(ch.Options, OptionName(c.src, c.buffer, j))
+	}
+	c.report.Choices = append(c.report.Choices, ch)
+	return len(c.report.Choices) - 1
+}
+
+// checkOption checks the contents of the option at buffer[index] of the
+// choice argument argName.
+func (c *completenessChecker) checkOption(argName string, index int) (total int) {
+	if c.report != nil {
+		c.path = append(c.path, PathElement{Arg: argName, Option: OptionName(c.src, c.buffer, index)})
+		defer func() { c.path = c.path[:len(c.path)-1] }()
+	}
+	return c.check(index, c.buffer[index].IndexEnd)
+}
+
+func (c *completenessChecker) check(startIndex, endIndex int) (total int) {
+	src, buffer := c.src, c.buffer
+
+	for i := startIndex; i < endIndex; i++ {
+		t := buffer[i]
+		switch t.Type {
+		case TokenTypeSelect:
+			total++
+			reportIndex := c.beginChoice(i, "select")
+			argName := buffer[i+1].String(src, buffer)
+			opts, presencePolicy, unknownPolicy := c.selectOptions(argName)
+			var missing, rejected []string
+			present := make([]bool, len(opts))
+			for j := range Options(buffer, i) {
+				if len(opts) != 0 && buffer[j].Type != TokenTypeOptionOther {
+					name := buffer[j+1].String(src, buffer)
+					if index := slices.Index(opts, name); index != -1 {
+						present[index] = true
+					} else if unknownPolicy == OptionUnknownPolicyReject {
+						rejected = append(rejected, name)
+						c.onRejected(j)
+					}
+				}
+				total += c.checkOption(argName, j)
+			}
+			if presencePolicy == OptionsPresencePolicyRequired {
+				for i, ok := range present {
+					if !ok {
+						missing = append(missing, opts[i])
+					}
+				}
+			}
+			if len(missing) != 0 {
+				c.onIncomplete(i)
+			}
+			if reportIndex != -1 {
+				r := &c.report.Choices[reportIndex]
+				r.MissingKeys, r.RejectedKeys = missing, rejected
+				r.Complete = len(missing) == 0
+				r.Percent = 100
+				if presencePolicy == OptionsPresencePolicyRequired && len(opts) != 0 {
+					r.Percent = percent(len(opts)-len(missing), len(opts))
+				}
+			}
+			i = t.IndexEnd // Skip contents, already checked per option.
+		case TokenTypePlural, TokenTypeSelectOrdinal:
+			total++
+			kind, expect := "plural", c.cardinal
+			if t.Type == TokenTypeSelectOrdinal {
+				kind, expect = "selectordinal", c.ordinal
+			}
+			reportIndex := c.beginChoice(i, kind)
+			argName := buffer[i+1].String(src, buffer)
+			var rules PluralRules
+			for j := range Options(buffer, i) {
+				rules.set(buffer[j].Type)
+				total += c.checkOption(argName, j)
+			}
+			if rules != expect {
+				c.onIncomplete(i)
+			}
+			if reportIndex != -1 {
+				r := &c.report.Choices[reportIndex]
+				r.Complete = rules == expect
+				var required int
+				for tp := range expect.all() {
+					required++
+					if !rules.has(tp) {
+						r.MissingCategories = append(r.MissingCategories, categoryName(tp))
+					}
+				}
+				r.Percent = percent(required-len(r.MissingCategories), required)
+			}
+			i = t.IndexEnd // Skip contents, already checked per option.
+		}
+	}
+	return total
+}
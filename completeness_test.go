@@ -0,0 +1,188 @@
+package icumsg_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestNewCompletenessReport(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+
+	fn := func(
+		t *testing.T,
+		locale language.Tag,
+		input string,
+		options map[string][]string,
+		presencePolicy icumsg.OptionsPresencePolicy,
+		unknownPolicy icumsg.OptionUnknownPolicy,
+		expect icumsg.CompletenessReport,
+	) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(locale, nil, input)
+		test.RequireNoErr(t, err)
+		actual := icumsg.NewCompletenessReport(input, buffer, locale, nil,
+			func(argName string) (
+				[]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy,
+			) {
+				return options[argName], presencePolicy, unknownPolicy
+			})
+		test.RequireDeepEqual(t, expect, actual)
+	}
+
+	fn(t, language.English, "No choices", nil, 0, 0,
+		icumsg.CompletenessReport{Percent: 100, Choices: []icumsg.ChoiceReport{}})
+
+	fn(t, language.Polish,
+		"{n, plural, =0{none} one{# a} other{# b}}",
+		nil, 0, 0,
+		icumsg.CompletenessReport{
+			Total: 1, Incomplete: 1, Percent: 0,
+			Choices: []icumsg.ChoiceReport{{
+				Index: 0, Start: 0, End: 41,
+				Kind: "plural", Arg: "n", Path: icumsg.Path{},
+				Options:           []string{"=0", "one", "other"},
+				MissingCategories: []string{"few", "many"},
+				Percent:           50,
+			}},
+		})
+
+	fn(t, language.English,
+		"{n, selectordinal, one{#st} other{#th}}",
+		nil, 0, 0,
+		icumsg.CompletenessReport{
+			Total: 1, Incomplete: 1, Percent: 0,
+			Choices: []icumsg.ChoiceReport{{
+				Index: 0, Start: 0, End: 39,
+				Kind: "selectordinal", Arg: "n", Path: icumsg.Path{},
+				Options:           []string{"one", "other"},
+				MissingCategories: []string{"two", "few"},
+				Percent:           50,
+			}},
+		})
+
+	fn(t, language.English,
+		"{n, plural, one{# {g, select, a{a} x{x} other{o}}} other{# {g, select, "+
+			"a{a} b{b} other{o}}}}",
+		map[string][]string{"g": {"a", "b"}},
+		icumsg.OptionsPresencePolicyRequired, icumsg.OptionUnknownPolicyReject,
+		icumsg.CompletenessReport{
+			Total: 3, Incomplete: 1, Percent: 66.67,
+			Choices: []icumsg.ChoiceReport{
+				{
+					Index: 0, Start: 0, End: 92,
+					Kind: "plural", Arg: "n", Path: icumsg.Path{},
+					Options:  []string{"one", "other"},
+					Complete: true, Percent: 100,
+				},
+				{
+					Index: 4, Start: 18, End: 49,
+					Kind: "select", Arg: "g",
+					Path:         icumsg.Path{{Arg: "n", Option: "one"}},
+					Options:      []string{"a", "x", "other"},
+					MissingKeys:  []string{"b"},
+					RejectedKeys: []string{"x"},
+					Percent:      50,
+				},
+				{
+					Index: 21, Start: 59, End: 90,
+					Kind: "select", Arg: "g",
+					Path:     icumsg.Path{{Arg: "n", Option: "other"}},
+					Options:  []string{"a", "b", "other"},
+					Complete: true, Percent: 100,
+				},
+			},
+		})
+
+	fn(t, language.English,
+		"{g, select, b{b} x{x} other{o}}",
+		map[string][]string{"g": {"a", "b", "c"}},
+		icumsg.OptionsPresencePolicyRequired, icumsg.OptionUnknownPolicyReject,
+		icumsg.CompletenessReport{
+			Total: 1, Incomplete: 1, Percent: 0,
+			Choices: []icumsg.ChoiceReport{{
+				Index: 0, Start: 0, End: 31,
+				Kind: "select", Arg: "g", Path: icumsg.Path{},
+				Options:      []string{"b", "x", "other"},
+				MissingKeys:  []string{"a", "c"},
+				RejectedKeys: []string{"x"},
+				Percent:      33.33,
+			}},
+		})
+
+	// Optional options are never missing.
+	fn(t, language.English,
+		"{g, select, b{b} x{x} other{o}}",
+		map[string][]string{"g": {"a", "b", "c"}},
+		icumsg.OptionsPresencePolicyOptional, icumsg.OptionUnknownPolicyIgnore,
+		icumsg.CompletenessReport{
+			Total: 1, Incomplete: 0, Percent: 100,
+			Choices: []icumsg.ChoiceReport{{
+				Index: 0, Start: 0, End: 31,
+				Kind: "select", Arg: "g", Path: icumsg.Path{},
+				Options:  []string{"b", "x", "other"},
+				Complete: true, Percent: 100,
+			}},
+		})
+}
+
+func TestCompletenessReportNestedPath(t *testing.T) {
+	const input = `{gender, select,
+		male{{count, plural,
+			one{{place, selectordinal, one{#st} other{#th}}}
+			other{x}
+		}}
+		other{y}
+	}`
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.English, nil, input)
+	test.RequireNoErr(t, err)
+
+	r := icumsg.NewCompletenessReport(input, buffer, language.English, nil,
+		func(string) ([]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy) {
+			return nil, 0, 0
+		})
+	test.RequireEqual(t, 3, r.Total)
+	test.RequireEqual(t, 1, r.Incomplete)
+	test.RequireEqual(t, 3, len(r.Choices))
+	test.RequireEqual(t, "", r.Choices[0].Path.String())
+	test.RequireEqual(t, "gender[male]", r.Choices[1].Path.String())
+	test.RequireEqual(t, "gender[male],count[one]", r.Choices[2].Path.String())
+	test.RequireEqual(t, "place", r.Choices[2].Arg)
+	test.RequireEqual(t, false, r.Choices[2].Complete)
+	test.RequireDeepEqual(t, []string{"two", "few"}, r.Choices[2].MissingCategories)
+}
+
+func TestCompletenessReportJSON(t *testing.T) {
+	const input = "{n, plural, other{# {g, select, other{o}}}}"
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.English, nil, input)
+	test.RequireNoErr(t, err)
+
+	r := icumsg.NewCompletenessReport(input, buffer, language.English, nil,
+		func(string) ([]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy) {
+			return []string{"male"}, icumsg.OptionsPresencePolicyRequired, 0
+		})
+	j, err := json.Marshal(r)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, `{"total":2,"incomplete":2,"percent":0,"choices":[`+
+		`{"index":0,"start":0,"end":43,"kind":"plural","arg":"n","path":[],`+
+		`"options":["other"],"missingCategories":["one"],"complete":false,"percent":50},`+
+		`{"index":4,"start":20,"end":41,"kind":"select","arg":"g",`+
+		`"path":[{"arg":"n","option":"other"}],"options":["other"],`+
+		`"missingKeys":["male"],"complete":false,"percent":0}]}`, string(j))
+
+	var decoded icumsg.CompletenessReport
+	test.RequireNoErr(t, json.Unmarshal(j, &decoded))
+	test.RequireDeepEqual(t, r, decoded)
+}
+
+func TestPathString(t *testing.T) {
+	test.RequireEqual(t, "", icumsg.Path{}.String())
+	test.RequireEqual(t, "n[other],g[male]", icumsg.Path{
+		{Arg: "n", Option: "other"}, {Arg: "g", Option: "male"},
+	}.String())
+}
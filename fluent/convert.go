@@ -0,0 +1,389 @@
+package fluent
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+)
+
+// FromICU converts the ICU message src tokenized into buffer to a Fluent
+// pattern. Plural arguments become select expressions on the variable,
+// selectordinal arguments on NUMBER($n, type: "ordinal") and select
+// arguments on the variable. Options "=N" become variant keys "N",
+// "other" becomes the default variant and "#" becomes { $n }.
+// Number, date and time arguments become NUMBER and DATETIME calls.
+// Plural offsets, currencies, skeletons, custom styles and the types
+// spellout, ordinal and duration are ErrUnrepresentable.
+func FromICU(src string, buffer []icumsg.Token) (Pattern, error) {
+	c := icuConverter{src: src, buffer: buffer}
+	p, err := c.pattern(0, len(buffer), "")
+	if p == nil && err == nil {
+		p = Pattern{}
+	}
+	return p, err
+}
+
+type icuConverter struct {
+	src    string
+	buffer []icumsg.Token
+}
+
+// pattern converts buffer[start:end]. hash is the name of the variable
+// "#" refers to, empty outside of plural and selectordinal options.
+func (c icuConverter) pattern(start, end int, hash string) (Pattern, error) {
+	var p Pattern
+	add := func(e Element) {
+		if last := len(p) - 1; last >= 0 && e.Placeable == nil && p[last].Placeable == nil {
+			p[last].Text += e.Text
+			return
+		}
+		p = append(p, e)
+	}
+	for i := start; i < end; i++ {
+		t := c.buffer[i]
+		switch t.Type {
+		case icumsg.TokenTypeLiteral:
+			for _, e := range literal(c.src[t.IndexStart:t.IndexEnd], hash) {
+				add(e)
+			}
+		case icumsg.TokenTypeSimpleArg:
+			e, err := c.expression(i)
+			if err != nil {
+				return nil, err
+			}
+			add(Element{Placeable: e})
+			// Skip the argument name, type and style.
+			i = icumsg.SimpleArgEnd(c.buffer, i)
+		case icumsg.TokenTypePlural, icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+			e, err := c.selectExpression(i, hash)
+			if err != nil {
+				return nil, err
+			}
+			add(Element{Placeable: e})
+			i = t.IndexEnd
+		}
+	}
+	return p, nil
+}
+
+func (c icuConverter) selectExpression(index int, hash string) (*Expression, error) {
+	arg := c.buffer[index+1].String(c.src, c.buffer)
+	if c.buffer[index+2].Type == icumsg.TokenTypePluralOffset {
+		return nil, fmt.Errorf("%w: offset of %q", ErrUnrepresentable, arg)
+	}
+	variable := Expression{Kind: ExpressionVariable, Value: arg}
+	e := &Expression{Kind: ExpressionSelect, Selector: &variable}
+	switch c.buffer[index].Type {
+	case icumsg.TokenTypePlural:
+		hash = arg
+	case icumsg.TokenTypeSelectOrdinal:
+		hash = arg
+		e.Selector = &Expression{
+			Kind: ExpressionFunction, Value: "NUMBER", Arguments: &Arguments{
+				Positional: []Expression{variable},
+				Named: []NamedArgument{{
+					Name: "type", Value: Expression{Kind: ExpressionString, Value: "ordinal"},
+				}},
+			},
+		}
+	}
+	for j := range icumsg.Options(c.buffer, index) {
+		v := Variant{Key: strings.TrimPrefix(icumsg.OptionName(c.src, c.buffer, j), "=")}
+		v.Default = v.Key == "other"
+		var err error
+		if v.Value, err = c.pattern(j+1, c.buffer[j].IndexEnd, hash); err != nil {
+			return nil, err
+		}
+		if v.Value == nil {
+			v.Value = Pattern{{Placeable: &Expression{Kind: ExpressionString}}}
+		}
+		e.Variants = append(e.Variants, v)
+	}
+	return e, nil
+}
+
+// expression converts the simple argument at buffer[index].
+func (c icuConverter) expression(index int) (*Expression, error) {
+	var a icumsg.Argument
+	for a = range icumsg.Arguments(c.src, c.buffer[index:]) {
+		break
+	}
+	variable := Expression{Kind: ExpressionVariable, Value: a.Name}
+	call := func(name string, options ...string) *Expression {
+		e := &Expression{Kind: ExpressionFunction, Value: name, Arguments: &Arguments{
+			Positional: []Expression{variable},
+		}}
+		for i := 0; i+1 < len(options); i += 2 {
+			kind := ExpressionString
+			if isDigit(options[i+1][0]) {
+				kind = ExpressionNumber
+			}
+			e.Arguments.Named = append(e.Arguments.Named, NamedArgument{
+				Name: options[i], Value: Expression{Kind: kind, Value: options[i+1]},
+			})
+		}
+		return e
+	}
+	style := styleNames[a.Style]
+	switch {
+	case a.Type == icumsg.TokenTypeSimpleArg:
+		return &variable, nil
+	case a.Type == icumsg.TokenTypeArgTypeNumber && a.Style == 0:
+		return call("NUMBER"), nil
+	case a.Type == icumsg.TokenTypeArgTypeNumber && a.Style == icumsg.TokenTypeArgStyleInteger:
+		return call("NUMBER", "maximumFractionDigits", "0"), nil
+	case a.Type == icumsg.TokenTypeArgTypeNumber && a.Style == icumsg.TokenTypeArgStylePercent:
+		return call("NUMBER", "style", "percent"), nil
+	case a.Type == icumsg.TokenTypeArgTypeDate && a.Style == 0:
+		return call("DATETIME"), nil
+	case a.Type == icumsg.TokenTypeArgTypeDate && style != "":
+		return call("DATETIME", "dateStyle", style), nil
+	case a.Type == icumsg.TokenTypeArgTypeTime && a.Style == 0:
+		return call("DATETIME", "timeStyle", "medium"), nil
+	case a.Type == icumsg.TokenTypeArgTypeTime && style != "":
+		return call("DATETIME", "timeStyle", style), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnrepresentable, c.buffer[index].String(c.src, c.buffer))
+}
+
+var styleNames = map[icumsg.TokenType]string{
+	icumsg.TokenTypeArgStyleShort:  "short",
+	icumsg.TokenTypeArgStyleMedium: "medium",
+	icumsg.TokenTypeArgStyleLong:   "long",
+	icumsg.TokenTypeArgStyleFull:   "full",
+}
+
+// literal unescapes the ICU literal l replacing '#' by { $hash }
+// unless hash is empty.
+func literal(l, hash string) []Element {
+	var elements []Element
+	for text, isHash := range icumsg.LiteralSegments(l, hash != "") {
+		if text != "" {
+			elements = append(elements, Element{Text: text})
+		}
+		if isHash {
+			elements = append(elements, Element{Placeable: &Expression{
+				Kind: ExpressionVariable, Value: hash,
+			}})
+		}
+	}
+	return elements
+}
+
+// ToICU converts p to an ICU message. Select expressions on variables
+// with only numeric and CLDR plural category keys and on NUMBER calls
+// become plural arguments, on NUMBER calls of type "ordinal"
+// selectordinal arguments and all others select arguments.
+// The default variant becomes "other" and numeric keys become "=N".
+// Placeables of the innermost plural variable become "#".
+// Term references are ErrTerm. Message references and functions other
+// than NUMBER and DATETIME with the options produced by FromICU
+// are ErrUnrepresentable. The result isn't validated.
+func ToICU(p Pattern) (string, error) {
+	var b strings.Builder
+	err := writeICU(&b, p, "")
+	return b.String(), err
+}
+
+func writeICU(b *strings.Builder, p Pattern, hash string) error {
+	for _, e := range p {
+		if e.Placeable == nil {
+			b.WriteString(icumsg.EscapeLiteral(e.Text))
+			continue
+		}
+		if err := writeICUExpression(b, e.Placeable, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeICUExpression(b *strings.Builder, e *Expression, hash string) error {
+	switch e.Kind {
+	case ExpressionString, ExpressionNumber:
+		b.WriteString(icumsg.EscapeLiteral(e.Value))
+	case ExpressionVariable:
+		if e.Value == hash {
+			b.WriteByte('#')
+		} else {
+			b.WriteString("{" + e.Value + "}")
+		}
+	case ExpressionPlaceable:
+		return writeICUExpression(b, e.Selector, hash)
+	case ExpressionTerm:
+		return fmt.Errorf("%w: reference to -%s", ErrTerm, e.Value)
+	case ExpressionMessage:
+		return fmt.Errorf("%w: reference to message %s", ErrUnrepresentable, e.Value)
+	case ExpressionFunction:
+		arg, options, err := call(e)
+		if err != nil {
+			return err
+		}
+		switch {
+		case e.Value == "NUMBER" && len(options) == 0 && arg == hash:
+			b.WriteByte('#')
+		case e.Value == "NUMBER" && len(options) == 0:
+			b.WriteString("{" + arg + ", number}")
+		case e.Value == "NUMBER" && options["maximumFractionDigits"] == "0" && len(options) == 1:
+			b.WriteString("{" + arg + ", number, integer}")
+		case e.Value == "NUMBER" && options["style"] == "percent" && len(options) == 1:
+			b.WriteString("{" + arg + ", number, percent}")
+		case e.Value == "DATETIME" && len(options) == 0:
+			b.WriteString("{" + arg + ", date}")
+		case e.Value == "DATETIME" && len(options) == 1 && isStyle(options["dateStyle"]):
+			b.WriteString("{" + arg + ", date, " + options["dateStyle"] + "}")
+		case e.Value == "DATETIME" && len(options) == 1 && isStyle(options["timeStyle"]):
+			b.WriteString("{" + arg + ", time, " + options["timeStyle"] + "}")
+		default:
+			return unrepresentable(e)
+		}
+	case ExpressionSelect:
+		return writeICUSelect(b, e, hash)
+	}
+	return nil
+}
+
+func writeICUSelect(b *strings.Builder, e *Expression, hash string) error {
+	var arg, keyword string
+	switch s := e.Selector; {
+	case s.Kind == ExpressionVariable:
+		arg, keyword = s.Value, "plural"
+		for _, v := range e.Variants {
+			if _, err := cldr.ParseCategory(v.Key); err != nil && !isDigit(v.Key[len(v.Key)-1]) {
+				keyword = "select"
+			}
+		}
+	case s.Kind == ExpressionFunction && s.Value == "NUMBER":
+		var options map[string]string
+		var err error
+		if arg, options, err = call(s); err != nil {
+			return err
+		}
+		switch {
+		case len(options) == 0:
+			keyword = "plural"
+		case len(options) == 1 && options["type"] == "ordinal":
+			keyword = "selectordinal"
+		default:
+			return unrepresentable(s)
+		}
+	case s.Kind == ExpressionTerm:
+		return fmt.Errorf("%w: selector -%s", ErrTerm, s.Value)
+	default:
+		return unrepresentable(s)
+	}
+	if keyword != "select" {
+		hash = arg
+	}
+
+	b.WriteString("{" + arg + ", " + keyword + ",")
+	// The default variant becomes "other" unless there's a variant
+	// with key "other", in which case it's also written with its own key.
+	var other, explicitOther *Variant
+	for i, v := range e.Variants {
+		switch {
+		case v.Default:
+			other = &e.Variants[i]
+			if v.Key == "other" {
+				continue
+			}
+		case v.Key == "other":
+			explicitOther = &e.Variants[i]
+			continue
+		}
+		key := v.Key
+		if keyword != "select" && isDigit(key[len(key)-1]) {
+			key = "=" + key
+		}
+		if err := writeICUVariant(b, key, v.Value, hash); err != nil {
+			return err
+		}
+	}
+	if explicitOther != nil {
+		other = explicitOther
+	}
+	if err := writeICUVariant(b, "other", other.Value, hash); err != nil {
+		return err
+	}
+	b.WriteString("}")
+	return nil
+}
+
+func writeICUVariant(b *strings.Builder, key string, p Pattern, hash string) error {
+	b.WriteString(" " + key + " {")
+	if err := writeICU(b, p, hash); err != nil {
+		return err
+	}
+	b.WriteString("}")
+	return nil
+}
+
+// call returns the variable and the named options of the function call e.
+func call(e *Expression) (arg string, options map[string]string, err error) {
+	if e.Arguments == nil || len(e.Arguments.Positional) != 1 ||
+		e.Arguments.Positional[0].Kind != ExpressionVariable {
+		return "", nil, unrepresentable(e)
+	}
+	options = map[string]string{}
+	for _, a := range e.Arguments.Named {
+		options[a.Name] = a.Value.Value
+	}
+	return e.Arguments.Positional[0].Value, options, nil
+}
+
+func unrepresentable(e *Expression) error {
+	var b strings.Builder
+	writeExpression(&b, e)
+	return fmt.Errorf("%w: %s", ErrUnrepresentable, b.String())
+}
+
+func isStyle(s string) bool {
+	return slices.Contains([]string{"short", "medium", "long", "full"}, s)
+}
+
+// Issue is a feature of a resource that doesn't translate to ICU.
+type Issue struct {
+	// ID is the identifier of the message or term with "-" for terms
+	// and the attribute, such as "-brand" or "login.title".
+	ID string
+
+	// Err is ErrTerm, ErrAttribute or the conversion error.
+	Err error
+}
+
+func (i Issue) Error() string { return fmt.Sprintf("%s: %v", i.ID, i.Err) }
+
+func (i Issue) Unwrap() error { return i.Err }
+
+// ToICU converts the message values of r to ICU messages by message ID
+// and reports an issue for every term, attribute and message
+// that doesn't convert.
+func (r *Resource) ToICU() (map[string]string, []Issue) {
+	messages := map[string]string{}
+	var issues []Issue
+	for _, e := range r.Entries {
+		switch e.Kind {
+		case EntryTerm:
+			issues = append(issues, Issue{ID: "-" + e.ID, Err: ErrTerm})
+			continue
+		case EntryComment:
+			continue
+		}
+		for _, a := range e.Attributes {
+			issues = append(issues, Issue{ID: e.ID + "." + a.ID, Err: ErrAttribute})
+		}
+		if e.Value == nil {
+			continue
+		}
+		s, err := ToICU(e.Value)
+		if err != nil {
+			issues = append(issues, Issue{ID: e.ID, Err: err})
+			continue
+		}
+		messages[e.ID] = s
+	}
+	return messages, issues
+}
@@ -0,0 +1,149 @@
+package fluent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/fluent"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestFromICU(t *testing.T) {
+	f := func(t *testing.T, src, expect, expectICU string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		p, err := fluent.FromICU(src, buffer)
+		test.RequireNoErr(t, err)
+		var b strings.Builder
+		test.RequireNoErr(t, fluent.Write(&b, &fluent.Resource{Entries: []fluent.Entry{
+			{Kind: fluent.EntryMessage, ID: "m", Value: p},
+		}}))
+		test.RequireEqual(t, expect, b.String())
+
+		// The output converts back to ICU.
+		icu, err := fluent.ToICU(p)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expectICU, icu)
+	}
+
+	f(t, "", "m = { \"\" }\n", "")
+	f(t, "It''s '{'{name}'}' # 100%",
+		"m = It's { \"{\" }{ $name }{ \"}\" } # 100%\n",
+		"It''s '{'{name}'}' '#' 100%")
+	f(t, "{n, number} {n, number, integer} {p, number, percent}",
+		"m = { NUMBER($n) } { NUMBER($n, maximumFractionDigits: 0) } "+
+			"{ NUMBER($p, style: \"percent\") }\n",
+		"{n, number} {n, number, integer} {p, number, percent}")
+	f(t, "{d, date} {d, date, short} {t, time} {t, time, full}",
+		"m = { DATETIME($d) } { DATETIME($d, dateStyle: \"short\") } "+
+			"{ DATETIME($t, timeStyle: \"medium\") } { DATETIME($t, timeStyle: \"full\") }\n",
+		"{d, date} {d, date, short} {t, time, medium} {t, time, full}")
+	f(t, "You have {count, plural, =0 {no files} one {# file} other {# '#' files}}.",
+		`m =
+    You have { $count ->
+        [0] no files
+        [one] { $count } file
+       *[other] { $count } # files
+    }.
+`, "You have {count, plural, =0 {no files} one {# file} other {# '#' files}}.")
+	f(t, "{pos, selectordinal, one {#st} other {#th}}",
+		`m =
+    { NUMBER($pos, type: "ordinal") ->
+        [one] { $pos }st
+       *[other] { $pos }th
+    }
+`, "{pos, selectordinal, one {#st} other {#th}}")
+	f(t, "{g, select, female {She} other {{n, plural, one {It} other {They}}}}",
+		`m =
+    { $g ->
+        [female] She
+       *[other]
+            { $n ->
+                [one] It
+               *[other] They
+            }
+    }
+`, "{g, select, female {She} other {{n, plural, one {It} other {They}}}}")
+}
+
+func TestFromICUErr(t *testing.T) {
+	f := func(t *testing.T, src string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		_, err = fluent.FromICU(src, buffer)
+		test.RequireErrIs(t, fluent.ErrUnrepresentable, err)
+	}
+
+	f(t, "{n, plural, offset:1 one {#} other {#}}")
+	f(t, "{c, number, currency}")
+	f(t, "{n, number, ::compact-short}")
+	f(t, "{n, spellout}")
+	f(t, "{d, duration}")
+}
+
+func TestToICU(t *testing.T) {
+	f := func(t *testing.T, input, expect string) {
+		t.Helper()
+		r, err := fluent.Read(strings.NewReader("m = " + input))
+		test.RequireNoErr(t, err)
+		icu, err := fluent.ToICU(r.Entries[0].Value)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, icu)
+	}
+
+	f(t, `{ "{" }{ 42 }{ { $x } }`, "'{'42{x}")
+	// The default variant is also written under its own key.
+	f(t, "{ NUMBER($n) } { $n ->\n [one] one\n *[many] many\n}",
+		"{n, number} {n, plural, one {one} many {many} other {many}}")
+	f(t, "{ $g ->\n [male] { $n }\n *[other] x\n}", "{g, select, male {{n}} other {x}}")
+	f(t, "{ NUMBER($n) ->\n [1] { NUMBER($n) }\n *[other] x\n}",
+		"{n, plural, =1 {#} other {x}}")
+	// An explicit "other" takes precedence over the default variant.
+	f(t, "{ $g ->\n *[a] A\n [other] O\n}", "{g, select, a {A} other {O}}")
+}
+
+func TestToICUErr(t *testing.T) {
+	f := func(t *testing.T, expect error, input string) {
+		t.Helper()
+		r, err := fluent.Read(strings.NewReader("m = " + input))
+		test.RequireNoErr(t, err)
+		_, err = fluent.ToICU(r.Entries[0].Value)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, fluent.ErrTerm, "{ -brand }")
+	f(t, fluent.ErrTerm, "{ -brand.gender ->\n *[other] x\n}")
+	f(t, fluent.ErrUnrepresentable, "{ msg }")
+	f(t, fluent.ErrUnrepresentable, "{ UPPER($x) }")
+	f(t, fluent.ErrUnrepresentable, `{ NUMBER($n, minimumFractionDigits: 2) }`)
+	f(t, fluent.ErrUnrepresentable, `{ DATETIME($d, month: "long") }`)
+	f(t, fluent.ErrUnrepresentable, `{ NUMBER(1) }`)
+	f(t, fluent.ErrUnrepresentable, "{ \"x\" ->\n *[other] x\n}")
+}
+
+func TestResourceToICU(t *testing.T) {
+	r, err := fluent.Read(strings.NewReader(ftl))
+	test.RequireNoErr(t, err)
+	messages, issues := r.ToICU()
+	test.RequireDeepEqual(t, map[string]string{
+		"hello":     "Hello, {name}!",
+		"multiline": "First line,\n\n  indented second line.",
+		"literals":  "'{' and A\"\\ 1.5 {x}",
+	}, messages)
+
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	test.RequireDeepEqual(t, []string{"-brand", "login.title", "emails", "calls"}, ids)
+	test.RequireErrIs(t, fluent.ErrTerm, issues[0])
+	test.RequireErrIs(t, fluent.ErrAttribute, issues[1])
+	test.RequireErrIs(t, fluent.ErrTerm, issues[2])
+	test.RequireErrIs(t, fluent.ErrUnrepresentable, issues[3])
+}
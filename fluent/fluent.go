@@ -0,0 +1,361 @@
+// Package fluent reads and writes Project Fluent (FTL) resources and
+// converts Fluent patterns to ICU messages and back.
+package fluent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrSyntax = errors.New("syntax error")
+
+	// ErrUnrepresentable is returned for constructs that have no
+	// equivalent in the target syntax, such as plural offsets in ICU
+	// or message references and unknown functions in Fluent.
+	ErrUnrepresentable = errors.New("not representable")
+
+	// ErrTerm is reported for terms and term references,
+	// which have no equivalent in ICU.
+	ErrTerm = errors.New("terms not supported")
+
+	// ErrAttribute is reported for message attributes,
+	// which have no equivalent in ICU.
+	ErrAttribute = errors.New("attributes not supported")
+)
+
+// Resource is an FTL resource.
+type Resource struct {
+	// Entries are the entries in the order of appearance.
+	Entries []Entry
+}
+
+// EntryKind is the kind of an entry.
+type EntryKind int8
+
+const (
+	_ EntryKind = iota
+	EntryMessage
+	EntryTerm
+
+	// EntryComment is a standalone comment.
+	EntryComment
+)
+
+// Entry is a message, term or standalone comment.
+type Entry struct {
+	Kind EntryKind
+
+	// Comments are the comment lines including the leading "#",
+	// "##" or "###".
+	Comments []string
+
+	// ID is the identifier without "-" for terms.
+	ID string
+
+	// Value is nil for messages with attributes only.
+	Value      Pattern
+	Attributes []Attribute
+}
+
+// Attribute is an attribute of a message or term such as ".title".
+type Attribute struct {
+	ID    string
+	Value Pattern
+}
+
+// Pattern is a sequence of text and placeables.
+type Pattern []Element
+
+// Element is either text or a placeable.
+type Element struct {
+	// Text is the text if Placeable is nil.
+	Text      string
+	Placeable *Expression
+}
+
+// ExpressionKind is the kind of an expression.
+type ExpressionKind int8
+
+const (
+	_ ExpressionKind = iota
+	ExpressionString
+	ExpressionNumber
+	ExpressionVariable
+	ExpressionMessage
+	ExpressionTerm
+	ExpressionFunction
+	ExpressionSelect
+
+	// ExpressionPlaceable is a nested placeable such as { { $x } }.
+	ExpressionPlaceable
+)
+
+// Expression is an expression of a placeable.
+type Expression struct {
+	Kind ExpressionKind
+
+	// Value is the unescaped string, the number, or the identifier of
+	// variables, messages, terms and functions without "$" or "-".
+	Value string
+
+	// Attribute is the attribute of message and term references.
+	Attribute string
+
+	// Arguments are the arguments of functions and term references,
+	// nil if there are none.
+	Arguments *Arguments
+
+	// Selector is the selector of select expressions and
+	// the expression of nested placeables.
+	Selector *Expression
+	Variants []Variant
+}
+
+// Arguments are call arguments.
+type Arguments struct {
+	Positional []Expression
+	Named      []NamedArgument
+}
+
+// NamedArgument is a named call argument such as type: "ordinal".
+type NamedArgument struct {
+	Name  string
+	Value Expression
+}
+
+// Variant is a variant of a select expression.
+type Variant struct {
+	// Key is an identifier or a number.
+	Key     string
+	Default bool
+	Value   Pattern
+}
+
+// Write writes r as an FTL resource. Patterns with line breaks or select
+// expressions start on a new line. Braces, leading and trailing spaces
+// and special characters at the start of lines are escaped as
+// string literals.
+func Write(w io.Writer, r *Resource) error {
+	var b strings.Builder
+	for i, e := range r.Entries {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		for _, c := range e.Comments {
+			b.WriteString(c + "\n")
+		}
+		switch e.Kind {
+		case EntryMessage:
+			b.WriteString(e.ID)
+		case EntryTerm:
+			b.WriteString("-" + e.ID)
+		default:
+			continue
+		}
+		b.WriteString(" =")
+		if e.Value != nil || len(e.Attributes) == 0 {
+			writeValue(&b, e.Value, "    ")
+		}
+		b.WriteByte('\n')
+		for _, a := range e.Attributes {
+			b.WriteString("    ." + a.ID + " =")
+			writeValue(&b, a.Value, "        ")
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeValue writes " " and the inline pattern p or,
+// if it's multiline, a line break and p indented.
+func writeValue(b *strings.Builder, p Pattern, indent string) {
+	s := patternString(p)
+	if !strings.Contains(s, "\n") {
+		b.WriteString(" " + s)
+		return
+	}
+	for line := range strings.Lines(s) {
+		b.WriteByte('\n')
+		if line = strings.TrimSuffix(line, "\n"); line != "" {
+			b.WriteString(indent + line)
+		}
+	}
+}
+
+// patternString returns p serialized without indentation.
+func patternString(p Pattern) string {
+	if len(p) == 0 {
+		return `{ "" }`
+	}
+	var b strings.Builder
+	for i, e := range p {
+		if e.Placeable == nil {
+			writeText(&b, e.Text, i == 0, i == len(p)-1)
+			continue
+		}
+		writePlaceable(&b, e.Placeable)
+	}
+	return b.String()
+}
+
+// writeText writes text escaping braces, the leading spaces of the
+// pattern and of lines, trailing spaces of the pattern and
+// characters with special meaning at the start of lines.
+func writeText(b *strings.Builder, text string, first, last bool) {
+	lineStart := first
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '{' || c == '}':
+			b.WriteString(`{ "` + string(c) + `" }`)
+		case lineStart && (c == '[' || c == '*' || c == '.'):
+			b.WriteString(`{ "` + string(c) + `" }`)
+		case c == ' ' && (lineStart || last && strings.TrimRight(text[i:], " ") == ""):
+			j := i
+			for j < len(text) && text[j] == ' ' {
+				j++
+			}
+			b.WriteString(`{ "` + text[i:j] + `" }`)
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+		lineStart = c == '\n'
+	}
+}
+
+// writePlaceable writes e enclosed in braces. Select expressions end
+// with a line break so the closing brace is on a line of its own.
+func writePlaceable(b *strings.Builder, e *Expression) {
+	b.WriteString("{ ")
+	writeExpression(b, e)
+	if e.Kind == ExpressionSelect {
+		b.WriteString("}")
+	} else {
+		b.WriteString(" }")
+	}
+}
+
+func writeExpression(b *strings.Builder, e *Expression) {
+	switch e.Kind {
+	case ExpressionString:
+		b.WriteString(quote(e.Value))
+	case ExpressionNumber:
+		b.WriteString(e.Value)
+	case ExpressionVariable:
+		b.WriteString("$" + e.Value)
+	case ExpressionMessage, ExpressionTerm:
+		if e.Kind == ExpressionTerm {
+			b.WriteByte('-')
+		}
+		b.WriteString(e.Value)
+		if e.Attribute != "" {
+			b.WriteString("." + e.Attribute)
+		}
+		writeArguments(b, e.Arguments)
+	case ExpressionFunction:
+		b.WriteString(e.Value)
+		if e.Arguments == nil {
+			b.WriteString("()")
+		}
+		writeArguments(b, e.Arguments)
+	case ExpressionPlaceable:
+		writePlaceable(b, e.Selector)
+	case ExpressionSelect:
+		writeExpression(b, e.Selector)
+		b.WriteString(" ->")
+		for _, v := range e.Variants {
+			b.WriteString("\n")
+			if v.Default {
+				b.WriteString("   *[")
+			} else {
+				b.WriteString("    [")
+			}
+			b.WriteString(v.Key + "]")
+			writeValue(b, v.Value, "        ")
+		}
+		b.WriteString("\n")
+	}
+}
+
+func writeArguments(b *strings.Builder, args *Arguments) {
+	if args == nil {
+		return
+	}
+	b.WriteByte('(')
+	for i, a := range args.Positional {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeExpression(b, &a)
+	}
+	for i, a := range args.Named {
+		if i > 0 || len(args.Positional) > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(a.Name + ": ")
+		writeExpression(b, &a.Value)
+	}
+	b.WriteByte(')')
+}
+
+// quote returns s as a string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7F:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquote returns the unescaped content of the string literal s
+// without the quotes.
+func unquote(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", fmt.Errorf("invalid escape sequence")
+		}
+		switch s[i+1] {
+		case '"', '\\':
+			b.WriteByte(s[i+1])
+			i++
+		case 'u', 'U':
+			n := 4
+			if s[i+1] == 'U' {
+				n = 6
+			}
+			if i+2+n > len(s) {
+				return "", fmt.Errorf("invalid escape sequence")
+			}
+			r, err := strconv.ParseUint(s[i+2:i+2+n], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape sequence")
+			}
+			b.WriteRune(rune(r))
+			i += 1 + n
+		default:
+			return "", fmt.Errorf("invalid escape sequence")
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,63 @@
+package fluent_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg/fluent"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+func TestWrite(t *testing.T) {
+	f := func(t *testing.T, input, expect string) {
+		t.Helper()
+		r, err := fluent.Read(strings.NewReader(input))
+		test.RequireNoErr(t, err)
+		var b bytes.Buffer
+		test.RequireNoErr(t, fluent.Write(&b, r))
+		test.RequireEqual(t, expect, b.String())
+
+		// The output reads back and writes to the same output.
+		r2, err := fluent.Read(&b)
+		test.RequireNoErr(t, err)
+		b.Reset()
+		test.RequireNoErr(t, fluent.Write(&b, r2))
+		test.RequireEqual(t, expect, b.String())
+	}
+
+	f(t, ftl, `### Resource comment.
+
+## Group comment.
+
+# Brand name.
+-brand = Firefox
+    .gender = masculine
+
+hello = Hello, { $name }!
+
+login =
+    .title = Log in
+
+emails =
+    You have { $count ->
+        [0] no unread emails.
+        [one] one unread email.
+       *[other]
+            { NUMBER($count) } unread emails
+            in { -brand }.
+    }
+
+multiline =
+    First line,
+
+    { "  " }indented second line.
+
+literals = { "{" } and { "A\"\\" } { 1.5 } { { $x } }
+
+calls = { DATETIME($d, dateStyle: "short") } { msg.attr } { -term(case: "genitive") }
+`)
+	f(t, "a = {\"  \"}[x]{\" \"}\nb = {\"\"}\n", "a = { \"  \" }[x]{ \" \" }\n\nb = { \"\" }\n")
+	f(t, "a =\n    {\"[\"}x\n    {\"*\"}y\n    {\".\"}z\n",
+		"a =\n    { \"[\" }x\n    { \"*\" }y\n    { \".\" }z\n")
+}
@@ -0,0 +1,451 @@
+package fluent
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Read reads an FTL resource. Comments directly preceding a message or
+// term ("#") belong to it, all others become standalone comment entries.
+func Read(r io.Reader) (*Resource, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{s: strings.ReplaceAll(string(data), "\r\n", "\n")}
+	res, err := p.resource()
+	if err != nil {
+		line := strings.Count(p.s[:min(p.pos, len(p.s))], "\n") + 1
+		return nil, fmt.Errorf("%w: line %d: %v", ErrSyntax, line, err)
+	}
+	return res, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) resource() (*Resource, error) {
+	r := new(Resource)
+	var comments []string
+	flush := func() {
+		if comments != nil {
+			r.Entries = append(r.Entries, Entry{Kind: EntryComment, Comments: comments})
+			comments = nil
+		}
+	}
+	for p.pos < len(p.s) {
+		line := p.s[p.pos:]
+		if i := strings.IndexByte(line, '\n'); i != -1 {
+			line = line[:i]
+		}
+		switch {
+		case strings.TrimLeft(line, " ") == "":
+			p.pos += len(line) + 1
+			flush()
+		case line[0] == '#':
+			level := len(line) - len(strings.TrimLeft(line, "#"))
+			if level > 3 || len(line) > level && line[level] != ' ' {
+				return nil, fmt.Errorf("invalid comment")
+			}
+			if len(comments) > 0 && !strings.HasPrefix(comments[0], line[:level]+" ") &&
+				comments[0] != line[:level] {
+				flush() // Comments of different levels.
+			}
+			comments = append(comments, line)
+			p.pos += len(line) + 1
+		case line[0] == '-' || isIdentifierStart(line[0]):
+			e, err := p.entry()
+			if err != nil {
+				return nil, err
+			}
+			if len(comments) > 0 && !strings.HasPrefix(comments[0], "##") {
+				e.Comments, comments = comments, nil
+			}
+			flush()
+			r.Entries = append(r.Entries, e)
+		default:
+			return nil, fmt.Errorf("expected message, term or comment")
+		}
+	}
+	flush()
+	return r, nil
+}
+
+func (p *parser) entry() (Entry, error) {
+	e := Entry{Kind: EntryMessage}
+	if p.s[p.pos] == '-' {
+		e.Kind = EntryTerm
+		p.pos++
+	}
+	var err error
+	if e.ID, err = p.identifier(); err != nil {
+		return e, err
+	}
+	p.skipInline()
+	if err := p.expect("="); err != nil {
+		return e, err
+	}
+	p.skipInline()
+	if e.Value, err = p.pattern(); err != nil {
+		return e, err
+	}
+	for {
+		save := p.pos
+		p.skipBlank()
+		if p.pos >= len(p.s) || p.s[p.pos] != '.' || p.s[p.pos-1] != ' ' {
+			p.pos = save
+			break
+		}
+		p.pos++
+		var a Attribute
+		if a.ID, err = p.identifier(); err != nil {
+			return e, err
+		}
+		p.skipInline()
+		if err := p.expect("="); err != nil {
+			return e, err
+		}
+		p.skipInline()
+		if a.Value, err = p.pattern(); err != nil {
+			return e, err
+		}
+		if a.Value == nil {
+			return e, fmt.Errorf("expected value of attribute %q", a.ID)
+		}
+		e.Attributes = append(e.Attributes, a)
+	}
+	if e.Value == nil && (e.Kind == EntryTerm || len(e.Attributes) == 0) {
+		return e, fmt.Errorf("expected value of %q", e.ID)
+	}
+	// Consume the rest of the line.
+	p.skipInline()
+	if p.pos < len(p.s) {
+		if p.s[p.pos] != '\n' {
+			return e, fmt.Errorf("unexpected %q", p.s[p.pos])
+		}
+		p.pos++
+	}
+	return e, nil
+}
+
+// element is a pattern element before dedentation.
+type element struct {
+	Element
+
+	// indent is the number of spaces of a continuation line
+	// and 0 for other elements. Text holds its line breaks.
+	indent int
+}
+
+// pattern parses a pattern starting on the current line and continuing
+// on indented lines. It returns nil for empty patterns.
+func (p *parser) pattern() (Pattern, error) {
+	var elements []element
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; c {
+		case '{':
+			e, err := p.placeable()
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element{Element: Element{Placeable: e}})
+		case '}':
+			return nil, fmt.Errorf("unexpected '}'")
+		case '\n':
+			save := p.pos
+			breaks := 0
+			indent := 0
+			for p.pos < len(p.s) && p.s[p.pos] == '\n' {
+				p.pos++
+				breaks++
+				indent = 0
+				for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+					p.pos++
+					indent++
+				}
+			}
+			if indent == 0 || p.pos >= len(p.s) ||
+				strings.IndexByte("[*.}", p.s[p.pos]) != -1 {
+				p.pos = save
+				return dedent(elements), nil
+			}
+			elements = append(elements, element{
+				Element: Element{Text: strings.Repeat("\n", breaks)}, indent: indent,
+			})
+		default:
+			end := strings.IndexAny(p.s[p.pos:], "{}\n")
+			if end == -1 {
+				end = len(p.s) - p.pos
+			}
+			elements = append(elements, element{
+				Element: Element{Text: p.s[p.pos : p.pos+end]},
+			})
+			p.pos += end
+		}
+	}
+	return dedent(elements), nil
+}
+
+// dedent removes the common indentation of continuation lines,
+// joins adjacent text and trims trailing whitespace.
+func dedent(elements []element) Pattern {
+	common := -1
+	for _, e := range elements {
+		if e.indent > 0 && (common == -1 || e.indent < common) {
+			common = e.indent
+		}
+	}
+	var pattern Pattern
+	for i, e := range elements {
+		if e.indent > 0 {
+			e.Text += strings.Repeat(" ", e.indent-common)
+			if i == 0 {
+				e.Text = strings.TrimLeft(e.Text, "\n") // Block patterns.
+			}
+		}
+		if last := len(pattern) - 1; last >= 0 && e.Placeable == nil &&
+			pattern[last].Placeable == nil {
+			pattern[last].Text += e.Text
+			continue
+		}
+		pattern = append(pattern, e.Element)
+	}
+	if last := len(pattern) - 1; last >= 0 && pattern[last].Placeable == nil {
+		pattern[last].Text = strings.TrimRight(pattern[last].Text, " \n")
+		if pattern[last].Text == "" {
+			pattern = pattern[:last]
+		}
+	}
+	if len(pattern) > 0 && pattern[0].Placeable == nil && pattern[0].Text == "" {
+		pattern = pattern[1:]
+	}
+	if len(pattern) == 0 {
+		return nil
+	}
+	return pattern
+}
+
+func (p *parser) placeable() (*Expression, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	e, err := p.inlineExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if strings.HasPrefix(p.s[p.pos:], "->") {
+		p.pos += 2
+		if e, err = p.selectExpression(e); err != nil {
+			return nil, err
+		}
+	}
+	p.skipBlank()
+	return e, p.expect("}")
+}
+
+func (p *parser) selectExpression(selector *Expression) (*Expression, error) {
+	e := &Expression{Kind: ExpressionSelect, Selector: selector}
+	defaults := 0
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.s) || p.s[p.pos] == '}' {
+			break
+		}
+		var v Variant
+		if p.s[p.pos] == '*' {
+			v.Default = true
+			defaults++
+			p.pos++
+		}
+		if err := p.expect("["); err != nil {
+			return nil, err
+		}
+		p.skipBlank()
+		key, err := p.inlineExpression()
+		if err != nil {
+			return nil, err
+		}
+		if key.Kind != ExpressionNumber &&
+			(key.Kind != ExpressionMessage || key.Attribute != "" || key.Arguments != nil) {
+			return nil, fmt.Errorf("expected variant key")
+		}
+		v.Key = key.Value
+		p.skipBlank()
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		p.skipInline()
+		if v.Value, err = p.pattern(); err != nil {
+			return nil, err
+		}
+		e.Variants = append(e.Variants, v)
+	}
+	if defaults != 1 {
+		return nil, fmt.Errorf("expected exactly one default variant")
+	}
+	return e, nil
+}
+
+func (p *parser) inlineExpression() (*Expression, error) {
+	if p.pos >= len(p.s) {
+		return nil, fmt.Errorf("expected expression")
+	}
+	switch c := p.s[p.pos]; {
+	case c == '"':
+		end := p.pos + 1
+		for end < len(p.s) && p.s[end] != '"' && p.s[end] != '\n' {
+			if p.s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(p.s) || p.s[end] != '"' {
+			return nil, fmt.Errorf("unclosed string literal")
+		}
+		s, err := unquote(p.s[p.pos+1 : end])
+		if err != nil {
+			return nil, err
+		}
+		p.pos = end + 1
+		return &Expression{Kind: ExpressionString, Value: s}, nil
+	case isDigit(c) || c == '-' && p.pos+1 < len(p.s) && isDigit(p.s[p.pos+1]):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.s) && isDigit(p.s[p.pos]) {
+			p.pos++
+		}
+		if p.pos+1 < len(p.s) && p.s[p.pos] == '.' && isDigit(p.s[p.pos+1]) {
+			p.pos++
+			for p.pos < len(p.s) && isDigit(p.s[p.pos]) {
+				p.pos++
+			}
+		}
+		return &Expression{Kind: ExpressionNumber, Value: p.s[start:p.pos]}, nil
+	case c == '$':
+		p.pos++
+		id, err := p.identifier()
+		return &Expression{Kind: ExpressionVariable, Value: id}, err
+	case c == '{':
+		inner, err := p.placeable()
+		return &Expression{Kind: ExpressionPlaceable, Selector: inner}, err
+	case c == '-' || isIdentifierStart(c):
+		e := &Expression{Kind: ExpressionMessage}
+		if c == '-' {
+			e.Kind = ExpressionTerm
+			p.pos++
+		}
+		var err error
+		if e.Value, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '.' {
+			p.pos++
+			if e.Attribute, err = p.identifier(); err != nil {
+				return nil, err
+			}
+		}
+		save := p.pos
+		p.skipBlank()
+		if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+			p.pos = save
+			return e, nil
+		}
+		if e.Kind == ExpressionMessage {
+			if e.Attribute != "" || strings.ToUpper(e.Value) != e.Value {
+				return nil, fmt.Errorf("invalid function name %q", e.Value)
+			}
+			e.Kind = ExpressionFunction
+		}
+		e.Arguments, err = p.arguments()
+		return e, err
+	}
+	return nil, fmt.Errorf("expected expression")
+}
+
+func (p *parser) arguments() (*Arguments, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := new(Arguments)
+	for {
+		p.skipBlank()
+		if p.pos < len(p.s) && p.s[p.pos] == ')' {
+			p.pos++
+			return args, nil
+		}
+		e, err := p.inlineExpression()
+		if err != nil {
+			return nil, err
+		}
+		p.skipBlank()
+		if p.pos < len(p.s) && p.s[p.pos] == ':' {
+			if e.Kind != ExpressionMessage || e.Attribute != "" {
+				return nil, fmt.Errorf("invalid argument name")
+			}
+			p.pos++
+			p.skipBlank()
+			v, err := p.inlineExpression()
+			if err != nil {
+				return nil, err
+			}
+			if v.Kind != ExpressionString && v.Kind != ExpressionNumber {
+				return nil, fmt.Errorf("expected literal value of argument %q", e.Value)
+			}
+			args.Named = append(args.Named, NamedArgument{Name: e.Value, Value: *v})
+		} else {
+			if len(args.Named) > 0 {
+				return nil, fmt.Errorf("positional argument after named arguments")
+			}
+			args.Positional = append(args.Positional, *e)
+		}
+		p.skipBlank()
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+		} else if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+			return nil, fmt.Errorf("expected ',' or ')'")
+		}
+	}
+}
+
+func (p *parser) identifier() (string, error) {
+	start := p.pos
+	if p.pos >= len(p.s) || !isIdentifierStart(p.s[p.pos]) {
+		return "", fmt.Errorf("expected identifier")
+	}
+	for p.pos < len(p.s) && (isIdentifierStart(p.s[p.pos]) || isDigit(p.s[p.pos]) ||
+		p.s[p.pos] == '_' || p.s[p.pos] == '-') {
+		p.pos++
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *parser) expect(s string) error {
+	if !strings.HasPrefix(p.s[p.pos:], s) {
+		return fmt.Errorf("expected %q", s)
+	}
+	p.pos += len(s)
+	return nil
+}
+
+// skipInline skips spaces.
+func (p *parser) skipInline() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// skipBlank skips spaces and line breaks.
+func (p *parser) skipBlank() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func isIdentifierStart(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
@@ -0,0 +1,158 @@
+package fluent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg/fluent"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+const ftl = `### Resource comment.
+
+## Group comment.
+
+# Brand name.
+-brand = Firefox
+    .gender = masculine
+
+hello = Hello, { $name }!
+login =
+    .title = Log in
+emails =
+    You have { $count ->
+        [0] no unread emails.
+        [one] one unread email.
+       *[other] { NUMBER($count) } unread emails
+            in { -brand }.
+    }
+multiline =
+    First line,
+
+      indented second line.
+literals = { "{" } and {"A\"\\"} { 1.5 } { { $x } }
+calls = { DATETIME($d, dateStyle: "short") } { msg.attr } { -term(case: "genitive") }
+`
+
+func TestRead(t *testing.T) {
+	r, err := fluent.Read(strings.NewReader(ftl))
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, 9, len(r.Entries))
+
+	test.RequireDeepEqual(t, fluent.Entry{
+		Kind: fluent.EntryComment, Comments: []string{"### Resource comment."},
+	}, r.Entries[0])
+	test.RequireDeepEqual(t, fluent.Entry{
+		Kind: fluent.EntryComment, Comments: []string{"## Group comment."},
+	}, r.Entries[1])
+	test.RequireDeepEqual(t, fluent.Entry{
+		Kind:     fluent.EntryTerm,
+		Comments: []string{"# Brand name."},
+		ID:       "brand",
+		Value:    fluent.Pattern{{Text: "Firefox"}},
+		Attributes: []fluent.Attribute{
+			{ID: "gender", Value: fluent.Pattern{{Text: "masculine"}}},
+		},
+	}, r.Entries[2])
+	test.RequireDeepEqual(t, fluent.Pattern{
+		{Text: "Hello, "},
+		{Placeable: &fluent.Expression{Kind: fluent.ExpressionVariable, Value: "name"}},
+		{Text: "!"},
+	}, r.Entries[3].Value)
+	test.RequireDeepEqual(t, fluent.Entry{
+		Kind: fluent.EntryMessage,
+		ID:   "login",
+		Attributes: []fluent.Attribute{
+			{ID: "title", Value: fluent.Pattern{{Text: "Log in"}}},
+		},
+	}, r.Entries[4])
+
+	count := fluent.Expression{Kind: fluent.ExpressionVariable, Value: "count"}
+	test.RequireDeepEqual(t, fluent.Pattern{
+		{Text: "You have "},
+		{Placeable: &fluent.Expression{
+			Kind: fluent.ExpressionSelect, Selector: &count,
+			Variants: []fluent.Variant{
+				{Key: "0", Value: fluent.Pattern{{Text: "no unread emails."}}},
+				{Key: "one", Value: fluent.Pattern{{Text: "one unread email."}}},
+				{Key: "other", Default: true, Value: fluent.Pattern{
+					{Placeable: &fluent.Expression{
+						Kind: fluent.ExpressionFunction, Value: "NUMBER",
+						Arguments: &fluent.Arguments{Positional: []fluent.Expression{count}},
+					}},
+					{Text: " unread emails\nin "},
+					{Placeable: &fluent.Expression{Kind: fluent.ExpressionTerm, Value: "brand"}},
+					{Text: "."},
+				}},
+			},
+		}},
+	}, r.Entries[5].Value)
+	test.RequireDeepEqual(t, fluent.Pattern{
+		{Text: "First line,\n\n  indented second line."},
+	}, r.Entries[6].Value)
+	test.RequireDeepEqual(t, fluent.Pattern{
+		{Placeable: &fluent.Expression{Kind: fluent.ExpressionString, Value: "{"}},
+		{Text: " and "},
+		{Placeable: &fluent.Expression{Kind: fluent.ExpressionString, Value: `A"\`}},
+		{Text: " "},
+		{Placeable: &fluent.Expression{Kind: fluent.ExpressionNumber, Value: "1.5"}},
+		{Text: " "},
+		{Placeable: &fluent.Expression{
+			Kind:     fluent.ExpressionPlaceable,
+			Selector: &fluent.Expression{Kind: fluent.ExpressionVariable, Value: "x"},
+		}},
+	}, r.Entries[7].Value)
+	test.RequireDeepEqual(t, fluent.Pattern{
+		{Placeable: &fluent.Expression{
+			Kind: fluent.ExpressionFunction, Value: "DATETIME",
+			Arguments: &fluent.Arguments{
+				Positional: []fluent.Expression{{Kind: fluent.ExpressionVariable, Value: "d"}},
+				Named: []fluent.NamedArgument{{
+					Name:  "dateStyle",
+					Value: fluent.Expression{Kind: fluent.ExpressionString, Value: "short"},
+				}},
+			},
+		}},
+		{Text: " "},
+		{Placeable: &fluent.Expression{
+			Kind: fluent.ExpressionMessage, Value: "msg", Attribute: "attr",
+		}},
+		{Text: " "},
+		{Placeable: &fluent.Expression{
+			Kind: fluent.ExpressionTerm, Value: "term",
+			Arguments: &fluent.Arguments{Named: []fluent.NamedArgument{{
+				Name:  "case",
+				Value: fluent.Expression{Kind: fluent.ExpressionString, Value: "genitive"},
+			}}},
+		}},
+	}, r.Entries[8].Value)
+}
+
+func TestReadErr(t *testing.T) {
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		_, err := fluent.Read(strings.NewReader(input))
+		test.RequireErrIs(t, fluent.ErrSyntax, err)
+	}
+
+	f(t, "=")
+	f(t, "msg")
+	f(t, "msg =")
+	f(t, "-term =\n    .attr = x")
+	f(t, "msg = }")
+	f(t, "msg = {")
+	f(t, "msg = { $x")
+	f(t, `msg = { "x }`)
+	f(t, `msg = { "\x" }`)
+	f(t, "msg = { lower() }")
+	f(t, "msg = { F(a: $x) }")
+	f(t, "msg = { F(a: 1, $x) }")
+	f(t, "msg = { F($x $y) }")
+	f(t, "msg = { $x ->\n [a] A\n}")
+	f(t, "msg = { $x ->\n *[a] A\n *[b] B\n}")
+	f(t, "msg = { $x ->\n *[$y] A\n}")
+	f(t, "msg = { $x ->\n *a] A\n}")
+	f(t, "#invalid")
+	f(t, "####")
+	f(t, "msg = x\n!")
+}
@@ -0,0 +1,608 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/romshark/icumsg"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// ErrArgumentCount is returned by Message.Append if the number of
+// arguments doesn't match the number of argument slots.
+var ErrArgumentCount = errors.New("wrong number of arguments")
+
+// Message is a compiled ICU message. Literals are unescaped, arguments
+// are resolved to slots, select options are looked up in maps and plural
+// options in tables by plural form. Numbers are formatted with the
+// symbols of the locale determined at compile time like Formatter
+// formats them.
+// Message is immutable and safe for concurrent use.
+type Message struct {
+	locale  language.Tag
+	args    []string
+	ops     []op
+	symbols symbols
+}
+
+type opCode uint8
+
+const (
+	_ opCode = iota
+	opLiteral
+	opSimple
+	opNumber
+	opInteger
+	opPercent
+	opDate // Dates and times with the layout in text.
+	opDuration
+	opHash
+	opSelect
+	opPlural
+	opSelectOrdinal
+)
+
+// op is an instruction of a compiled message. The option bodies of
+// select, plural and selectordinal instructions follow the instruction
+// and next is the index of the instruction following the last body.
+type op struct {
+	code   opCode
+	slot   int
+	next   int
+	text   string
+	choice *choice
+}
+
+type choice struct {
+	offset int
+
+	// options are the ranges of instructions of option bodies.
+	options []span
+
+	// keys maps select option names to options.
+	keys map[string]int
+
+	// exact are the options "=n" of plurals and forms maps plural forms
+	// to options, all forms without option map to "other".
+	exact []exact
+	forms [plural.Many + 1]int
+
+	// other is the option "other".
+	other int
+}
+
+type span struct{ start, end int }
+
+type exact struct {
+	value  float64
+	option int
+}
+
+// Compile tokenizes the ICU message src of locale and compiles it.
+// Arguments of types and styles that can't be formatted are
+// ErrUnsupported, see Formatter.Format for the accepted values.
+func Compile(locale language.Tag, src string) (*Message, error) {
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(locale, nil, src)
+	if err != nil {
+		return nil, err
+	}
+	return CompileTokens(locale, src, buffer)
+}
+
+// CompileTokens compiles the ICU message src of locale tokenized into
+// buffer, such as the message and tokens of a catalog entry.
+// buffer isn't retained.
+func CompileTokens(locale language.Tag, src string, buffer []icumsg.Token) (*Message, error) {
+	m := &Message{locale: locale, symbols: newSymbols(locale)}
+	c := compiler{m: m, src: src, buffer: buffer}
+	if err := c.compile(0, len(buffer), false); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type compiler struct {
+	m      *Message
+	src    string
+	buffer []icumsg.Token
+
+	// merge is true if the last instruction is a literal
+	// the next literal can be merged with.
+	merge bool
+}
+
+// slot returns the slot of argument name assigning the next one
+// if the argument has none yet.
+func (c *compiler) slot(name string) int {
+	for i, a := range c.m.args {
+		if a == name {
+			return i
+		}
+	}
+	c.m.args = append(c.m.args, name)
+	return len(c.m.args) - 1
+}
+
+// literal adds the unescaped literal l.
+// '#' becomes opHash if hash is true.
+func (c *compiler) literal(l string, hash bool) {
+	for text, isHash := range icumsg.LiteralSegments(l, hash) {
+		switch {
+		case text == "":
+		case c.merge:
+			c.m.ops[len(c.m.ops)-1].text += text
+		default:
+			c.m.ops = append(c.m.ops, op{code: opLiteral, text: text})
+			c.merge = true
+		}
+		if isHash {
+			c.m.ops = append(c.m.ops, op{code: opHash})
+			c.merge = false
+		}
+	}
+}
+
+// compile compiles buffer[start:end]. hash is true inside
+// options of plural and selectordinal arguments.
+func (c *compiler) compile(start, end int, hash bool) error {
+	for i := start; i < end; i++ {
+		t := c.buffer[i]
+		switch t.Type {
+		case icumsg.TokenTypeLiteral:
+			c.literal(c.src[t.IndexStart:t.IndexEnd], hash)
+		case icumsg.TokenTypeSimpleArg:
+			if err := c.simpleArg(i); err != nil {
+				return err
+			}
+			// Skip the argument name, type and style.
+			i = icumsg.SimpleArgEnd(c.buffer, i)
+		case icumsg.TokenTypePlural, icumsg.TokenTypeSelectOrdinal, icumsg.TokenTypeSelect:
+			if err := c.choice(i, hash); err != nil {
+				return err
+			}
+			i = t.IndexEnd
+		}
+	}
+	return nil
+}
+
+func (c *compiler) simpleArg(index int) error {
+	var a icumsg.Argument
+	for a = range icumsg.Arguments(c.src, c.buffer[index:]) {
+		break
+	}
+	o := op{slot: c.slot(a.Name)}
+	switch {
+	case a.Type == icumsg.TokenTypeSimpleArg:
+		o.code = opSimple
+	case a.Type == icumsg.TokenTypeArgTypeNumber && a.Style == 0:
+		o.code = opNumber
+	case a.Type == icumsg.TokenTypeArgTypeNumber && a.Style == icumsg.TokenTypeArgStyleInteger:
+		o.code = opInteger
+	case a.Type == icumsg.TokenTypeArgTypeNumber && a.Style == icumsg.TokenTypeArgStylePercent:
+		o.code = opPercent
+	case a.Type == icumsg.TokenTypeArgTypeDate && dateLayouts[a.Style] != "":
+		o.code, o.text = opDate, dateLayouts[a.Style]
+	case a.Type == icumsg.TokenTypeArgTypeTime && timeLayouts[a.Style] != "":
+		o.code, o.text = opDate, timeLayouts[a.Style]
+	case a.Type == icumsg.TokenTypeArgTypeDuration && a.Style == 0:
+		o.code = opDuration
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupported, c.buffer[index].String(c.src, c.buffer))
+	}
+	c.m.ops = append(c.m.ops, o)
+	c.merge = false
+	return nil
+}
+
+func (c *compiler) choice(index int, hash bool) error {
+	t := c.buffer[index]
+	ch := &choice{other: -1}
+	o := op{slot: c.slot(c.buffer[index+1].String(c.src, c.buffer)), choice: ch}
+	switch t.Type {
+	case icumsg.TokenTypeSelect:
+		o.code = opSelect
+		ch.keys = map[string]int{}
+	case icumsg.TokenTypePlural:
+		o.code, hash = opPlural, true
+	case icumsg.TokenTypeSelectOrdinal:
+		o.code, hash = opSelectOrdinal, true
+	}
+	if off := c.buffer[index+2]; off.Type == icumsg.TokenTypePluralOffset {
+		ch.offset, _ = strconv.Atoi(c.src[off.IndexStart:off.IndexEnd])
+	}
+	at := len(c.m.ops)
+	c.m.ops = append(c.m.ops, o)
+
+	for j := range icumsg.Options(c.buffer, index) {
+		option := len(ch.options)
+		start := len(c.m.ops)
+		c.merge = false
+		if err := c.compile(j+1, c.buffer[j].IndexEnd, hash); err != nil {
+			return err
+		}
+		ch.options = append(ch.options, span{start: start, end: len(c.m.ops)})
+
+		name := icumsg.OptionName(c.src, c.buffer, j)
+		switch tp := c.buffer[j].Type; {
+		case tp == icumsg.TokenTypeOptionOther:
+			ch.other = option
+		case tp == icumsg.TokenTypeOptionNumber:
+			v, _ := strconv.ParseFloat(name[1:], 64)
+			ch.exact = append(ch.exact, exact{value: v, option: option})
+		case tp == icumsg.TokenTypeOption:
+			ch.keys[name] = option
+		default:
+			for form, category := range categoryOptions {
+				if category == tp {
+					ch.forms[form] = option + 1 // Shifted to tell unset forms apart.
+				}
+			}
+		}
+	}
+	for form := range ch.forms {
+		if ch.forms[form] == 0 {
+			ch.forms[form] = ch.other
+		} else {
+			ch.forms[form]--
+		}
+	}
+	c.m.ops[at].next = len(c.m.ops)
+	c.merge = false
+	return nil
+}
+
+// Locale returns the locale of m.
+func (m *Message) Locale() language.Tag { return m.locale }
+
+// Args returns the argument names by slot in the order of first
+// appearance. The returned slice must not be modified.
+func (m *Message) Args() []string { return m.args }
+
+// Append appends m formatted with args by slot to dst.
+// See Formatter.Format for the accepted values.
+func (m *Message) Append(dst []byte, args ...any) ([]byte, error) {
+	if len(args) != len(m.args) {
+		return dst, fmt.Errorf("%w: got %d, want %d", ErrArgumentCount, len(args), len(m.args))
+	}
+	return m.render(dst, args, 0, len(m.ops), num{})
+}
+
+// Format returns m formatted with args by argument name.
+func (m *Message) Format(args map[string]any) (string, error) {
+	slots := make([]any, len(m.args))
+	for i, name := range m.args {
+		v, ok := args[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrMissingArgument, name)
+		}
+		slots[i] = v
+	}
+	b, err := m.Append(nil, slots...)
+	return string(b), err
+}
+
+// render renders ops[start:end]. hash is the number "#" refers to.
+func (m *Message) render(dst []byte, args []any, start, end int, hash num) ([]byte, error) {
+	for i := start; i < end; {
+		o := &m.ops[i]
+		i++
+		var v any
+		if o.code != opLiteral && o.code != opHash {
+			v = args[o.slot]
+		}
+		switch o.code {
+		case opLiteral:
+			dst = append(dst, o.text...)
+		case opHash:
+			dst = m.symbols.appendDecimal(dst, hash, 3)
+		case opSimple:
+			dst = m.appendValue(dst, v)
+		case opNumber, opInteger, opPercent:
+			n, ok := numeric(v)
+			if !ok {
+				return dst, m.typeErr(o, v)
+			}
+			switch o.code {
+			case opNumber:
+				dst = m.symbols.appendDecimal(dst, n, 3)
+			case opInteger:
+				n.float = math.RoundToEven(n.float)
+				dst = m.symbols.appendDecimal(dst, n, 0)
+			case opPercent:
+				dst = m.symbols.appendPercent(dst, n)
+			}
+		case opDate:
+			t, ok := v.(time.Time)
+			if !ok {
+				return dst, m.typeErr(o, v)
+			}
+			dst = t.AppendFormat(dst, o.text)
+		case opDuration:
+			d, ok := v.(time.Duration)
+			if !ok {
+				n, ok := numeric(v)
+				if !ok {
+					return dst, m.typeErr(o, v)
+				}
+				d = time.Duration(n.float64() * float64(time.Second))
+			}
+			dst = append(dst, d.String()...)
+		case opSelect, opPlural, opSelectOrdinal:
+			option, n, err := m.option(o, v)
+			if err != nil {
+				return dst, err
+			}
+			if o.code == opSelect {
+				n = hash
+			}
+			s := o.choice.options[option]
+			if dst, err = m.render(dst, args, s.start, s.end, n); err != nil {
+				return dst, err
+			}
+			i = o.next
+		}
+	}
+	return dst, nil
+}
+
+// option returns the option of o selected by v and the number "#"
+// refers to in the option of plural and selectordinal arguments.
+func (m *Message) option(o *op, v any) (option int, hash num, err error) {
+	ch := o.choice
+	if o.code == opSelect {
+		var key string
+		switch v := v.(type) {
+		case string:
+			key = v
+		case fmt.Stringer:
+			key = v.String()
+		default:
+			key = fmt.Sprint(v)
+		}
+		if option, ok := ch.keys[key]; ok {
+			return option, num{}, nil
+		}
+		return ch.other, num{}, nil
+	}
+	n, ok := numeric(v)
+	if !ok {
+		return 0, num{}, m.typeErr(o, v)
+	}
+	value := n.float64()
+	n = n.sub(ch.offset)
+	for _, e := range ch.exact {
+		if e.value == value {
+			return e.option, n, nil
+		}
+	}
+	return ch.forms[pluralForm(nil, m.locale, o.code == opSelectOrdinal, n)], n, nil
+}
+
+func (m *Message) typeErr(o *op, v any) error {
+	return fmt.Errorf("%w: %T for argument %q", ErrArgumentType, v, m.args[o.slot])
+}
+
+// appendValue appends v of a simple argument.
+func (m *Message) appendValue(dst []byte, v any) []byte {
+	switch v := v.(type) {
+	case string:
+		return append(dst, v...)
+	case fmt.Stringer:
+		return append(dst, v.String()...)
+	}
+	if n, ok := numeric(v); ok {
+		return m.symbols.appendValue(dst, n, v)
+	}
+	return fmt.Append(dst, v)
+}
+
+// symbols are the number symbols of a locale.
+type symbols struct {
+	// zero is the digit zero, the digits one to nine follow it.
+	zero                  rune
+	minus, group, decimal string
+	nan, infinity         string
+
+	// percent are the prefix and suffix of positive
+	// and negativePercent those of negative percentages.
+	percent, negativePercent [2]string
+
+	// primary is the size of the group of the lowest digits
+	// and secondary the size of the other groups, 0 if there's no grouping.
+	primary, secondary int
+
+	// printer formats floats golang.org/x/text/message formats
+	// in scientific notation.
+	printer *message.Printer
+}
+
+// newSymbols determines the symbols of locale from numbers formatted
+// by golang.org/x/text/message.
+func newSymbols(locale language.Tag) symbols {
+	p := message.NewPrinter(locale)
+	s := symbols{zero: '0', printer: p}
+
+	// For example: "-12,345,678.5", "-1,23,45,678.5" or "؜-١٢٬٣٤٥٬٦٧٨٫٥".
+	var runs [][]rune // Digit runs.
+	var separators []string
+	for _, r := range p.Sprint(number.Decimal(-12345678.5)) {
+		switch {
+		case unicode.IsDigit(r):
+			if len(runs) == len(separators) {
+				runs = append(runs, nil)
+			}
+			runs[len(runs)-1] = append(runs[len(runs)-1], r)
+		case len(runs) == 0:
+			s.minus += string(r)
+		case len(separators) < len(runs):
+			separators = append(separators, string(r))
+		default:
+			separators[len(separators)-1] += string(r)
+		}
+	}
+	if len(runs) > 0 {
+		s.zero = runs[0][0] - 1
+	}
+	if n := len(separators); n > 0 {
+		s.decimal = separators[n-1]
+	}
+	if n := len(runs); n > 2 {
+		s.group = separators[0]
+		s.primary = len(runs[n-2])
+		s.secondary = len(runs[n-3])
+	}
+
+	s.nan = p.Sprint(number.Decimal(math.NaN()))
+	s.infinity = p.Sprint(number.Decimal(math.Inf(1)))
+	s.percent = affixes(p.Sprint(number.Percent(0.25)))
+	s.negativePercent = affixes(p.Sprint(number.Percent(-0.25)))
+	return s
+}
+
+// affixes returns the text before and after the digits of s.
+func affixes(s string) [2]string {
+	first, last := -1, 0
+	for i, r := range s {
+		if unicode.IsDigit(r) {
+			if first == -1 {
+				first = i
+			}
+			last = i + utf8.RuneLen(r)
+		}
+	}
+	if first == -1 {
+		return [2]string{}
+	}
+	return [2]string{s[:first], s[last:]}
+}
+
+// appendValue appends n of a simple argument formatted like %v, which
+// uses scientific notation for floats with exponents below -4 or above 5.
+// v is the argument value of n.
+func (s *symbols) appendValue(dst []byte, n num, v any) []byte {
+	if n.isInt || math.IsNaN(n.float) || math.IsInf(n.float, 0) {
+		return s.appendDecimal(dst, n, 0)
+	}
+	var buf [32]byte
+	b := strconv.AppendFloat(buf[:0], n.float, 'e', -1, n.size)
+	e := bytes.IndexByte(b, 'e')
+	exp := 0
+	for _, c := range b[e+2:] {
+		exp = exp*10 + int(c-'0')
+	}
+	if b[e+1] == '-' {
+		exp = -exp
+	}
+	if exp < -4 || exp > 5 {
+		return append(dst, s.printer.Sprint(v)...)
+	}
+	return s.appendFloat(dst, n.float, -1, n.size)
+}
+
+// appendDecimal appends n with at most fractionDigits fraction digits
+// like golang.org/x/text/number.Decimal.
+func (s *symbols) appendDecimal(dst []byte, n num, fractionDigits int) []byte {
+	if !n.isInt {
+		return s.appendFloat(dst, n.float, fractionDigits, n.size)
+	}
+	var buf [20]byte
+	return s.appendDigits(dst, n.neg, strconv.AppendUint(buf[:0], n.abs, 10), nil)
+}
+
+// appendFloat appends f of bitSize rounded to fractionDigits fraction
+// digits, or with the shortest fraction that represents f exactly if -1.
+func (s *symbols) appendFloat(dst []byte, f float64, fractionDigits, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(dst, s.nan...)
+	case math.IsInf(f, 0):
+		if f < 0 {
+			dst = append(dst, s.minus...)
+		}
+		return append(dst, s.infinity...)
+	}
+	var buf [64]byte
+	b := strconv.AppendFloat(buf[:0], math.Abs(f), 'f', fractionDigits, bitSize)
+	integer, fraction := b, b[:0]
+	if i := bytes.IndexByte(b, '.'); i != -1 {
+		integer, fraction = b[:i], b[i+1:]
+	}
+	for len(fraction) > 0 && fraction[len(fraction)-1] == '0' {
+		fraction = fraction[:len(fraction)-1]
+	}
+	// Negative numbers rounded to zero keep their sign, -0.0 doesn't.
+	return s.appendDigits(dst, f < 0, integer, fraction)
+}
+
+// appendDigits appends the decimal digits of integer grouped
+// and followed by the digits of fraction.
+func (s *symbols) appendDigits(dst []byte, negative bool, integer, fraction []byte) []byte {
+	if negative {
+		dst = append(dst, s.minus...)
+	}
+	for i, c := range integer {
+		if rest := len(integer) - i; i > 0 && s.primary > 0 && (rest == s.primary ||
+			rest > s.primary && (rest-s.primary)%s.secondary == 0) {
+			dst = append(dst, s.group...)
+		}
+		dst = s.appendDigit(dst, c)
+	}
+	if len(fraction) > 0 {
+		dst = append(dst, s.decimal...)
+		for _, c := range fraction {
+			dst = s.appendDigit(dst, c)
+		}
+	}
+	return dst
+}
+
+func (s *symbols) appendDigit(dst []byte, c byte) []byte {
+	if s.zero == '0' {
+		return append(dst, c)
+	}
+	return utf8.AppendRune(dst, s.zero+rune(c-'0'))
+}
+
+// appendPercent appends n multiplied by 100 as an integer percentage
+// like golang.org/x/text/number.Percent.
+func (s *symbols) appendPercent(dst []byte, n num) []byte {
+	if !n.isInt && math.IsNaN(n.float) {
+		return append(dst, s.nan...)
+	}
+	affixes := s.percent
+	if n.neg || n.float < 0 {
+		affixes = s.negativePercent
+	}
+	dst = append(dst, affixes[0]...)
+	var buf [64]byte
+	switch {
+	case n.isInt:
+		digits := strconv.AppendUint(buf[:0], n.abs, 10)
+		if n.abs != 0 {
+			digits = append(digits, "00"...)
+		}
+		dst = s.appendDigits(dst, false, digits, nil)
+	case math.IsInf(n.float, 0):
+		dst = append(dst, s.infinity...)
+	default:
+		// Round to two fraction digits and drop the decimal point.
+		b := strconv.AppendFloat(buf[:0], math.Abs(n.float), 'f', 2, n.size)
+		b = append(b[:len(b)-3], b[len(b)-2:]...)
+		for len(b) > 1 && b[0] == '0' {
+			b = b[1:]
+		}
+		dst = s.appendDigits(dst, false, b, nil)
+	}
+	return append(dst, affixes[1]...)
+}
@@ -0,0 +1,228 @@
+package format_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/format"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestCompile(t *testing.T) {
+	// f checks that the compiled message renders the same as Formatter.
+	f := func(t *testing.T, locale language.Tag, src string, args map[string]any, expect string) {
+		t.Helper()
+		m, err := format.Compile(locale, src)
+		test.RequireNoErr(t, err)
+		s, err := m.Format(args)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, s)
+
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(locale, nil, src)
+		test.RequireNoErr(t, err)
+		s, err = format.Formatter{Locale: locale}.Format(src, buffer, args)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, s)
+	}
+
+	en, de, pl := language.English, language.German, language.Polish
+	f(t, en, "", nil, "")
+	f(t, en, "It''s '{'quoted'}' # '#'", nil, "It's {quoted} # #")
+	f(t, en, "Hello {name}, {n}!", map[string]any{"name": "Ann", "n": 1500}, "Hello Ann, 1,500!")
+	f(t, en, "{n} {n}", map[string]any{"n": -0.5}, "-0.5 -0.5")
+	f(t, de, "{n, number} {n, number, integer} {p, number, percent}",
+		map[string]any{"n": 1234.5, "p": 0.25}, "1.234,5 1.234 25\u00a0%")
+	f(t, language.French, "{n, number}", map[string]any{"n": 1234567.25},
+		"1\u00a0234\u00a0567,25")
+	f(t, language.Hindi, "{n, number}", map[string]any{"n": 12345678}, "1,23,45,678")
+	f(t, language.Arabic, "{n, number}", map[string]any{"n": 1234.5}, "١٬٢٣٤٫٥")
+
+	// Integers aren't converted to float64.
+	numbers := "{n} {n, number} {n, number, integer} {n, number, percent} " +
+		"{n, plural, other {#}}"
+	f(t, en, numbers, map[string]any{"n": uint64(math.MaxUint64)},
+		"18,446,744,073,709,551,615 18,446,744,073,709,551,615 "+
+			"18,446,744,073,709,551,615 1,844,674,407,370,955,161,500% "+
+			"18,446,744,073,709,551,615")
+	f(t, en, numbers, map[string]any{"n": int64(math.MinInt64)},
+		"-9,223,372,036,854,775,808 -9,223,372,036,854,775,808 "+
+			"-9,223,372,036,854,775,808 -922,337,203,685,477,580,800% "+
+			"-9,223,372,036,854,775,808")
+	f(t, en, numbers, map[string]any{"n": int64(1)<<60 + 1},
+		"1,152,921,504,606,846,977 1,152,921,504,606,846,977 "+
+			"1,152,921,504,606,846,977 115,292,150,460,684,697,700% "+
+			"1,152,921,504,606,846,977")
+	f(t, en, "{n, plural, offset:1 other {#}}", map[string]any{"n": uint64(math.MaxUint64)},
+		"18,446,744,073,709,551,614")
+
+	// Floats of simple arguments are formatted like %v with all digits,
+	// floats of number arguments and "#" with at most three fraction digits.
+	f(t, en, numbers, map[string]any{"n": 1234567.891},
+		"1.234567891×10⁰⁶ 1,234,567.891 1,234,568 123,456,789% 1,234,567.891")
+	f(t, de, numbers, map[string]any{"n": 1e-7}, "1·10⁻⁰⁷ 0 0 0 % 0")
+	f(t, en, numbers, map[string]any{"n": 1.23456789}, "1.23456789 1.235 1 123% 1.235")
+	f(t, en, numbers, map[string]any{"n": 999999.9}, "999,999.9 999,999.9 1,000,000 99,999,990% 999,999.9")
+	f(t, en, numbers, map[string]any{"n": 0.0005}, "0.0005 0.001 0 0% 0.001")
+	f(t, en, numbers, map[string]any{"n": float32(1.1)}, "1.1 1.1 1 110% 1.1")
+	f(t, en, numbers, map[string]any{"n": -0.0004}, "-0.0004 -0 0 -0% -0")
+	f(t, en, numbers, map[string]any{"n": math.Copysign(0, -1)}, "0 0 0 0% 0")
+	f(t, en, numbers, map[string]any{"n": 2.675}, "2.675 2.675 3 267% 2.675")
+	f(t, en, numbers, map[string]any{"n": 1e21},
+		"1×10²¹ 1,000,000,000,000,000,000,000 1,000,000,000,000,000,000,000 "+
+			"100,000,000,000,000,000,000,000% 1,000,000,000,000,000,000,000")
+	f(t, en, numbers, map[string]any{"n": math.NaN()}, "NaN NaN NaN NaN NaN")
+	f(t, en, numbers, map[string]any{"n": math.Inf(-1)}, "-∞ -∞ -∞ -∞% -∞")
+	f(t, language.Arabic, numbers, map[string]any{"n": math.NaN()},
+		"ليس رقم ليس رقم ليس رقم ليس رقم ليس رقم")
+	f(t, language.Arabic, "{n, number, percent}", map[string]any{"n": -0.5}, "؜-٥٠٪؜")
+
+	d := time.Date(2025, 3, 7, 14, 5, 9, 0, time.UTC)
+	f(t, en, "{d, date} {d, date, short} {d, date, full} {d, time, short} {d, time, long}",
+		map[string]any{"d": d}, "Mar 7, 2025 3/7/25 Friday, March 7, 2025 14:05 14:05:09 UTC")
+	f(t, en, "{d, duration} {s, duration}",
+		map[string]any{"d": 90 * time.Second, "s": 1.5}, "1m30s 1.5s")
+
+	files := "{n, plural, =0 {no files} one {# file} other {# files}}"
+	f(t, en, files, map[string]any{"n": 0}, "no files")
+	f(t, en, files, map[string]any{"n": 1}, "1 file")
+	f(t, en, files, map[string]any{"n": uint8(2)}, "2 files")
+	f(t, en, files, map[string]any{"n": 1.5}, "1.5 files")
+	f(t, en, files, map[string]any{"n": 2500}, "2,500 files")
+
+	pl3 := "{n, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}"
+	f(t, pl, pl3, map[string]any{"n": 1}, "1 plik")
+	f(t, pl, pl3, map[string]any{"n": 22}, "22 pliki")
+	f(t, pl, pl3, map[string]any{"n": 25}, "25 plików")
+	f(t, pl, pl3, map[string]any{"n": 2.5}, "2,5 pliku")
+
+	offset := "{n, plural, offset:1 =0 {nobody} =1 {{name}} one {{name} and # other} " +
+		"other {{name} and # others}}"
+	f(t, en, offset, map[string]any{"n": 0, "name": "Ann"}, "nobody")
+	f(t, en, offset, map[string]any{"n": 1, "name": "Ann"}, "Ann")
+	f(t, en, offset, map[string]any{"n": 2, "name": "Ann"}, "Ann and 1 other")
+	f(t, en, offset, map[string]any{"n": 3, "name": "Ann"}, "Ann and 2 others")
+
+	ordinal := "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}"
+	f(t, en, ordinal, map[string]any{"n": 1}, "1st")
+	f(t, en, ordinal, map[string]any{"n": 22}, "22nd")
+	f(t, en, ordinal, map[string]any{"n": 13}, "13th")
+
+	gender := "{g, select, female {She} male {He} other {They}} " +
+		"{g, select, other {# {n, plural, one {#} other {# '#'}}}}"
+	f(t, en, gender, map[string]any{"g": "female", "n": 1}, "She # 1")
+	f(t, en, gender, map[string]any{"g": "x", "n": 2}, "They # 2 #")
+	f(t, en, "{a, select, x {X} other {O}}-{b, select, other {B}}",
+		map[string]any{"a": "x", "b": 1}, "X-B")
+}
+
+func TestCompileArgs(t *testing.T) {
+	m, err := format.Compile(language.English,
+		"{b} {a, plural, other {{c} # {b}}} {d, select, other {{a}}}")
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, []string{"b", "a", "c", "d"}, m.Args())
+	test.RequireEqual(t, language.English, m.Locale())
+
+	b, err := m.Append([]byte("> "), "B", 2, "C", "D")
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "> B C 2 B 2", string(b))
+}
+
+func TestCompileTokens(t *testing.T) {
+	src := "{n, plural, one {# file} other {# files}}"
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.German, nil, src)
+	test.RequireNoErr(t, err)
+	m, err := format.CompileTokens(language.German, src, buffer)
+	test.RequireNoErr(t, err)
+	b, err := m.Append(nil, 1500)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "1.500 files", string(b))
+}
+
+func TestCompileErr(t *testing.T) {
+	f := func(t *testing.T, expect error, src string) {
+		t.Helper()
+		_, err := format.Compile(language.English, src)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, icumsg.ErrUnclosedQuote, "'{")
+	f(t, format.ErrUnsupported, "{n, spellout}")
+	f(t, format.ErrUnsupported, "{n, ordinal}")
+	f(t, format.ErrUnsupported, "{n, number, currency}")
+	f(t, format.ErrUnsupported, "{n, number, ::compact-short}")
+	f(t, format.ErrUnsupported, "{d, date, yyyy}")
+}
+
+func TestMessageAppendErr(t *testing.T) {
+	f := func(t *testing.T, expect error, src string, args ...any) {
+		t.Helper()
+		m, err := format.Compile(language.English, src)
+		test.RequireNoErr(t, err)
+		_, err = m.Append(nil, args...)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, format.ErrArgumentCount, "{x}")
+	f(t, format.ErrArgumentCount, "{x}", 1, 2)
+	f(t, format.ErrArgumentType, "{n, number}", "1")
+	f(t, format.ErrArgumentType, "{n, plural, other {#}}", "1")
+	f(t, format.ErrArgumentType, "{d, date}", "2025-01-01")
+	f(t, format.ErrArgumentType, "{d, duration}", "1s")
+
+	m, err := format.Compile(language.English, "{x} {y}")
+	test.RequireNoErr(t, err)
+	_, err = m.Format(map[string]any{"x": 1})
+	test.RequireErrIs(t, format.ErrMissingArgument, err)
+}
+
+func TestMessageAppendAllocs(t *testing.T) {
+	m, err := format.Compile(language.Polish,
+		"{name} ma {n, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}} "+
+			"({p, number, percent}, {g, select, a {A} other {{n, number, integer}}}) {f}")
+	test.RequireNoErr(t, err)
+	args := []any{"Ann", 1234, 0.5, "b", 1.25}
+	dst := make([]byte, 0, 256)
+	allocs := testing.AllocsPerRun(100, func() {
+		var err error
+		if dst, err = m.Append(dst[:0], args...); err != nil {
+			t.Fatal(err)
+		}
+	})
+	test.RequireEqual(t, "Ann ma 1\u00a0234 pliki (50%, 1\u00a0234) 1,25", string(dst))
+	test.RequireEqual(t, 0.0, allocs)
+}
+
+func TestMessageConcurrent(t *testing.T) {
+	m, err := format.Compile(language.English,
+		"{n, plural, =0 {no files} one {# file} other {# files}}")
+	test.RequireNoErr(t, err)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				b, err := m.Append(nil, i)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				expect := map[int]string{0: "no files", 1: "1 file"}[i]
+				if expect == "" {
+					expect = string(rune('0'+i)) + " files"
+				}
+				if string(b) != expect {
+					t.Errorf("expected %q, received %q", expect, b)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,441 @@
+// Package format formats ICU messages with argument values.
+//
+// Numbers are formatted with golang.org/x/text/message and plural
+// categories are determined with golang.org/x/text/feature/plural,
+// restricted to the categories of a PluralRuleProvider if one is set.
+// Dates and times are formatted with fixed layouts that aren't localized.
+package format
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/romshark/icumsg"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	ErrMissingArgument = errors.New("missing argument")
+
+	// ErrArgumentType is returned for argument values of a type
+	// the argument doesn't accept, such as strings for number arguments.
+	ErrArgumentType = errors.New("invalid argument type")
+
+	// ErrUnsupported is returned for argument types and styles that
+	// can't be formatted: spellout, ordinal, currency, custom styles
+	// and skeletons.
+	ErrUnsupported = errors.New("unsupported argument")
+)
+
+// Formatter formats ICU messages of a locale.
+type Formatter struct {
+	Locale language.Tag
+
+	// Plurals restricts the plural categories selected for plural and
+	// selectordinal arguments to those it supports for Locale if it's
+	// not nil, categories it doesn't support select option "other".
+	// It should be the provider the messages were tokenized with.
+	// Categories are always determined by the rules of
+	// golang.org/x/text/feature/plural.
+	Plurals icumsg.PluralRuleProvider
+
+	// EscapeArgument is applied to the formatted values of arguments
+	// including "#" if it's not nil.
+	EscapeArgument func(string) string
+
+	// EscapeLiteral is applied to the unescaped literals of the message
+	// if it's not nil.
+	EscapeLiteral func(string) string
+}
+
+// Format formats the ICU message src tokenized into buffer with args
+// by argument name. Arguments accept the following values:
+//
+//   - simple arguments ({name}) accept any value, numbers are localized
+//   - number, plural and selectordinal arguments accept integers and floats
+//   - select arguments accept any value, the option is selected by the
+//     value formatted with fmt.Sprint
+//   - date and time arguments accept time.Time
+//   - duration arguments accept time.Duration and numbers of seconds
+func (f Formatter) Format(src string, buffer []icumsg.Token, args map[string]any) (string, error) {
+	s := formatter{
+		Formatter: f,
+		src:       src,
+		buffer:    buffer,
+		args:      args,
+		printer:   message.NewPrinter(f.Locale),
+	}
+	if err := s.format(0, len(buffer), nil); err != nil {
+		return "", err
+	}
+	return s.b.String(), nil
+}
+
+type formatter struct {
+	Formatter
+	src     string
+	buffer  []icumsg.Token
+	args    map[string]any
+	printer *message.Printer
+	b       strings.Builder
+}
+
+// format formats buffer[start:end]. hash is the number "#" refers to
+// and nil outside of plural and selectordinal options.
+func (f *formatter) format(start, end int, hash *num) error {
+	for i := start; i < end; i++ {
+		t := f.buffer[i]
+		switch t.Type {
+		case icumsg.TokenTypeLiteral:
+			f.literal(f.src[t.IndexStart:t.IndexEnd], hash)
+		case icumsg.TokenTypeSimpleArg:
+			if err := f.simpleArg(i); err != nil {
+				return err
+			}
+			// Skip the argument name, type and style.
+			i = icumsg.SimpleArgEnd(f.buffer, i)
+		case icumsg.TokenTypePlural, icumsg.TokenTypeSelectOrdinal, icumsg.TokenTypeSelect:
+			if err := f.choice(i, hash); err != nil {
+				return err
+			}
+			i = t.IndexEnd
+		}
+	}
+	return nil
+}
+
+// literal writes the unescaped literal l replacing '#' by hash
+// unless hash is nil.
+func (f *formatter) literal(l string, hash *num) {
+	for text, isHash := range icumsg.LiteralSegments(l, hash != nil) {
+		f.writeLiteral(text)
+		if isHash {
+			f.writeArgument(f.printer.Sprint(number.Decimal(hash.value())))
+		}
+	}
+}
+
+func (f *formatter) writeLiteral(s string) {
+	if f.EscapeLiteral != nil {
+		s = f.EscapeLiteral(s)
+	}
+	f.b.WriteString(s)
+}
+
+func (f *formatter) writeArgument(s string) {
+	if f.EscapeArgument != nil {
+		s = f.EscapeArgument(s)
+	}
+	f.b.WriteString(s)
+}
+
+func (f *formatter) arg(name string) (any, error) {
+	v, ok := f.args[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMissingArgument, name)
+	}
+	return v, nil
+}
+
+// Layouts of date and time styles.
+var (
+	dateLayouts = map[icumsg.TokenType]string{
+		0:                              "Jan 2, 2006",
+		icumsg.TokenTypeArgStyleShort:  "1/2/06",
+		icumsg.TokenTypeArgStyleMedium: "Jan 2, 2006",
+		icumsg.TokenTypeArgStyleLong:   "January 2, 2006",
+		icumsg.TokenTypeArgStyleFull:   "Monday, January 2, 2006",
+	}
+	timeLayouts = map[icumsg.TokenType]string{
+		0:                              "15:04:05",
+		icumsg.TokenTypeArgStyleShort:  "15:04",
+		icumsg.TokenTypeArgStyleMedium: "15:04:05",
+		icumsg.TokenTypeArgStyleLong:   "15:04:05 MST",
+		icumsg.TokenTypeArgStyleFull:   "15:04:05 MST",
+	}
+)
+
+// simpleArg formats the simple argument at buffer[index].
+func (f *formatter) simpleArg(index int) error {
+	var a icumsg.Argument
+	for a = range icumsg.Arguments(f.src, f.buffer[index:]) {
+		break
+	}
+	v, err := f.arg(a.Name)
+	if err != nil {
+		return err
+	}
+	typeErr := func() error {
+		return fmt.Errorf("%w: %T for %s", ErrArgumentType, v,
+			f.buffer[index].String(f.src, f.buffer))
+	}
+	var s string
+	switch a.Type {
+	case icumsg.TokenTypeSimpleArg:
+		s = f.printer.Sprint(v)
+	case icumsg.TokenTypeArgTypeNumber:
+		n, ok := numeric(v)
+		if !ok {
+			return typeErr()
+		}
+		switch a.Style {
+		case 0:
+			s = f.printer.Sprint(number.Decimal(n.value()))
+		case icumsg.TokenTypeArgStyleInteger:
+			if !n.isInt {
+				n.float = math.RoundToEven(n.float)
+			}
+			s = f.printer.Sprint(number.Decimal(n.value()))
+		case icumsg.TokenTypeArgStylePercent:
+			s = f.printer.Sprint(number.Percent(n.value()))
+		default:
+			return fmt.Errorf("%w: %s", ErrUnsupported, f.buffer[index].String(f.src, f.buffer))
+		}
+	case icumsg.TokenTypeArgTypeDate, icumsg.TokenTypeArgTypeTime:
+		t, ok := v.(time.Time)
+		if !ok {
+			return typeErr()
+		}
+		layouts := dateLayouts
+		if a.Type == icumsg.TokenTypeArgTypeTime {
+			layouts = timeLayouts
+		}
+		layout, ok := layouts[a.Style]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnsupported, f.buffer[index].String(f.src, f.buffer))
+		}
+		s = t.Format(layout)
+	case icumsg.TokenTypeArgTypeDuration:
+		switch v := v.(type) {
+		case time.Duration:
+			s = v.String()
+		default:
+			n, ok := numeric(v)
+			if !ok {
+				return typeErr()
+			}
+			s = time.Duration(n.float64() * float64(time.Second)).String()
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupported, f.buffer[index].String(f.src, f.buffer))
+	}
+	f.writeArgument(s)
+	return nil
+}
+
+// choice formats the option of the plural, selectordinal or select
+// argument at buffer[index] that matches the value of the argument.
+func (f *formatter) choice(index int, hash *num) error {
+	t := f.buffer[index]
+	name := f.buffer[index+1].String(f.src, f.buffer)
+	v, err := f.arg(name)
+	if err != nil {
+		return err
+	}
+
+	if t.Type == icumsg.TokenTypeSelect {
+		key := fmt.Sprint(v)
+		option := -1
+		for j := range icumsg.Options(f.buffer, index) {
+			if n := icumsg.OptionName(f.src, f.buffer, j); n == key ||
+				option == -1 && n == "other" {
+				option = j
+			}
+		}
+		return f.format(option+1, f.buffer[option].IndexEnd, hash)
+	}
+
+	n, ok := numeric(v)
+	if !ok {
+		return fmt.Errorf("%w: %T for %s %q", ErrArgumentType, v, t.Type, name)
+	}
+	value := n.float64()
+	if o := f.buffer[index+2]; o.Type == icumsg.TokenTypePluralOffset {
+		offset, _ := strconv.Atoi(f.src[o.IndexStart:o.IndexEnd])
+		n = n.sub(offset)
+	}
+	ordinal := t.Type == icumsg.TokenTypeSelectOrdinal
+	category := categoryOptions[pluralForm(f.Plurals, f.Locale, ordinal, n)]
+
+	// Options "=n" match the value before subtracting the offset and
+	// take precedence over categories.
+	exact, option, other := -1, -1, -1
+	for j := range icumsg.Options(f.buffer, index) {
+		switch o := f.buffer[j].Type; {
+		case o == icumsg.TokenTypeOptionNumber:
+			x, _ := strconv.ParseFloat(icumsg.OptionName(f.src, f.buffer, j)[1:], 64)
+			if exact == -1 && x == value {
+				exact = j
+			}
+		case o == category:
+			option = j
+		case o == icumsg.TokenTypeOptionOther:
+			other = j
+		}
+	}
+	if exact != -1 {
+		option = exact
+	} else if option == -1 {
+		option = other
+	}
+	return f.format(option+1, f.buffer[option].IndexEnd, &n)
+}
+
+var categoryOptions = map[plural.Form]icumsg.TokenType{
+	plural.Zero:  icumsg.TokenTypeOptionZero,
+	plural.One:   icumsg.TokenTypeOptionOne,
+	plural.Two:   icumsg.TokenTypeOptionTwo,
+	plural.Few:   icumsg.TokenTypeOptionFew,
+	plural.Many:  icumsg.TokenTypeOptionMany,
+	plural.Other: icumsg.TokenTypeOptionOther,
+}
+
+// pluralForm returns the plural form of n in locale.
+// The form is "other" if plurals isn't nil and doesn't support it.
+func pluralForm(
+	plurals icumsg.PluralRuleProvider, locale language.Tag, ordinal bool, n num,
+) plural.Form {
+	rules := plural.Cardinal
+	if ordinal {
+		rules = plural.Ordinal
+	}
+	form := matchPlural(rules, locale, n)
+	if plurals == nil {
+		return form
+	}
+	r, o := plurals.PluralRules(locale)
+	if ordinal {
+		r = o
+	}
+	supported := true
+	switch form {
+	case plural.Zero:
+		supported = r.Zero
+	case plural.One:
+		supported = r.One
+	case plural.Two:
+		supported = r.Two
+	case plural.Few:
+		supported = r.Few
+	case plural.Many:
+		supported = r.Many
+	}
+	if !supported {
+		return plural.Other
+	}
+	return form
+}
+
+// matchPlural returns the plural form of n in locale.
+func matchPlural(rules *plural.Rules, locale language.Tag, n num) plural.Form {
+	var buf [64]byte
+	b := n.appendAbs(buf[:0])
+	// The operands may be passed modulo 10,000,000.
+	var i, v, f int
+	fraction := false
+	for _, c := range b {
+		switch {
+		case c == '.':
+			fraction = true
+		case fraction:
+			f = (f*10 + int(c-'0')) % 10_000_000
+			v++
+		default:
+			i = (i*10 + int(c-'0')) % 10_000_000
+		}
+	}
+	return rules.MatchPlural(locale, i, v, v, f, f)
+}
+
+// num is a numeric argument value. Integers are kept exact since
+// not all int64 and uint64 values are representable as float64.
+type num struct {
+	isInt bool
+	neg   bool    // neg is true for negative integers.
+	abs   uint64  // abs is the absolute value of integers.
+	float float64 // float is the value of floats.
+	size  int     // size is the bit size of floats, 32 or 64.
+}
+
+// numeric returns v as num if it's an integer or a float.
+func numeric(v any) (num, bool) {
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := r.Int()
+		if i < 0 {
+			return num{isInt: true, neg: true, abs: -uint64(i)}, true
+		}
+		return num{isInt: true, abs: uint64(i)}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return num{isInt: true, abs: r.Uint()}, true
+	case reflect.Float32:
+		return num{float: r.Float(), size: 32}, true
+	case reflect.Float64:
+		return num{float: r.Float(), size: 64}, true
+	}
+	return num{}, false
+}
+
+// float64 returns n as float64, rounding integers beyond 2^53.
+func (n num) float64() float64 {
+	if !n.isInt {
+		return n.float
+	}
+	if n.neg {
+		return -float64(n.abs)
+	}
+	return float64(n.abs)
+}
+
+// value returns n as int64, uint64, float32 or float64 for
+// golang.org/x/text/number.
+func (n num) value() any {
+	switch {
+	case n.size == 32:
+		return float32(n.float)
+	case !n.isInt:
+		return n.float
+	case n.neg:
+		return -int64(n.abs)
+	}
+	return n.abs
+}
+
+// sub returns n minus the plural offset.
+func (n num) sub(offset int) num {
+	if !n.isInt {
+		n.float -= float64(offset)
+		return n
+	}
+	switch o := uint64(offset); {
+	case n.neg && n.abs > 1<<63-o:
+		// Beyond the range of int64.
+		return num{float: n.float64() - float64(offset), size: 64}
+	case n.neg:
+		n.abs += o
+	case n.abs >= o:
+		n.abs -= o
+	default:
+		n.neg, n.abs = true, o-n.abs
+	}
+	return n
+}
+
+// appendAbs appends the decimal digits of the absolute value of n
+// with the shortest fraction that represents floats exactly.
+func (n num) appendAbs(dst []byte) []byte {
+	if n.isInt {
+		return strconv.AppendUint(dst, n.abs, 10)
+	}
+	return strconv.AppendFloat(dst, math.Abs(n.float), 'f', -1, n.size)
+}
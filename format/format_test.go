@@ -0,0 +1,139 @@
+package format_test
+
+import (
+	"html"
+	"testing"
+	"time"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/format"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestFormat(t *testing.T) {
+	f := func(t *testing.T, locale language.Tag, src string, args map[string]any, expect string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(locale, nil, src)
+		test.RequireNoErr(t, err)
+		s, err := format.Formatter{Locale: locale}.Format(src, buffer, args)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, s)
+	}
+
+	en, de, pl := language.English, language.German, language.Polish
+	f(t, en, "", nil, "")
+	f(t, en, "It''s '{'quoted'}' # '#'", nil, "It's {quoted} # #")
+	f(t, en, "Hello {name}, {n}!", map[string]any{"name": "Ann", "n": 1500}, "Hello Ann, 1,500!")
+	f(t, de, "{n, number} {n, number, integer} {p, number, percent}",
+		map[string]any{"n": 1234.5, "p": 0.25}, "1.234,5 1.234 25\u00a0%")
+	d := time.Date(2025, 3, 7, 14, 5, 9, 0, time.UTC)
+	f(t, en, "{d, date} {d, date, short} {d, date, full} {d, time, short} {d, time, long}",
+		map[string]any{"d": d}, "Mar 7, 2025 3/7/25 Friday, March 7, 2025 14:05 14:05:09 UTC")
+	f(t, en, "{d, duration} {s, duration}",
+		map[string]any{"d": 90 * time.Second, "s": 1.5}, "1m30s 1.5s")
+
+	files := "{n, plural, =0 {no files} one {# file} other {# files}}"
+	f(t, en, files, map[string]any{"n": 0}, "no files")
+	f(t, en, files, map[string]any{"n": 1}, "1 file")
+	f(t, en, files, map[string]any{"n": uint8(2)}, "2 files")
+	f(t, en, files, map[string]any{"n": 1.0}, "1 file")
+	f(t, en, files, map[string]any{"n": 1.5}, "1.5 files")
+	f(t, en, files, map[string]any{"n": 2500}, "2,500 files")
+
+	pl3 := "{n, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}"
+	f(t, pl, pl3, map[string]any{"n": 1}, "1 plik")
+	f(t, pl, pl3, map[string]any{"n": 22}, "22 pliki")
+	f(t, pl, pl3, map[string]any{"n": 25}, "25 plików")
+	f(t, pl, pl3, map[string]any{"n": 2.5}, "2,5 pliku")
+
+	offset := "{n, plural, offset:1 =0 {nobody} =1 {{name}} one {{name} and # other} " +
+		"other {{name} and # others}}"
+	f(t, en, offset, map[string]any{"n": 0, "name": "Ann"}, "nobody")
+	f(t, en, offset, map[string]any{"n": 1, "name": "Ann"}, "Ann")
+	f(t, en, offset, map[string]any{"n": 2, "name": "Ann"}, "Ann and 1 other")
+	f(t, en, offset, map[string]any{"n": 3, "name": "Ann"}, "Ann and 2 others")
+
+	ordinal := "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}"
+	f(t, en, ordinal, map[string]any{"n": 1}, "1st")
+	f(t, en, ordinal, map[string]any{"n": 22}, "22nd")
+	f(t, en, ordinal, map[string]any{"n": 13}, "13th")
+
+	gender := "{g, select, female {She} male {He} other {They}} " +
+		"{g, select, other {# {n, plural, one {#} other {# '#'}}}}"
+	f(t, en, gender, map[string]any{"g": "female", "n": 1}, "She # 1")
+	f(t, en, gender, map[string]any{"g": "x", "n": 2}, "They # 2 #")
+}
+
+func TestFormatEscape(t *testing.T) {
+	src := "<b>{name}</b> has {n, plural, one {# <i>file</i>} other {# files}}"
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.English, nil, src)
+	test.RequireNoErr(t, err)
+	args := map[string]any{"name": "<script>", "n": 1}
+
+	s, err := format.Formatter{
+		Locale: language.English, EscapeArgument: html.EscapeString,
+	}.Format(src, buffer, args)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "<b>&lt;script&gt;</b> has 1 <i>file</i>", s)
+
+	s, err = format.Formatter{
+		Locale: language.English, EscapeArgument: html.EscapeString,
+		EscapeLiteral: html.EscapeString,
+	}.Format(src, buffer, args)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "&lt;b&gt;&lt;script&gt;&lt;/b&gt; has 1 &lt;i&gt;file&lt;/i&gt;", s)
+}
+
+func TestFormatErr(t *testing.T) {
+	f := func(t *testing.T, expect error, src string, args map[string]any) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		_, err = format.Formatter{Locale: language.English}.Format(src, buffer, args)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, format.ErrMissingArgument, "{x}", nil)
+	f(t, format.ErrMissingArgument, "{n, plural, other {{x}}}", map[string]any{"n": 1})
+	f(t, format.ErrMissingArgument, "{g, select, other {x}}", nil)
+	f(t, format.ErrArgumentType, "{n, number}", map[string]any{"n": "1"})
+	f(t, format.ErrArgumentType, "{n, plural, other {#}}", map[string]any{"n": "1"})
+	f(t, format.ErrArgumentType, "{d, date}", map[string]any{"d": "2025-01-01"})
+	f(t, format.ErrArgumentType, "{d, duration}", map[string]any{"d": "1s"})
+	f(t, format.ErrUnsupported, "{n, spellout}", map[string]any{"n": 1})
+	f(t, format.ErrUnsupported, "{n, ordinal}", map[string]any{"n": 1})
+	f(t, format.ErrUnsupported, "{n, number, currency}", map[string]any{"n": 1})
+	f(t, format.ErrUnsupported, "{n, number, ::compact-short}", map[string]any{"n": 1})
+	f(t, format.ErrUnsupported, "{d, date, yyyy}", map[string]any{"d": time.Time{}})
+}
+
+func TestFormatPlurals(t *testing.T) {
+	src := "{n, plural, one {# file} other {# files}} {n, selectordinal, one {#st} other {#th}}"
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.English, nil, src)
+	test.RequireNoErr(t, err)
+	args := map[string]any{"n": 1}
+
+	s, err := format.Formatter{Locale: language.English}.Format(src, buffer, args)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "1 file 1st", s)
+
+	// Categories the provider doesn't support select "other".
+	var plurals icumsg.PluralRuleRegistry
+	plurals.Register(language.English, icumsg.PluralRules{}, icumsg.PluralRules{})
+	s, err = format.Formatter{
+		Locale: language.English, Plurals: &plurals,
+	}.Format(src, buffer, args)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "1 files 1th", s)
+
+	s, err = format.Formatter{
+		Locale: language.English, Plurals: icumsg.CLDRPluralRules{},
+	}.Format(src, buffer, args)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "1 file 1st", s)
+}
@@ -0,0 +1,326 @@
+package gettext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+	cldrdata "github.com/romshark/icumsg/internal/cldr"
+	"github.com/romshark/icumsg/internal/printf"
+	"golang.org/x/text/language"
+)
+
+var (
+	// ErrPluralFormCount is returned by Mapping.ToICU for plural messages
+	// with a number of plural forms other than nplurals.
+	ErrPluralFormCount = errors.New("number of plural forms differs from nplurals")
+
+	// ErrAmbiguousCategory is wrapped by Mapping.Err if the integer
+	// samples of a plural category fall into different plural forms.
+	ErrAmbiguousCategory = errors.New("plural category spans multiple plural forms")
+
+	// ErrUnusedForm is wrapped by Mapping.Err if no plural
+	// category maps to a plural form.
+	ErrUnusedForm = errors.New("plural form not used by any plural category")
+
+	// ErrMergedCategories is wrapped by the errors of Mapping.FromICU
+	// if plural categories that map to the same plural form have
+	// options with different contents.
+	ErrMergedCategories = errors.New("plural categories with different contents share a plural form")
+
+	// ErrUnrepresentable is wrapped by the errors of Mapping.FromICU
+	// for parts of ICU messages gettext can't express, such as arguments
+	// other than the plural argument, offsets and "=n" options.
+	ErrUnrepresentable = errors.New("not representable in gettext")
+)
+
+// Mapping maps the plural form indexes of gettext to the cardinal
+// CLDR plural categories of a locale.
+//
+// A category maps to the plural form most of the integer samples of
+// the CLDR plural rules fall into, the lowest one if tied. Categories
+// without integer samples, such as "other" in Polish which applies to
+// fractions only, map to the last plural form.
+type Mapping struct {
+	Locale language.Tag
+	Forms  PluralForms
+
+	categories cldr.Categories
+	index      [cldr.CategoryOther + 1]int // Plural form index by category.
+	ambiguous  cldr.Categories
+	primary    cldr.Categories // Categories with integer samples.
+}
+
+// NewMapping creates the mapping between forms and the cardinal
+// plural categories of locale.
+func NewMapping(locale language.Tag, forms PluralForms) *Mapping {
+	m := &Mapping{Locale: locale, Forms: forms}
+	m.categories, _ = cldr.LocaleCategories(locale)
+	samples, ok := cldrdata.CardinalSamplesByTag[locale]
+	if !ok {
+		base, _ := locale.Base()
+		samples = cldrdata.CardinalSamplesByBase[base]
+	}
+	for c := range m.categories.All() {
+		var s []uint64
+		switch c {
+		case cldr.CategoryZero:
+			s = samples.Zero
+		case cldr.CategoryOne:
+			s = samples.One
+		case cldr.CategoryTwo:
+			s = samples.Two
+		case cldr.CategoryFew:
+			s = samples.Few
+		case cldr.CategoryMany:
+			s = samples.Many
+		case cldr.CategoryOther:
+			s = samples.Other
+		}
+		if len(s) == 0 {
+			m.index[c] = forms.N - 1
+			continue
+		}
+		m.primary = m.primary.With(c)
+		counts := make([]int, forms.N)
+		for _, n := range s {
+			counts[forms.Index(n)]++
+		}
+		for i, count := range counts {
+			if count > counts[m.index[c]] {
+				m.index[c] = i
+			}
+			if count > 0 && count < len(s) {
+				m.ambiguous = m.ambiguous.With(c)
+			}
+		}
+	}
+	return m
+}
+
+// Index returns the plural form index of category c.
+func (m *Mapping) Index(c cldr.Category) int { return m.index[c] }
+
+// Categories returns the categories mapped to plural form index i.
+func (m *Mapping) Categories(i int) cldr.Categories {
+	var s cldr.Categories
+	for c := range m.categories.All() {
+		if m.index[c] == i {
+			s = s.With(c)
+		}
+	}
+	return s
+}
+
+// Err returns nil if the mapping is lossless. Otherwise the returned
+// error wraps ErrAmbiguousCategory if the integer samples of a category
+// fall into different plural forms and ErrUnusedForm if no category
+// maps to a plural form.
+func (m *Mapping) Err() error {
+	var errs []error
+	for c := range m.ambiguous.All() {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrAmbiguousCategory, c))
+	}
+	for i := range m.Forms.N {
+		if m.Categories(i) == 0 {
+			errs = append(errs, fmt.Errorf("%w: %d", ErrUnusedForm, i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ToICU converts msg to an ICU message. Plural messages become a plural
+// argument named arg with an option for every category of the locale
+// taken from the plural form the category maps to.
+// Text is escaped (see icumsg.EscapeLiteral) and, in plural forms,
+// number specifiers such as "%d", "%i", "%ld" and "%1$d" become "#".
+// Other specifiers and escaped "%%" are kept. Untranslated plural
+// messages, such as in POT files, convert msgid and msgid_plural as
+// the plural forms of "nplurals=2; plural=(n != 1);".
+//
+// The message is converted even if the conversion isn't lossless,
+// in which case the returned error wraps the reasons (see Mapping.Err).
+// For plural messages with a number of plural forms other than nplurals,
+// ErrPluralFormCount is returned instead.
+func (m *Mapping) ToICU(msg Message, arg string) (string, error) {
+	if !msg.IsPlural() {
+		if len(msg.Str) == 0 || msg.Str[0] == "" {
+			return icumsg.EscapeLiteral(msg.ID), nil
+		}
+		return icumsg.EscapeLiteral(msg.Str[0]), nil
+	}
+	forms := msg.Str
+	untranslated := true
+	for _, s := range forms {
+		untranslated = untranslated && s == ""
+	}
+	mapping := m
+	if untranslated {
+		forms = []string{msg.ID, msg.IDPlural}
+		mapping = NewMapping(m.Locale, germanicForms)
+	}
+	if len(forms) != mapping.Forms.N {
+		return "", fmt.Errorf("%w: %d instead of %d",
+			ErrPluralFormCount, len(forms), mapping.Forms.N)
+	}
+	var b strings.Builder
+	b.WriteString("{" + arg + ", plural,")
+	for c := range mapping.categories.All() {
+		b.WriteString(" " + c.String() + " {")
+		hashNumbers(&b, forms[mapping.index[c]])
+		b.WriteString("}")
+	}
+	b.WriteString("}")
+	return b.String(), mapping.Err()
+}
+
+// hashNumbers writes the escaped plural form s to b replacing
+// number specifiers by "#".
+func hashNumbers(b *strings.Builder, s string) {
+	for seg := range printf.Segments(s) {
+		if seg.IsNumber() {
+			b.WriteByte('#')
+		} else {
+			b.WriteString(icumsg.EscapeLiteral(seg.Text))
+		}
+	}
+}
+
+// germanicForms are the plural forms of msgid and msgid_plural.
+// eval is set directly since the expression is known to be valid.
+var germanicForms = PluralForms{N: 2, Expr: "(n != 1)", eval: func(n uint64) uint64 {
+	return boolean(n != 1)
+}}
+
+// FromICU converts the ICU message src tokenized into buffer for the locale
+// to msgstr: a single string for messages without plural argument and
+// a string per plural form for messages consisting of a plural argument
+// optionally surrounded by text, which is repeated in every plural form.
+// Literals are unescaped and, in plural forms, "#" becomes "%d".
+// Options missing in src are taken from option "other".
+//
+// The message is converted even if the conversion isn't lossless,
+// in which case the returned error wraps the reasons: ErrUnrepresentable
+// for arguments other than the plural argument, offsets and "=n" options
+// (which are written as is or dropped) as well as ErrMergedCategories and
+// the mapping errors (see Mapping.Err).
+func (m *Mapping) FromICU(src string, buffer []icumsg.Token) ([]string, error) {
+	var errs []error
+	unrepresentable := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format,
+			append([]any{ErrUnrepresentable}, args...)...))
+	}
+	plural := -1
+	for i := 0; i < len(buffer); i++ {
+		switch t := buffer[i]; t.Type {
+		case icumsg.TokenTypePlural:
+			if plural != -1 {
+				unrepresentable("multiple plural arguments")
+				i = t.IndexEnd
+				continue
+			}
+			plural = i
+			i = t.IndexEnd
+		case icumsg.TokenTypeSimpleArg, icumsg.TokenTypeSelect,
+			icumsg.TokenTypeSelectOrdinal:
+			unrepresentable("%s %q", t.Type, buffer[i+1].String(src, buffer))
+			if t.Type != icumsg.TokenTypeSimpleArg {
+				i = t.IndexEnd
+			}
+		}
+	}
+	if plural == -1 {
+		return []string{text(src, buffer, 0, len(buffer), false)}, errors.Join(errs...)
+	}
+
+	prefix := text(src, buffer, 0, plural, false)
+	suffix := text(src, buffer, buffer[plural].IndexEnd+1, len(buffer), false)
+	if buffer[plural+2].Type == icumsg.TokenTypePluralOffset {
+		unrepresentable("offset")
+	}
+	options := map[cldr.Category]string{}
+	for j := range icumsg.Options(buffer, plural) {
+		start := j + 1
+		if t := buffer[j].Type; t == icumsg.TokenTypeOptionNumber {
+			unrepresentable("option %s", icumsg.OptionName(src, buffer, j))
+			continue
+		}
+		c, _ := cldr.CategoryOf(buffer[j].Type)
+		for k := start; k < buffer[j].IndexEnd; k++ {
+			switch t := buffer[k]; t.Type {
+			case icumsg.TokenTypeSimpleArg, icumsg.TokenTypePlural,
+				icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+				unrepresentable("%s %q in option %s",
+					t.Type, buffer[k+1].String(src, buffer), c)
+				if t.Type != icumsg.TokenTypeSimpleArg {
+					k = t.IndexEnd
+				}
+			}
+		}
+		options[c] = prefix + text(src, buffer, start, buffer[j].IndexEnd, true) + suffix
+	}
+	forms := make([]string, m.Forms.N)
+	for i := range forms {
+		categories := m.Categories(i)
+		if categories == 0 {
+			forms[i] = options[cldr.CategoryOther]
+			continue
+		}
+		// Prefer categories with integer samples since gettext has no fractions.
+		var chosen cldr.Category
+		for c := range categories.All() {
+			if chosen == 0 || !m.primary.Has(chosen) && m.primary.Has(c) {
+				chosen = c
+			}
+		}
+		forms[i] = option(options, chosen)
+		for c := range categories.All() {
+			if option(options, c) != forms[i] {
+				errs = append(errs, fmt.Errorf("%w: %s and %s in plural form %d",
+					ErrMergedCategories, chosen, c, i))
+			}
+		}
+	}
+	if err := m.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return forms, errors.Join(errs...)
+}
+
+// option returns the contents of the option of c or "other" if there's none.
+func option(options map[cldr.Category]string, c cldr.Category) string {
+	if s, ok := options[c]; ok {
+		return s
+	}
+	return options[cldr.CategoryOther]
+}
+
+// text returns the unescaped literals of buffer[start:end] and the source of
+// any arguments. If hash is true then "#" becomes "%d".
+func text(src string, buffer []icumsg.Token, start, end int, hash bool) string {
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		switch t := buffer[i]; t.Type {
+		case icumsg.TokenTypeLiteral:
+			unescape(&b, src[t.IndexStart:t.IndexEnd], hash)
+		case icumsg.TokenTypeSimpleArg:
+			b.WriteString(src[t.IndexStart:t.IndexEnd])
+		case icumsg.TokenTypePlural, icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+			b.WriteString(t.String(src, buffer))
+			i = t.IndexEnd
+		}
+	}
+	return b.String()
+}
+
+// unescape writes the unescaped ICU literal l to b.
+func unescape(b *strings.Builder, l string, hash bool) {
+	for text, isHash := range icumsg.LiteralSegments(l, hash) {
+		b.WriteString(text)
+		if isHash {
+			b.WriteString("%d")
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+	"github.com/romshark/icumsg/gettext"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+const (
+	formsEnglish = "nplurals=2; plural=(n != 1);"
+	formsFrench  = "nplurals=2; plural=(n > 1);"
+	formsPolish  = "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && " +
+		"(n%100<10 || n%100>=20) ? 1 : 2);"
+	formsArabic = "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : " +
+		"n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);"
+)
+
+func mapping(t *testing.T, locale language.Tag, forms string) *gettext.Mapping {
+	t.Helper()
+	p, err := gettext.ParsePluralForms(forms)
+	test.RequireNoErr(t, err)
+	return gettext.NewMapping(locale, p)
+}
+
+func TestMapping(t *testing.T) {
+	f := func(t *testing.T, locale language.Tag, forms string, expect ...string) {
+		t.Helper()
+		m := mapping(t, locale, forms)
+		test.RequireNoErr(t, m.Err())
+		var actual []string
+		for i := range m.Forms.N {
+			actual = append(actual, m.Categories(i).String())
+		}
+		test.RequireDeepEqual(t, expect, actual)
+	}
+
+	f(t, language.English, formsEnglish, "one", "other")
+	f(t, language.French, formsFrench, "one", "many,other")
+	// Polish "other" applies to fractions only.
+	f(t, language.Polish, formsPolish, "one", "few", "many,other")
+	f(t, language.Arabic, formsArabic, "zero", "one", "two", "few", "many", "other")
+	f(t, language.Japanese, "nplurals=1; plural=0;", "other")
+
+	m := mapping(t, language.Polish, formsPolish)
+	test.RequireEqual(t, 1, m.Index(cldr.CategoryFew))
+	test.RequireEqual(t, 2, m.Index(cldr.CategoryOther))
+}
+
+func TestMappingErr(t *testing.T) {
+	// 0 is "one" in French but form 1 here.
+	m := mapping(t, language.French, formsEnglish)
+	test.RequireErrIs(t, gettext.ErrAmbiguousCategory, m.Err())
+	test.RequireEqual(t, "plural category spans multiple plural forms: one", m.Err().Error())
+
+	m = mapping(t, language.English, "nplurals=3; plural=(n != 1);")
+	test.RequireErrIs(t, gettext.ErrUnusedForm, m.Err())
+	test.RequireEqual(t, "plural form not used by any plural category: 2", m.Err().Error())
+}
+
+func TestToICU(t *testing.T) {
+	m := mapping(t, language.Polish, formsPolish)
+	f := func(t *testing.T, msg gettext.Message, expect string) {
+		t.Helper()
+		actual, err := m.ToICU(msg, "n")
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, actual)
+		var tokenizer icumsg.Tokenizer
+		_, err = tokenizer.Tokenize(language.Polish, nil, actual)
+		test.RequireNoErr(t, err)
+	}
+
+	f(t, gettext.Message{ID: "It's {x}", Str: []string{"To '{x}' #"}},
+		"To '''{'x'}''' '#'")
+	f(t, gettext.Message{ID: "Untranslated"}, "Untranslated")
+	f(t, gettext.Message{
+		ID: "%d file", IDPlural: "%d files",
+		Str: []string{"%d plik", "%d pliki", "%d plików"},
+	}, "{n, plural, one {# plik} few {# pliki} many {# plików} other {# plików}}")
+	// Number specifiers become "#", other specifiers and "%%" are kept.
+	f(t, gettext.Message{
+		ID: "%i file", IDPlural: "%i files",
+		Str: []string{"%i plik", "%ld pliki", "%1$d plików %s"},
+	}, "{n, plural, one {# plik} few {# pliki} many {# plików %s} other {# plików %s}}")
+	f(t, gettext.Message{
+		ID: "%d%% done", IDPlural: "%d%% done",
+		Str: []string{"100%%d", "%%%d %f", "%5d %.2f % #"},
+	}, "{n, plural, one {100%%d} few {%%# %f} many {%5d %.2f % '#'} other {%5d %.2f % '#'}}")
+	// Untranslated messages are converted from msgid and msgid_plural.
+	f(t, gettext.Message{
+		ID: "%d file", IDPlural: "%d files", Str: []string{"", "", ""},
+	}, "{n, plural, one {# file} few {# files} many {# files} other {# files}}")
+
+	_, err := m.ToICU(gettext.Message{
+		ID: "%d file", IDPlural: "%d files", Str: []string{"%d plik", "%d pliki"},
+	}, "n")
+	test.RequireErrIs(t, gettext.ErrPluralFormCount, err)
+
+	// Lossy conversions still convert.
+	m = mapping(t, language.French, formsEnglish)
+	actual, err := m.ToICU(gettext.Message{
+		ID: "%d file", IDPlural: "%d files", Str: []string{"%d fichier", "%d fichiers"},
+	}, "count")
+	test.RequireErrIs(t, gettext.ErrAmbiguousCategory, err)
+	test.RequireEqual(t,
+		"{count, plural, one {# fichier} many {# fichiers} other {# fichiers}}", actual)
+}
+
+func TestFromICU(t *testing.T) {
+	f := func(
+		t *testing.T, locale language.Tag, forms, src string, expect []string,
+		expectErr error,
+	) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(locale, nil, src)
+		test.RequireNoErr(t, err)
+		actual, err := mapping(t, locale, forms).FromICU(src, buffer)
+		if expectErr == nil {
+			test.RequireNoErr(t, err)
+		} else {
+			test.RequireErrIs(t, expectErr, err)
+		}
+		test.RequireDeepEqual(t, expect, actual)
+	}
+
+	f(t, language.English, formsEnglish, "It''s '{'x'}' # 100%",
+		[]string{"It's {x} # 100%"}, nil)
+	f(t, language.Polish, formsPolish,
+		"Masz {n, plural, one {# plik} few {# pliki} many {# plików} other {# pliku}}.",
+		[]string{"Masz %d plik.", "Masz %d pliki.", "Masz %d plików."},
+		// "other" of fractions is lost.
+		gettext.ErrMergedCategories)
+	f(t, language.Polish, formsPolish,
+		"{n, plural, one {# plik} other {# plików} few {'#'# pliki}}",
+		[]string{"%d plik", "#%d pliki", "%d plików"}, nil)
+	f(t, language.English, formsEnglish,
+		"{a} {n, plural, offset:1 =0 {none} one {#} other {# {x}}}",
+		[]string{"{a} %d", "{a} %d {x}"}, gettext.ErrUnrepresentable)
+	f(t, language.English, formsEnglish,
+		"{n, plural, other {#}} {m, plural, other {#}}",
+		[]string{"%d {m, plural, other {#}}", "%d {m, plural, other {#}}"},
+		gettext.ErrUnrepresentable)
+	f(t, language.English, formsEnglish, "{g, select, other {x}}",
+		[]string{"{g, select, other {x}}"}, gettext.ErrUnrepresentable)
+}
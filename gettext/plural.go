@@ -0,0 +1,267 @@
+package gettext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PluralForms is a parsed Plural-Forms header field such as
+// "nplurals=2; plural=(n != 1);".
+type PluralForms struct {
+	// N is the number of plural forms (nplurals).
+	N int
+
+	// Expr is the C expression computing the plural form index of n.
+	Expr string
+
+	eval func(n uint64) uint64
+}
+
+// ParsePluralForms parses a Plural-Forms header field.
+func ParsePluralForms(s string) (PluralForms, error) {
+	var p PluralForms
+	var hasN bool
+	for field := range strings.SplitSeq(s, ";") {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			if strings.TrimSpace(field) == "" {
+				continue
+			}
+			return p, fmt.Errorf("%w: %q", ErrInvalidPluralForms, field)
+		}
+		switch k, v = strings.TrimSpace(k), strings.TrimSpace(v); k {
+		case "nplurals":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return p, fmt.Errorf("%w: nplurals=%s", ErrInvalidPluralForms, v)
+			}
+			p.N, hasN = n, true
+		case "plural":
+			e := exprParser{s: v}
+			eval, err := e.parse()
+			if err != nil {
+				return p, fmt.Errorf("%w: plural=%s: %v", ErrInvalidPluralForms, v, err)
+			}
+			p.Expr, p.eval = v, eval
+		default:
+			return p, fmt.Errorf("%w: unknown field %q", ErrInvalidPluralForms, k)
+		}
+	}
+	if !hasN || p.eval == nil {
+		return p, fmt.Errorf("%w: expected nplurals and plural", ErrInvalidPluralForms)
+	}
+	return p, nil
+}
+
+// String returns p in Plural-Forms header field syntax.
+func (p PluralForms) String() string {
+	return "nplurals=" + strconv.Itoa(p.N) + "; plural=" + p.Expr + ";"
+}
+
+// Index returns the index of the plural form of n.
+// Results of the expression out of range are limited to N-1.
+func (p PluralForms) Index(n uint64) int {
+	i := p.eval(n)
+	if i >= uint64(p.N) {
+		return p.N - 1
+	}
+	return int(i)
+}
+
+// exprParser parses the plural expression, a subset of C:
+// the variable n, unsigned integer constants, parentheses and
+// the operators ?:, ||, &&, ==, !=, <, <=, >, >=, +, -, *, /, % and !.
+type exprParser struct {
+	s   string
+	pos int
+}
+
+type evalFunc = func(n uint64) uint64
+
+func (p *exprParser) parse() (evalFunc, error) {
+	f, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.s) {
+		return nil, fmt.Errorf("unexpected %q at %d", p.s[p.pos:], p.pos)
+	}
+	return f, nil
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' ||
+		p.s[p.pos] == '\n' || p.s[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+// consume consumes op if it's next.
+func (p *exprParser) consume(op string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.s[p.pos:], op) {
+		return false
+	}
+	// Don't mistake "<=" for "<" or "!=" for "!" and so on.
+	if len(op) == 1 && p.pos+1 < len(p.s) && p.s[p.pos+1] == '=' &&
+		strings.Contains("<>!=", op) {
+		return false
+	}
+	p.pos += len(op)
+	return true
+}
+
+func boolean(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *exprParser) ternary() (evalFunc, error) {
+	cond, err := p.or()
+	if err != nil || !p.consume("?") {
+		return cond, err
+	}
+	then, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(":") {
+		return nil, fmt.Errorf("expected ':' at %d", p.pos)
+	}
+	els, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n uint64) uint64 {
+		if cond(n) != 0 {
+			return then(n)
+		}
+		return els(n)
+	}, nil
+}
+
+// binary parses left-associative binary operators of the same precedence.
+func (p *exprParser) binary(
+	operand func() (evalFunc, error), ops map[string]func(a, b uint64) uint64,
+) (evalFunc, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op func(a, b uint64) uint64
+		for _, s := range [...]string{"||", "&&", "==", "!=", "<=", ">=", "<", ">",
+			"+", "-", "*", "/", "%"} {
+			if f, ok := ops[s]; ok && p.consume(s) {
+				op = f
+				break
+			}
+		}
+		if op == nil {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n uint64) uint64 { return op(l(n), right(n)) }
+	}
+}
+
+func (p *exprParser) or() (evalFunc, error) {
+	return p.binary(p.and, map[string]func(a, b uint64) uint64{
+		"||": func(a, b uint64) uint64 { return boolean(a != 0 || b != 0) },
+	})
+}
+
+func (p *exprParser) and() (evalFunc, error) {
+	return p.binary(p.equality, map[string]func(a, b uint64) uint64{
+		"&&": func(a, b uint64) uint64 { return boolean(a != 0 && b != 0) },
+	})
+}
+
+func (p *exprParser) equality() (evalFunc, error) {
+	return p.binary(p.relational, map[string]func(a, b uint64) uint64{
+		"==": func(a, b uint64) uint64 { return boolean(a == b) },
+		"!=": func(a, b uint64) uint64 { return boolean(a != b) },
+	})
+}
+
+func (p *exprParser) relational() (evalFunc, error) {
+	return p.binary(p.additive, map[string]func(a, b uint64) uint64{
+		"<":  func(a, b uint64) uint64 { return boolean(a < b) },
+		"<=": func(a, b uint64) uint64 { return boolean(a <= b) },
+		">":  func(a, b uint64) uint64 { return boolean(a > b) },
+		">=": func(a, b uint64) uint64 { return boolean(a >= b) },
+	})
+}
+
+func (p *exprParser) additive() (evalFunc, error) {
+	return p.binary(p.multiplicative, map[string]func(a, b uint64) uint64{
+		"+": func(a, b uint64) uint64 { return a + b },
+		"-": func(a, b uint64) uint64 { return a - b },
+	})
+}
+
+func (p *exprParser) multiplicative() (evalFunc, error) {
+	return p.binary(p.unary, map[string]func(a, b uint64) uint64{
+		"*": func(a, b uint64) uint64 { return a * b },
+		"/": func(a, b uint64) uint64 {
+			if b == 0 {
+				return 0
+			}
+			return a / b
+		},
+		"%": func(a, b uint64) uint64 {
+			if b == 0 {
+				return 0
+			}
+			return a % b
+		},
+	})
+}
+
+func (p *exprParser) unary() (evalFunc, error) {
+	if p.consume("!") {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n uint64) uint64 { return boolean(f(n) == 0) }, nil
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (evalFunc, error) {
+	p.skipSpace()
+	switch {
+	case p.pos >= len(p.s):
+		return nil, fmt.Errorf("unexpected end of expression")
+	case p.s[p.pos] == 'n':
+		p.pos++
+		return func(n uint64) uint64 { return n }, nil
+	case p.s[p.pos] == '(':
+		p.pos++
+		f, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, fmt.Errorf("expected ')' at %d", p.pos)
+		}
+		return f, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	v, err := strconv.ParseUint(p.s[start:p.pos], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected %q at %d", p.s[start:], start)
+	}
+	return func(uint64) uint64 { return v }, nil
+}
@@ -0,0 +1,60 @@
+package gettext_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg/gettext"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+func TestParsePluralForms(t *testing.T) {
+	f := func(t *testing.T, input string, expectN int, expect map[uint64]int) {
+		t.Helper()
+		p, err := gettext.ParsePluralForms(input)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expectN, p.N)
+		for n, index := range expect {
+			if actual := p.Index(n); actual != index {
+				t.Errorf("Index(%d): expected %d, received %d", n, index, actual)
+			}
+		}
+	}
+
+	f(t, "nplurals=1; plural=0;", 1, map[uint64]int{0: 0, 1: 0, 100: 0})
+	f(t, "nplurals=2; plural=(n != 1);", 2, map[uint64]int{0: 1, 1: 0, 2: 1})
+	f(t, "nplurals=2; plural=n>1", 2, map[uint64]int{0: 0, 1: 0, 2: 1})
+	f(t, "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && "+
+		"(n%100<10 || n%100>=20) ? 1 : 2);",
+		3, map[uint64]int{0: 2, 1: 0, 2: 1, 4: 1, 5: 2, 12: 2, 22: 1, 25: 2, 112: 2})
+	f(t, "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : "+
+		"n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+		6, map[uint64]int{0: 0, 1: 1, 2: 2, 3: 3, 11: 4, 100: 5, 102: 5, 103: 3})
+	f(t, "nplurals=2; plural=!(n == 1) * 1 + 0 - 0 / 0;", 2, map[uint64]int{1: 0, 2: 1})
+	// Out of range results are limited to nplurals-1.
+	f(t, "nplurals=2; plural=n;", 2, map[uint64]int{0: 0, 1: 1, 5: 1})
+
+	p, err := gettext.ParsePluralForms(" nplurals = 2 ; plural = ( n != 1 ) ; ")
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, "nplurals=2; plural=( n != 1 );", p.String())
+}
+
+func TestParsePluralFormsErr(t *testing.T) {
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		_, err := gettext.ParsePluralForms(input)
+		test.RequireErrIs(t, gettext.ErrInvalidPluralForms, err)
+	}
+
+	f(t, "")
+	f(t, "nplurals=2;")
+	f(t, "plural=n != 1;")
+	f(t, "nplurals=0; plural=0;")
+	f(t, "nplurals=INTEGER; plural=EXPRESSION;")
+	f(t, "nplurals=2; plural=(n != 1;")
+	f(t, "nplurals=2; plural=n ? 1;")
+	f(t, "nplurals=2; plural=n 1;")
+	f(t, "nplurals=2; plural=n = 1;")
+	f(t, "nplurals=2; plural=;")
+	f(t, "nplurals=2; plural=0; x=1;")
+	f(t, "nplurals=2; plural")
+}
@@ -0,0 +1,329 @@
+// Package gettext reads and writes gettext PO and POT files and converts
+// gettext messages to ICU messages and back, mapping the plural form
+// indexes of the Plural-Forms header to CLDR plural categories.
+package gettext
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// File is a PO or POT file.
+type File struct {
+	// Messages are the messages in the order of appearance.
+	// The header, if any, is the first message with an empty ID.
+	Messages []Message
+}
+
+// Message is a PO entry.
+type Message struct {
+	// Comments are the comment lines including the leading "#",
+	// such as "#. Extracted comment", "#: main.go:12" or "#, c-format".
+	// Obsolete entries ("#~") are kept as comments.
+	Comments []string
+
+	Context  string // msgctxt
+	ID       string // msgid
+	IDPlural string // msgid_plural
+
+	// Str is msgstr or msgstr[0] to msgstr[n] for plural messages.
+	Str []string
+}
+
+// IsPlural returns true for messages with msgid_plural.
+func (m Message) IsPlural() bool { return m.IDPlural != "" }
+
+// Flags returns the flags of the "#," comments such as "fuzzy" and "c-format".
+func (m Message) Flags() []string {
+	var flags []string
+	for _, c := range m.Comments {
+		if s, ok := strings.CutPrefix(c, "#,"); ok {
+			for f := range strings.SplitSeq(s, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					flags = append(flags, f)
+				}
+			}
+		}
+	}
+	return flags
+}
+
+var (
+	ErrSyntax             = errors.New("syntax error")
+	ErrNoPluralForms      = errors.New("missing Plural-Forms header")
+	ErrInvalidPluralForms = errors.New("invalid Plural-Forms")
+)
+
+// Header returns the header entry or nil if there's none.
+func (f *File) Header() *Message {
+	if len(f.Messages) > 0 && f.Messages[0].ID == "" && f.Messages[0].Context == "" {
+		return &f.Messages[0]
+	}
+	return nil
+}
+
+// HeaderField returns the value of the header field such as "Language"
+// or an empty string if there's none.
+func (f *File) HeaderField(name string) string {
+	h := f.Header()
+	if h == nil || len(h.Str) == 0 {
+		return ""
+	}
+	for line := range strings.Lines(h.Str[0]) {
+		k, v, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// Language returns the locale of the "Language" header field
+// or language.Und if there's none.
+func (f *File) Language() (language.Tag, error) {
+	l := f.HeaderField("Language")
+	if l == "" {
+		return language.Und, nil
+	}
+	return language.Parse(l)
+}
+
+// PluralForms returns the parsed "Plural-Forms" header field.
+func (f *File) PluralForms() (PluralForms, error) {
+	s := f.HeaderField("Plural-Forms")
+	if s == "" {
+		return PluralForms{}, ErrNoPluralForms
+	}
+	return ParsePluralForms(s)
+}
+
+// Read reads a PO or POT file.
+func Read(r io.Reader) (*File, error) {
+	p := poParser{s: bufio.NewScanner(r)}
+	f := new(File)
+	for {
+		m, err := p.entry()
+		if err == io.EOF {
+			return f, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrSyntax, p.line, err)
+		}
+		f.Messages = append(f.Messages, m)
+	}
+}
+
+type poParser struct {
+	s    *bufio.Scanner
+	line int
+
+	// next is the line read ahead, valid if hasNext.
+	next    string
+	hasNext bool
+}
+
+func (p *poParser) readLine() (string, bool) {
+	if p.hasNext {
+		p.hasNext = false
+		return p.next, true
+	}
+	if !p.s.Scan() {
+		return "", false
+	}
+	p.line++
+	return strings.TrimSpace(p.s.Text()), true
+}
+
+func (p *poParser) unreadLine(l string) { p.next, p.hasNext = l, true }
+
+// entry reads the next entry and returns io.EOF if there's none.
+func (p *poParser) entry() (m Message, err error) {
+	var keyword string // The keyword the current string belongs to.
+	var value *string
+	started := false
+	for {
+		l, ok := p.readLine()
+		if !ok {
+			if err := p.s.Err(); err != nil {
+				return m, err
+			}
+			if !started {
+				return m, io.EOF
+			}
+			break
+		}
+		switch {
+		case l == "":
+			if started {
+				return m, p.check(m)
+			}
+		case strings.HasPrefix(l, "#"):
+			if keyword != "" {
+				// Comments start the next entry.
+				p.unreadLine(l)
+				return m, p.check(m)
+			}
+			m.Comments = append(m.Comments, l)
+			started = true
+		case strings.HasPrefix(l, `"`):
+			if value == nil {
+				return m, errors.New("string without keyword")
+			}
+			s, err := unquote(l)
+			if err != nil {
+				return m, err
+			}
+			*value += s
+		default:
+			kw, rest, _ := strings.Cut(l, " ")
+			if kw == "msgctxt" && keyword != "" || kw == "msgid" && keyword != "" &&
+				keyword != "msgctxt" {
+				// The next entry without separating blank line.
+				p.unreadLine(l)
+				return m, p.check(m)
+			}
+			s, err := unquote(strings.TrimSpace(rest))
+			if err != nil {
+				return m, err
+			}
+			switch {
+			case kw == "msgctxt":
+				m.Context, value = s, &m.Context
+			case kw == "msgid":
+				m.ID, value = s, &m.ID
+			case kw == "msgid_plural":
+				m.IDPlural, value = s, &m.IDPlural
+			case kw == "msgstr" || strings.HasPrefix(kw, "msgstr["):
+				i := 0
+				if kw != "msgstr" {
+					n, ok := strings.CutSuffix(kw[len("msgstr["):], "]")
+					if i, err = strconv.Atoi(n); !ok || err != nil || i != len(m.Str) {
+						return m, fmt.Errorf("unexpected %s", kw)
+					}
+				}
+				m.Str = append(m.Str, s)
+				value = &m.Str[i]
+			default:
+				return m, fmt.Errorf("unknown keyword %q", kw)
+			}
+			keyword, started = kw, true
+		}
+	}
+	return m, p.check(m)
+}
+
+func (p *poParser) check(m Message) error {
+	if len(m.Str) == 0 && (m.ID != "" || m.IDPlural != "") {
+		return fmt.Errorf("msgid %q: missing msgstr", m.ID)
+	}
+	return nil
+}
+
+// unquote unquotes a C-style string literal as used in PO files.
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	s = s[1 : len(s)-1]
+	if !strings.ContainsAny(s, `\"`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' {
+			return "", errors.New("unescaped quote")
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i >= len(s) {
+			return "", errors.New("unterminated escape sequence")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			return "", fmt.Errorf("unknown escape sequence \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// Write writes f as a PO file. Strings containing line breaks are written
+// as one line per line break as conventional in PO files.
+func Write(w io.Writer, f *File) error {
+	var b strings.Builder
+	for i, m := range f.Messages {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		for _, c := range m.Comments {
+			b.WriteString(c)
+			b.WriteByte('\n')
+		}
+		if m.ID == "" && m.Context == "" && len(m.Str) == 0 {
+			continue // Comments only, such as obsolete entries.
+		}
+		if m.Context != "" {
+			writeString(&b, "msgctxt", m.Context)
+		}
+		writeString(&b, "msgid", m.ID)
+		if m.IsPlural() {
+			writeString(&b, "msgid_plural", m.IDPlural)
+			for i, s := range m.Str {
+				writeString(&b, "msgstr["+strconv.Itoa(i)+"]", s)
+			}
+			continue
+		}
+		var s string
+		if len(m.Str) > 0 {
+			s = m.Str[0]
+		}
+		writeString(&b, "msgstr", s)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeString(b *strings.Builder, keyword, s string) {
+	b.WriteString(keyword)
+	b.WriteByte(' ')
+	if i := strings.IndexByte(s, '\n'); i == -1 || i == len(s)-1 {
+		b.WriteString(quote(s))
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteString("\"\"\n")
+	for line := range strings.Lines(s) {
+		b.WriteString(quote(line))
+		b.WriteByte('\n')
+	}
+}
+
+var quoteReplacer = strings.NewReplacer(
+	`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`,
+	"\a", `\a`, "\b", `\b`, "\f", `\f`, "\v", `\v`,
+)
+
+func quote(s string) string { return `"` + quoteReplacer.Replace(s) + `"` }
@@ -0,0 +1,126 @@
+package gettext_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg/gettext"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+const poFile = `# Polish translation.
+msgid ""
+msgstr ""
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && "
+"(n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Greeting on the start page.
+#: main.go:12
+#, c-format, fuzzy
+msgctxt "start"
+msgid "Hello \"%s\"\t\\"
+msgstr "Cześć \"%s\"\t\\"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d plik"
+msgstr[1] "%d pliki"
+msgstr[2] "%d plików"
+
+#~ msgid "Obsolete"
+#~ msgstr "Przestarzały"
+`
+
+func TestReadWrite(t *testing.T) {
+	f, err := gettext.Read(strings.NewReader(poFile))
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, 4, len(f.Messages))
+	test.RequireEqual(t, "pl", f.HeaderField("language"))
+	l, err := f.Language()
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.Polish, l)
+	forms, err := f.PluralForms()
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, 3, forms.N)
+
+	test.RequireDeepEqual(t, gettext.Message{
+		Comments: []string{"#. Greeting on the start page.", "#: main.go:12", "#, c-format, fuzzy"},
+		Context:  "start",
+		ID:       "Hello \"%s\"\t\\",
+		Str:      []string{"Cześć \"%s\"\t\\"},
+	}, f.Messages[1])
+	test.RequireDeepEqual(t, []string{"c-format", "fuzzy"}, f.Messages[1].Flags())
+	test.RequireEqual(t, true, f.Messages[2].IsPlural())
+	test.RequireDeepEqual(t, []string{"%d plik", "%d pliki", "%d plików"}, f.Messages[2].Str)
+	test.RequireDeepEqual(t, gettext.Message{
+		Comments: []string{`#~ msgid "Obsolete"`, `#~ msgstr "Przestarzały"`},
+	}, f.Messages[3])
+
+	var b bytes.Buffer
+	test.RequireNoErr(t, gettext.Write(&b, f))
+	test.RequireEqual(t, `# Polish translation.
+msgid ""
+msgstr ""
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Greeting on the start page.
+#: main.go:12
+#, c-format, fuzzy
+msgctxt "start"
+msgid "Hello \"%s\"\t\\"
+msgstr "Cześć \"%s\"\t\\"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d plik"
+msgstr[1] "%d pliki"
+msgstr[2] "%d plików"
+
+#~ msgid "Obsolete"
+#~ msgstr "Przestarzały"
+`, b.String())
+}
+
+func TestReadNoSeparator(t *testing.T) {
+	f, err := gettext.Read(strings.NewReader(`msgid "a"
+msgstr "A"
+msgctxt "c"
+msgid "b"
+msgstr "B"
+#: x.go:1
+msgid "c"
+msgstr ""
+"C\n"
+"D"`))
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, 3, len(f.Messages))
+	test.RequireEqual(t, "c", f.Messages[1].Context)
+	test.RequireEqual(t, "C\nD", f.Messages[2].Str[0])
+	test.RequireEqual(t, (*gettext.Message)(nil), f.Header())
+	_, err = f.PluralForms()
+	test.RequireErrIs(t, gettext.ErrNoPluralForms, err)
+}
+
+func TestReadErr(t *testing.T) {
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		_, err := gettext.Read(strings.NewReader(input))
+		test.RequireErrIs(t, gettext.ErrSyntax, err)
+	}
+
+	f(t, `"orphan"`)
+	f(t, `msgid "a`)
+	f(t, `msgid a`)
+	f(t, `msgid "a"`)
+	f(t, `msgid "a" msgstr "b"`)
+	f(t, "msgid \"a\"\nmsgstr \"\\x\"")
+	f(t, "msgid \"a\"\nmsgstr \"\\\"")
+	f(t, "msgid \"a\"\nmsgstr \"\"\"")
+	f(t, "msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[1] \"x\"")
+	f(t, "msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[0 \"x\"")
+	f(t, "msgid \"a\"\nmsgval \"b\"")
+}
@@ -0,0 +1,103 @@
+// Package httplocale negotiates the locale of HTTP requests from the
+// Accept-Language header among the locales of a message catalog.
+package httplocale
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/format"
+	"golang.org/x/text/language"
+)
+
+// Locale is the negotiated locale of a request.
+type Locale struct {
+	// Tag is the locale of the catalog that was chosen.
+	Tag language.Tag
+
+	// Cardinal and Ordinal are the plural rules of Tag.
+	Cardinal, Ordinal icumsg.PluralRules
+}
+
+// Formatter returns a formatter for the locale that selects only
+// plural categories of Cardinal and Ordinal, see format.Formatter.Plurals.
+func (l Locale) Formatter() format.Formatter {
+	return format.Formatter{
+		Locale:  l.Tag,
+		Plurals: localeRules{cardinal: l.Cardinal, ordinal: l.Ordinal},
+	}
+}
+
+// localeRules provides the plural rules of a Locale.
+type localeRules struct{ cardinal, ordinal icumsg.PluralRules }
+
+func (r localeRules) PluralRules(language.Tag) (cardinal, ordinal icumsg.PluralRules) {
+	return r.cardinal, r.ordinal
+}
+
+// Negotiator chooses locales among the locales of a catalog
+// that have plural rules. Negotiator is safe for concurrent use.
+type Negotiator struct {
+	plurals icumsg.PluralRuleProvider
+	locales []language.Tag
+	matcher language.Matcher
+}
+
+// New creates a negotiator for the locales c has at the time of the call.
+// Locales without plural rules provided by plurals are skipped except
+// for the source locale, which is chosen if no other locale matches.
+// plurals should be the provider the messages were tokenized with,
+// icumsg.CLDRPluralRules is used if nil.
+func New(c *catalog.Catalog, plurals icumsg.PluralRuleProvider) *Negotiator {
+	if plurals == nil {
+		plurals = icumsg.CLDRPluralRules{}
+	}
+	n := &Negotiator{plurals: plurals, locales: []language.Tag{c.Source()}}
+	for _, l := range c.Locales() {
+		if cardinal, _ := plurals.PluralRules(l); cardinal.Other &&
+			!slices.Contains(n.locales, l) {
+			n.locales = append(n.locales, l)
+		}
+	}
+	n.matcher = language.NewMatcher(n.locales)
+	return n
+}
+
+// Locales returns the locales that can be chosen, the source locale first.
+func (n *Negotiator) Locales() []language.Tag { return slices.Clone(n.locales) }
+
+// Negotiate chooses the locale for the preferred locales
+// of an Accept-Language header.
+func (n *Negotiator) Negotiate(acceptLanguage string) Locale {
+	preferred, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, i, _ := n.matcher.Match(preferred...)
+	l := Locale{Tag: n.locales[i]}
+	l.Cardinal, l.Ordinal = n.plurals.PluralRules(l.Tag)
+	return l
+}
+
+// Middleware stores the locale negotiated for the Accept-Language header
+// in the request context, see FromContext.
+func (n *Negotiator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), l)))
+	})
+}
+
+type ctxKey struct{}
+
+// WithLocale returns a copy of ctx with l.
+func WithLocale(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the locale of ctx and false if there's none.
+func FromContext(ctx context.Context) (Locale, bool) {
+	l, ok := ctx.Value(ctxKey{}).(Locale)
+	return l, ok
+}
@@ -0,0 +1,94 @@
+package httplocale_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/httplocale"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+var klingon = language.MustParse("tlh")
+
+func newNegotiator(t *testing.T) *httplocale.Negotiator {
+	t.Helper()
+	c := catalog.New(language.English, nil)
+	for _, l := range []language.Tag{
+		language.English, language.German, language.BrazilianPortuguese, klingon,
+	} {
+		_ = c.Add(l, "hello", "Hello")
+	}
+	return httplocale.New(c, nil)
+}
+
+func TestNegotiate(t *testing.T) {
+	n := newNegotiator(t)
+	// Klingon has no plural rules.
+	test.RequireDeepEqual(t, []language.Tag{
+		language.English, language.German, language.BrazilianPortuguese,
+	}, n.Locales())
+
+	f := func(t *testing.T, acceptLanguage string, expect language.Tag) {
+		t.Helper()
+		l := n.Negotiate(acceptLanguage)
+		test.RequireEqual(t, expect, l.Tag)
+		cardinal, ordinal := icumsg.CLDRPluralRules{}.PluralRules(expect)
+		test.RequireEqual(t, cardinal, l.Cardinal)
+		test.RequireEqual(t, ordinal, l.Ordinal)
+		test.RequireEqual(t, expect, l.Formatter().Locale)
+	}
+
+	f(t, "", language.English)
+	f(t, "de-CH, en;q=0.5", language.German)
+	f(t, "pt", language.BrazilianPortuguese)
+	f(t, "fr, de;q=0.8", language.German)
+	f(t, "tlh", language.English)
+	f(t, "invalid;;;", language.English)
+}
+
+func TestMiddleware(t *testing.T) {
+	n := newNegotiator(t)
+	var locale httplocale.Locale
+	var ok bool
+	h := n.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale, ok = httplocale.FromContext(r.Context())
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "de-AT")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	test.RequireEqual(t, true, ok)
+	test.RequireEqual(t, language.German, locale.Tag)
+	test.RequireEqual(t, "Accept-Language", w.Header().Get("Vary"))
+
+	_, ok = httplocale.FromContext(r.Context())
+	test.RequireEqual(t, false, ok)
+}
+
+func TestLocaleFormatter(t *testing.T) {
+	c := catalog.New(language.English, nil)
+	_ = c.Add(language.German, "files", "{n, plural, one {# Datei} other {# Dateien}}")
+	e, ok := c.Lookup(language.German, "files")
+	test.RequireEqual(t, true, ok)
+	args := map[string]any{"n": 1}
+
+	f := func(t *testing.T, plurals icumsg.PluralRuleProvider, expect string) {
+		t.Helper()
+		l := httplocale.New(c, plurals).Negotiate("de")
+		test.RequireEqual(t, language.German, l.Tag)
+		s, err := l.Formatter().Format(e.Message, e.Tokens, args)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, s)
+	}
+
+	f(t, nil, "1 Datei")
+
+	// The formatter uses the plural rules resolved by the negotiator.
+	plurals := icumsg.NewPluralRuleRegistry(nil)
+	plurals.Register(language.German, icumsg.PluralRules{}, icumsg.PluralRules{})
+	f(t, plurals, "1 Dateien")
+}
@@ -5,12 +5,10 @@ package icumsg
 import (
 	"errors"
 	"iter"
-	"slices"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
-	"github.com/romshark/icumsg/internal/cldr"
 	"golang.org/x/text/language"
 )
 
@@ -145,10 +143,26 @@ type Token struct {
 }
 
 type Tokenizer struct {
-	loc    language.Tag
-	plural cldr.PluralRules
-	s      string
-	pos    int
+	// Plurals provides the plural rules of the locale.
+	// CLDRPluralRules is used if nil.
+	Plurals PluralRuleProvider
+
+	loc               language.Tag
+	cardinal, ordinal PluralRules
+	rulesLoaded       bool // cardinal and ordinal are loaded for loc.
+	s                 string
+	pos               int
+
+	// yield is the function tokens are yielded to by Tokens, nil for Tokenize.
+	yield func(Token, error) bool
+	// stopped is true if yield returned false.
+	stopped bool
+	// depth is the number of complex tokens yielded but not terminated.
+	depth int
+	// base is the stream index of the first token in the buffer.
+	base int
+	// stream is the buffer reused by Tokens.
+	stream []Token
 }
 
 // Pos returns the last position (byte offset in the input string) the tokenizer was at.
@@ -182,7 +196,9 @@ func (t Token) String(s string, buffer []Token) string {
 
 // Options returns an iterator iterating over all options of a select,
 // plural or selectordinal token at buffer[tokenIndex].
-// The iterator provides the indexes of option tokens.
+// The iterator provides the indexes of option tokens of all types,
+// including TokenTypeOptionNumber options such as "=1" of plural and
+// selectordinal arguments.
 // Returns a no-op iterator if buffer[tokenIndex] is neither of:
 //
 //   - TokenTypeSelect
@@ -202,6 +218,7 @@ func Options(buffer []Token, tokenIndex int) iter.Seq[int] {
 		for ti := tokenIndex + 2; ti < endIndex; {
 			switch buffer[ti].Type {
 			case TokenTypeOption,
+				TokenTypeOptionNumber,
 				TokenTypeOptionZero,
 				TokenTypeOptionOne,
 				TokenTypeOptionTwo,
@@ -219,161 +236,15 @@ func Options(buffer []Token, tokenIndex int) iter.Seq[int] {
 	}
 }
 
-// OptionsPresencePolicy defines treatment of known options.
-type OptionsPresencePolicy int8
-
-const (
-	// OptionsPresencePolicyOptional does not require all select options to be present
-	// for the ICU message to be considered complete.
-	OptionsPresencePolicyOptional = iota
-
-	// OptionsPresencePolicyRequired requires all select options to be present
-	// for the ICU message to be considered complete.
-	OptionsPresencePolicyRequired
-)
-
-// OptionUnknownPolicy defines treatment of unknown select options.
-type OptionUnknownPolicy int8
-
-const (
-	// OptionUnknownPolicyIgnore ignores unknown select options.
-	OptionUnknownPolicyIgnore OptionUnknownPolicy = iota
-	// OptionUnknownPolicyReject rejects unknown select options.
-	OptionUnknownPolicyReject
-)
-
-// Completeness returns the total number of choices in src.
-// onIncomplete is invoked when an incomplete, select, plural or selectordinal
-// is encountered.
-// onRejected is invoked when an unknown select option was encountered.
-// selectOptions is invoked when a select is encountered and if it returns
-// a slice then those will be the expected options the presence of which
-// will define whether the select is complete (depending on the policies returned).
-// selectOptions is not invoked for plural and selectordinal, instead locale is used
-// to determine what options are required.
-func Completeness(
-	src string,
-	buffer []Token,
-	locale language.Tag,
-	selectOptions func(argName string) (
-		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
-	),
-	onIncomplete func(index int),
-	onRejected func(index int),
-) (total int) {
-	return completeness(0, len(buffer), src, buffer, locale,
-		selectOptions, onIncomplete, onRejected)
-}
-
-func completeness(
-	startIndex, endIndex int,
-	src string,
-	buffer []Token,
-	locale language.Tag,
-	selectOptions func(argName string) (
-		[]string, OptionsPresencePolicy, OptionUnknownPolicy,
-	),
-	onIncomplete func(index int),
-	onRejected func(index int),
-) (total int) {
-	var pluralRules cldr.PluralRules
-	{ // Select plural rules
-		var ok bool
-		if pluralRules, ok = cldr.PluralRulesByTag[locale]; !ok {
-			base, _ := locale.Base()
-			pluralRules = cldr.PluralRulesByBase[base]
-		}
-	}
-
-	for i := startIndex; i < endIndex; i++ {
-		t := buffer[i]
-		switch t.Type {
-		case TokenTypeSelect:
-			total++
-			tn := buffer[i+1]
-			opts, presencePolicy, unknownPolicy := selectOptions(tn.String(src, buffer))
-			if len(opts) != 0 {
-				reqCount := len(opts)
-				for j := range Options(buffer, i) {
-					if buffer[j].Type != TokenTypeOptionOther {
-						name := buffer[j+1].String(src, buffer)
-						if inOpts := slices.Contains(opts, name); inOpts {
-							reqCount--
-						} else if unknownPolicy == OptionUnknownPolicyReject {
-							onRejected(j)
-						}
-						continue
-					}
-					total += completeness(
-						j, buffer[j].IndexEnd,
-						src, buffer, locale,
-						selectOptions, onIncomplete, onRejected,
-					)
-				}
-				if presencePolicy == OptionsPresencePolicyRequired && reqCount != 0 {
-					onIncomplete(i)
-				}
-			}
-			i = t.IndexEnd + 1
-		case TokenTypePlural:
-			total++
-			var rules cldr.Rules
-			for j := range Options(buffer, i) {
-				switch buffer[j].Type {
-				case TokenTypeOptionZero:
-					rules.Zero = true
-				case TokenTypeOptionOne:
-					rules.One = true
-				case TokenTypeOptionTwo:
-					rules.Two = true
-				case TokenTypeOptionFew:
-					rules.Few = true
-				case TokenTypeOptionMany:
-					rules.Many = true
-				case TokenTypeOptionOther:
-					rules.Other = true
-				}
-				total += completeness(
-					j, buffer[j].IndexEnd,
-					src, buffer, locale,
-					selectOptions, onIncomplete, onRejected,
-				)
-			}
-			if rules != pluralRules.Cardinal {
-				onIncomplete(i)
-			}
-			i = t.IndexEnd + 1
-		case TokenTypeSelectOrdinal:
-			total++
-			var rules cldr.Rules
-			for j := range Options(buffer, i) {
-				switch buffer[j].Type {
-				case TokenTypeOptionZero:
-					rules.Zero = true
-				case TokenTypeOptionOne:
-					rules.One = true
-				case TokenTypeOptionTwo:
-					rules.Two = true
-				case TokenTypeOptionFew:
-					rules.Few = true
-				case TokenTypeOptionMany:
-					rules.Many = true
-				case TokenTypeOptionOther:
-					rules.Other = true
-				}
-				total += completeness(
-					j, buffer[j].IndexEnd,
-					src, buffer, locale,
-					selectOptions, onIncomplete, onRejected,
-				)
-			}
-			if rules != pluralRules.Ordinal {
-				onIncomplete(i)
-			}
-			i = t.IndexEnd + 1
-		}
+// OptionName returns the name of the option at buffer[index],
+// such as "male", "=1" or "few".
+// buffer[index] must be an option token as provided by Options.
+func OptionName(src string, buffer []Token, index int) string {
+	switch buffer[index].Type {
+	case TokenTypeOption, TokenTypeOptionNumber:
+		return buffer[index+1].String(src, buffer)
 	}
-	return total
+	return categoryName(buffer[index].Type)
 }
 
 // Tokenize resets the tokenizer and appends any tokens encountered to buffer.
@@ -381,14 +252,7 @@ func (t *Tokenizer) Tokenize(
 	locale language.Tag, buffer []Token, s string,
 ) ([]Token, error) {
 	t.loc, t.s, t.pos = locale, s, 0 // Reset tokenizer.
-
-	{ // Select plural rules
-		var ok bool
-		if t.plural, ok = cldr.PluralRulesByTag[t.loc]; !ok {
-			base, _ := t.loc.Base()
-			t.plural = cldr.PluralRulesByBase[base]
-		}
-	}
+	t.rulesLoaded = false
 
 	if s == "" {
 		return buffer, nil
@@ -397,7 +261,7 @@ func (t *Tokenizer) Tokenize(
 		strings.IndexByte(s, '{') == -1 &&
 		strings.IndexByte(s, '}') == -1 {
 		// Fast path for simple inputs.
-		return append(buffer, Token{
+		return t.emit(buffer, Token{
 			IndexStart: 0,
 			IndexEnd:   len(s),
 			Type:       TokenTypeLiteral,
@@ -415,9 +279,78 @@ func (t *Tokenizer) Tokenize(
 	return buffer, nil
 }
 
+// Tokens returns an iterator over the tokens of s yielding them as
+// they're produced instead of materializing the whole buffer, which
+// allows stopping early, for example after the first argument.
+// If s is invalid the error is yielded last with a zero Token,
+// tokens yielded before belong to the invalid message.
+//
+// Complex tokens (plural, select, selectordinal and options) are yielded
+// before their contents, so their IndexEnd isn't known yet and is 0.
+// Instead, the terminator yielded after the contents reports the link:
+// its IndexStart is the index of the complex token in the sequence of
+// yielded tokens. All other tokens are the same as those of Tokenize.
+//
+// Only the tokens of the innermost top-level argument are kept in memory.
+// The tokenizer must not be used for anything else during iteration.
+func (t *Tokenizer) Tokens(locale language.Tag, s string) iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		t.yield, t.stopped, t.depth, t.base = yield, false, 0, 0
+		defer func() { t.yield = nil }()
+		var err error
+		t.stream, err = t.Tokenize(locale, t.stream[:0], s)
+		if err != nil && !t.stopped {
+			yield(Token{}, err)
+		}
+	}
+}
+
+// errStopped is returned by the tokenizer if yield returned false.
+var errStopped = errors.New("stopped")
+
+// emit appends tokens to buffer and yields them if the tokenizer streams.
+// It's small enough to be inlined so that Tokenize appends directly.
+func (t *Tokenizer) emit(buffer []Token, tokens ...Token) []Token {
+	if t.yield == nil {
+		return append(buffer, tokens...)
+	}
+	return t.emitStream(buffer, tokens)
+}
+
+// emitStream appends tokens to buffer and yields them.
+// Once no complex token is open, the yielded tokens are dropped from
+// buffer since no token refers to them anymore.
+func (t *Tokenizer) emitStream(buffer, tokens []Token) []Token {
+	for _, tk := range tokens {
+		if t.stopped {
+			return buffer
+		}
+		buffer = append(buffer, tk)
+		switch {
+		case tk.Type >= TokenTypePlural && tk.Type <= TokenTypeOptionNumber:
+			t.depth++
+			tk.IndexEnd = 0
+		case tk.Type > TokenTypeOptionNumber: // Terminators.
+			t.depth--
+			tk.IndexStart += t.base
+		}
+		if !t.yield(tk, nil) {
+			t.stopped = true
+		}
+		if t.depth == 0 {
+			t.base += len(buffer)
+			buffer = buffer[:0]
+		}
+	}
+	return buffer
+}
+
 func (t *Tokenizer) consumeExpr(buffer []Token) ([]Token, error) {
 	var err error
 	for t.pos < len(t.s) {
+		if t.stopped {
+			return buffer, errStopped
+		}
 		if t.s[t.pos] == '}' {
 			break
 		}
@@ -476,7 +409,7 @@ func (t *Tokenizer) consumeArgument(buffer []Token) ([]Token, error) {
 		}
 
 		t.pos++ // Consume the '}'.
-		buffer = append(buffer, Token{
+		buffer = t.emit(buffer, Token{
 			IndexStart: start,
 			IndexEnd:   t.pos,
 			Type:       TokenTypeSimpleArg,
@@ -520,7 +453,7 @@ func (t *Tokenizer) consumeArgument(buffer []Token) ([]Token, error) {
 			}
 			t.pos++ // Consume the closing bracket.
 
-			buffer = append(buffer, Token{
+			buffer = t.emit(buffer, Token{
 				IndexStart: start,
 				IndexEnd:   t.pos,
 				Type:       TokenTypeSimpleArg,
@@ -530,7 +463,7 @@ func (t *Tokenizer) consumeArgument(buffer []Token) ([]Token, error) {
 				Type:       TokenTypeArgName,
 			}, tokenArgType)
 			if tokenArgStyle.Type != 0 {
-				buffer = append(buffer, tokenArgStyle)
+				buffer = t.emit(buffer, tokenArgStyle)
 			}
 
 			return buffer, nil
@@ -644,7 +577,7 @@ func (t *Tokenizer) consumeArgStyle() (token Token, err error) {
 
 func (t *Tokenizer) skipWhitespaces() {
 	for ; t.pos < len(t.s); t.pos++ {
-		if !isWhitespace(t.s[t.pos]) {
+		if !IsWhitespace(t.s[t.pos]) {
 			break
 		}
 	}
@@ -665,7 +598,7 @@ func (t *Tokenizer) consumeSelectArg(
 
 	initiatorBufIndex := len(buffer)
 
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: start,
 		IndexEnd:   0, // This is determined later.
 		Type:       TokenTypeSelect,
@@ -698,7 +631,7 @@ func (t *Tokenizer) consumeSelectArg(
 
 	// Link the argument initiator to the argument terminator.
 	buffer[initiatorBufIndex].IndexEnd = len(buffer)
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: initiatorBufIndex,
 		IndexEnd:   t.pos,
 		Type:       TokenTypeComplexArgTerm,
@@ -723,13 +656,13 @@ func (t *Tokenizer) consumeOption(buffer []Token) ([]Token, error) {
 	t.pos = end
 
 	initiatorBufIndex = len(buffer)
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: start,
 		IndexEnd:   0, // Set later to terminator buffer index.
 		Type:       tp,
 	})
 	if tp == TokenTypeOption {
-		buffer = append(buffer, Token{
+		buffer = t.emit(buffer, Token{
 			IndexStart: start,
 			IndexEnd:   t.pos,
 			Type:       TokenTypeOptionName,
@@ -776,7 +709,7 @@ func (t *Tokenizer) consumeOption(buffer []Token) ([]Token, error) {
 
 	// Link the argument initiator to the argument terminator.
 	buffer[initiatorBufIndex].IndexEnd = len(buffer)
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: initiatorBufIndex,
 		IndexEnd:   t.pos,
 		Type:       TokenTypeOptionTerm,
@@ -785,7 +718,7 @@ func (t *Tokenizer) consumeOption(buffer []Token) ([]Token, error) {
 	return buffer, nil
 }
 
-func (t *Tokenizer) consumeOptionPlural(buffer []Token, f cldr.Rules) ([]Token, error) {
+func (t *Tokenizer) consumeOptionPlural(buffer []Token, f PluralRules) ([]Token, error) {
 	start := t.pos
 	tp := TokenTypeOptionNumber
 	var initiatorBufIndex int
@@ -820,37 +753,16 @@ func (t *Tokenizer) consumeOptionPlural(buffer []Token, f cldr.Rules) ([]Token,
 			}
 		}
 
-		option := t.s[start:t.pos]
-		switch option {
+		switch t.s[start:t.pos] {
 		case "zero":
-			if !f.Zero {
-				t.pos = start // Rollback.
-				return buffer, ErrUnsupportedPluralRule
-			}
 			tp = TokenTypeOptionZero
 		case "one":
-			if !f.One {
-				t.pos = start // Rollback.
-				return buffer, ErrUnsupportedPluralRule
-			}
 			tp = TokenTypeOptionOne
 		case "two":
-			if !f.Two {
-				t.pos = start // Rollback.
-				return buffer, ErrUnsupportedPluralRule
-			}
 			tp = TokenTypeOptionTwo
 		case "few":
-			if !f.Few {
-				t.pos = start // Rollback.
-				return buffer, ErrUnsupportedPluralRule
-			}
 			tp = TokenTypeOptionFew
 		case "many":
-			if !f.Many {
-				t.pos = start // Rollback.
-				return buffer, ErrUnsupportedPluralRule
-			}
 			tp = TokenTypeOptionMany
 		case "other":
 			tp = TokenTypeOptionOther
@@ -858,16 +770,20 @@ func (t *Tokenizer) consumeOptionPlural(buffer []Token, f cldr.Rules) ([]Token,
 			t.pos = start // Roll back to start.
 			return buffer, ErrInvalidOption
 		}
+		if tp != TokenTypeOptionOther && !f.has(tp) {
+			t.pos = start // Rollback.
+			return buffer, ErrUnsupportedPluralRule
+		}
 	}
 
 	initiatorBufIndex = len(buffer)
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: start,
 		IndexEnd:   0, // Set later to terminator buffer index.
 		Type:       tp,
 	})
 	if tp == TokenTypeOptionNumber {
-		buffer = append(buffer, Token{
+		buffer = t.emit(buffer, Token{
 			IndexStart: numStart,
 			IndexEnd:   t.pos,
 			Type:       TokenTypeOptionName,
@@ -913,7 +829,7 @@ func (t *Tokenizer) consumeOptionPlural(buffer []Token, f cldr.Rules) ([]Token,
 	// Link the argument initiator to the argument terminator.
 	buffer[initiatorBufIndex].IndexEnd = len(buffer)
 	t.pos++ // Consume closing bracket.
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: initiatorBufIndex,
 		IndexEnd:   t.pos,
 		Type:       TokenTypeOptionTerm,
@@ -938,7 +854,7 @@ func (t *Tokenizer) consumeSelectOrdinalArg(
 
 	initiatorBufIndex := len(buffer)
 
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: start,
 		IndexEnd:   0, // This is determined later.
 		Type:       TokenTypeSelectOrdinal,
@@ -958,7 +874,7 @@ func (t *Tokenizer) consumeSelectOrdinalArg(
 		}
 
 		var err error
-		buffer, err = t.consumeOptionPlural(buffer, t.plural.Ordinal)
+		buffer, err = t.consumeOptionPlural(buffer, t.pluralRules(true))
 		if err != nil {
 			return buffer, err
 		}
@@ -973,7 +889,7 @@ func (t *Tokenizer) consumeSelectOrdinalArg(
 
 	// Link the argument initiator to the argument terminator.
 	buffer[initiatorBufIndex].IndexEnd = len(buffer)
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: initiatorBufIndex,
 		IndexEnd:   t.pos,
 		Type:       TokenTypeComplexArgTerm,
@@ -995,7 +911,7 @@ func (t *Tokenizer) consumePluralArg(
 
 	initiatorBufIndex := len(buffer)
 
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: start,
 		IndexEnd:   0, // This is determined later.
 		Type:       TokenTypePlural,
@@ -1047,7 +963,7 @@ func (t *Tokenizer) consumePluralArg(
 		}
 
 		var err error
-		buffer, err = t.consumeOptionPlural(buffer, t.plural.Cardinal)
+		buffer, err = t.consumeOptionPlural(buffer, t.pluralRules(false))
 		if err != nil {
 			return buffer, err
 		}
@@ -1060,7 +976,7 @@ func (t *Tokenizer) consumePluralArg(
 
 	// Link the argument initiator to the argument terminator.
 	buffer[initiatorBufIndex].IndexEnd = len(buffer)
-	buffer = append(buffer, Token{
+	buffer = t.emit(buffer, Token{
 		IndexStart: initiatorBufIndex,
 		IndexEnd:   t.pos,
 		Type:       TokenTypeComplexArgTerm,
@@ -1069,51 +985,21 @@ func (t *Tokenizer) consumePluralArg(
 }
 
 func (t *Tokenizer) validateOptions(buffer []Token, bufIndex, startArg int) error {
-	var zero, one, two, few, many, other bool
+	var seen PluralRules
 	for i := bufIndex; i < len(buffer); i++ {
 		outer := buffer[i]
 		switch outer.Type {
-		case TokenTypeOptionZero:
-			if zero {
+		case TokenTypeOptionZero,
+			TokenTypeOptionOne,
+			TokenTypeOptionTwo,
+			TokenTypeOptionFew,
+			TokenTypeOptionMany,
+			TokenTypeOptionOther:
+			if seen.has(outer.Type) {
 				t.pos = outer.IndexStart
 				return ErrDuplicateOption
 			}
-			zero = true
-			i = outer.IndexEnd // Skip contents.
-		case TokenTypeOptionOne:
-			if one {
-				t.pos = outer.IndexStart
-				return ErrDuplicateOption
-			}
-			one = true
-			i = outer.IndexEnd // Skip contents.
-		case TokenTypeOptionTwo:
-			if two {
-				t.pos = outer.IndexStart
-				return ErrDuplicateOption
-			}
-			two = true
-			i = outer.IndexEnd // Skip contents.
-		case TokenTypeOptionFew:
-			if few {
-				t.pos = outer.IndexStart
-				return ErrDuplicateOption
-			}
-			few = true
-			i = outer.IndexEnd // Skip contents.
-		case TokenTypeOptionMany:
-			if many {
-				t.pos = outer.IndexStart
-				return ErrDuplicateOption
-			}
-			many = true
-			i = outer.IndexEnd // Skip contents.
-		case TokenTypeOptionOther:
-			if other {
-				t.pos = outer.IndexStart
-				return ErrDuplicateOption
-			}
-			other = true
+			seen.set(outer.Type)
 			i = outer.IndexEnd // Skip contents.
 		case TokenTypeOptionNumber, TokenTypeOption:
 			nameToken := buffer[i+1]
@@ -1145,17 +1031,34 @@ func (t *Tokenizer) validateOptions(buffer []Token, bufIndex, startArg int) erro
 			i = outer.IndexEnd // Skip contents.
 		}
 	}
-	if !other {
+	if !seen.Other {
 		t.pos = startArg // Rollback.
 		return ErrMissingOptionOther
 	}
 	return nil
 }
 
-func isWhitespace(b byte) bool {
+// IsWhitespace returns true if b is whitespace as skipped by the tokenizer
+// between argument names, types, styles and options.
+func IsWhitespace(b byte) bool {
 	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
 }
 
+// pluralRules returns the ordinal plural rules of the locale if ordinal,
+// otherwise the cardinal ones. The rules are looked up only once per
+// message and only for messages that have plural or selectordinal
+// arguments, which keeps tokenizing messages without them cheap.
+func (t *Tokenizer) pluralRules(ordinal bool) PluralRules {
+	if !t.rulesLoaded {
+		t.cardinal, t.ordinal = pluralRules(t.Plurals, t.loc)
+		t.rulesLoaded = true
+	}
+	if ordinal {
+		return t.ordinal
+	}
+	return t.cardinal
+}
+
 func (t *Tokenizer) isEOF() bool { return t.pos >= len(t.s) }
 
 func (t *Tokenizer) consumePluralOffsetNum(buffer []Token) ([]Token, error) {
@@ -1165,7 +1068,7 @@ func (t *Tokenizer) consumePluralOffsetNum(buffer []Token) ([]Token, error) {
 	start := t.pos
 	if t.s[t.pos] == '0' {
 		t.pos++ // Consume the zero as the number since leading zeros are not allowed.
-		buffer = append(buffer, Token{
+		buffer = t.emit(buffer, Token{
 			IndexStart: start,
 			IndexEnd:   t.pos,
 			Type:       TokenTypePluralOffset,
@@ -1179,7 +1082,7 @@ func (t *Tokenizer) consumePluralOffsetNum(buffer []Token) ([]Token, error) {
 				return buffer, ErrInvalidOffset
 			}
 
-			buffer = append(buffer, Token{
+			buffer = t.emit(buffer, Token{
 				IndexStart: start,
 				IndexEnd:   t.pos,
 				Type:       TokenTypePluralOffset,
@@ -1262,7 +1165,7 @@ func (t *Tokenizer) consumeLiteral(buffer []Token) ([]Token, error) {
 		return buffer, ErrUnclosedQuote
 	}
 	if t.pos > start {
-		buffer = append(buffer, Token{
+		buffer = t.emit(buffer, Token{
 			IndexStart: start,
 			IndexEnd:   t.pos,
 			Type:       TokenTypeLiteral,
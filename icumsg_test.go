@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 
 	"golang.org/x/text/language"
 
 	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/format"
 	"github.com/romshark/icumsg/internal/test"
 )
 
@@ -758,6 +760,104 @@ func TestTokenizeErr(t *testing.T) {
 	}
 }
 
+// requireTokensEqualBuffer requires the tokens yielded by Tokenizer.Tokens
+// to be equal to buffer once complex tokens are linked to their terminators.
+func requireTokensEqualBuffer(t testing.TB, buffer, tokens []icumsg.Token) {
+	t.Helper()
+	linked := slices.Clone(tokens)
+	for i, tk := range linked {
+		switch {
+		case tk.Type >= icumsg.TokenTypePlural && tk.Type <= icumsg.TokenTypeOptionNumber:
+			test.RequireEqual(t, 0, tk.IndexEnd)
+		case tk.Type > icumsg.TokenTypeOptionNumber:
+			linked[tk.IndexStart].IndexEnd = i
+		}
+	}
+	test.RequireDeepEqual(t, buffer, linked)
+}
+
+func TestTokens(t *testing.T) {
+	t.Parallel()
+
+	var tokenizer, streamer icumsg.Tokenizer
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(language.English, nil, input)
+		test.RequireNoErr(t, err)
+		var tokens []icumsg.Token
+		for tk, err := range streamer.Tokens(language.English, input) {
+			test.RequireNoErr(t, err)
+			tokens = append(tokens, tk)
+		}
+		requireTokensEqualBuffer(t, buffer, tokens)
+	}
+
+	f(t, "")
+	f(t, "foo")
+	f(t, "'{}' {x} {y, number, integer} {d, date, ::yyyy}")
+	f(t, "{n, plural, offset:1 =0 {none} one {# {x}} other {#}} after")
+	f(t, "a {g, select, other {{n, selectordinal, one {#st} other {#th}}}} b {c} "+
+		"{g, select, x {X} other {O}}")
+	f(t, ReadFile[string](t, "testdata/lorem_ipsum.txt"))
+	f(t, ReadFile[string](t, "testdata/lorem_ipsum_args.icu.txt"))
+	f(t, ReadFile[string](t, "testdata/nested.icu.txt"))
+}
+
+func TestTokensBreak(t *testing.T) {
+	t.Parallel()
+
+	// Stop at the first argument, the invalid rest is never tokenized.
+	input := "Hello {name}, {n, plural, one {#}} {"
+	var tokenizer icumsg.Tokenizer
+	var types []icumsg.TokenType
+	for tk, err := range tokenizer.Tokens(language.English, input) {
+		test.RequireNoErr(t, err)
+		types = append(types, tk.Type)
+		if tk.Type == icumsg.TokenTypeArgName {
+			break
+		}
+	}
+	test.RequireDeepEqual(t, []icumsg.TokenType{
+		icumsg.TokenTypeLiteral, icumsg.TokenTypeSimpleArg, icumsg.TokenTypeArgName,
+	}, types)
+
+	// Stop at the last option, the duplicate option error isn't yielded.
+	input = "{n, plural, one {a} one {b}}"
+	types = types[:0]
+	for tk, err := range tokenizer.Tokens(language.English, input) {
+		test.RequireNoErr(t, err)
+		types = append(types, tk.Type)
+		if len(types) == 8 {
+			test.RequireEqual(t, icumsg.TokenTypeOptionTerm, tk.Type)
+			break
+		}
+	}
+	test.RequireEqual(t, 8, len(types))
+}
+
+func TestTokensErr(t *testing.T) {
+	t.Parallel()
+
+	var tokenizer icumsg.Tokenizer
+	for _, tt := range TestsErrors {
+		t.Run("", func(t *testing.T) {
+			t.Logf("input: %q", tt.Input)
+			var errs []error
+			for tk, err := range tokenizer.Tokens(language.MustParse("cy"), tt.Input) {
+				if err != nil {
+					test.RequireEqual(t, icumsg.Token{}, tk)
+				}
+				errs = append(errs, err)
+			}
+			test.RequireErrIs(t, tt.ExpectErr, errs[len(errs)-1])
+			test.RequireEqual(t, tt.ExpectErrIndex, tokenizer.Pos())
+			for _, err := range errs[:len(errs)-1] {
+				test.RequireNoErr(t, err)
+			}
+		})
+	}
+}
+
 func TestOptions(t *testing.T) {
 	var tokenizer icumsg.Tokenizer
 	var buffer []icumsg.Token
@@ -780,6 +880,12 @@ func TestOptions(t *testing.T) {
 	fn(t, "Prefix {x, plural, other {a} one {b}}", 1,
 		Token{Str: "other {a}", Type: icumsg.TokenTypeOptionOther},
 		Token{Str: "one {b}", Type: icumsg.TokenTypeOptionOne})
+	fn(t, "Prefix {x, plural, =0{a {y, select, z{z} other{o}}} other {b}}", 1,
+		Token{Str: "=0{a {y, select, z{z} other{o}}}", Type: icumsg.TokenTypeOptionNumber},
+		Token{Str: "other {b}", Type: icumsg.TokenTypeOptionOther})
+	fn(t, "Prefix {x, selectordinal, =1{first} other{#th}}", 1,
+		Token{Str: "=1{first}", Type: icumsg.TokenTypeOptionNumber},
+		Token{Str: "other{#th}", Type: icumsg.TokenTypeOptionOther})
 	fn(t, "Prefix {x,select, other{x}}", 1,
 		Token{Str: "other{x}", Type: icumsg.TokenTypeOptionOther})
 	fn(t, "Prefix {x,select,other{o}opt1{a}opt2{b}opt3{c}opt4{d}}", 1,
@@ -1016,6 +1122,39 @@ func TestCompleteness(t *testing.T) {
 			"{_1, select, a{a} other{o}}",
 		}, nil)
 
+	// Nested plural in every select option.
+	fn(t, language.English,
+		`{_0, select,
+			male{   {_1, plural, other{his #}} }
+			female{ {_1, plural, one{her #} other{her #}} }
+			other{  {_1, plural, other{their #}} }
+		}`,
+		map[string][]string{"_0": {"male", "female"}},
+		icumsg.OptionsPresencePolicyRequired, icumsg.OptionUnknownPolicyReject,
+		4, []string{
+			"{_1, plural, other{his #}}",
+			"{_1, plural, other{their #}}",
+		}, nil)
+
+	// Nested plural in select without known options.
+	fn(t, language.English,
+		`{_0, select, a{ {_1, plural, other{a}} } other{o}}`,
+		nil, icumsg.OptionsPresencePolicyRequired, icumsg.OptionUnknownPolicyReject,
+		2, []string{"{_1, plural, other{a}}"}, nil)
+
+	// Nested plural in rejected option.
+	fn(t, language.English,
+		`{_0, select, x{ {_1, plural, other{x}} } other{o}}`,
+		map[string][]string{"_0": {"a"}},
+		icumsg.OptionsPresencePolicyOptional, icumsg.OptionUnknownPolicyReject,
+		2, []string{"{_1, plural, other{x}}"}, []string{"x{ {_1, plural, other{x}} }"})
+
+	// Adjacent choices.
+	fn(t, language.English,
+		`{_0, plural, other{a}}{_1, plural, other{b}}{_2, select, other{c}}`,
+		nil, 0, 0,
+		3, []string{"{_0, plural, other{a}}", "{_1, plural, other{b}}"}, nil)
+
 	// Select in ordinal.
 	fn(t, language.English,
 		`some missing and some rejected: {_0, selectordinal,
@@ -1051,9 +1190,27 @@ func Fuzz(f *testing.F) {
 	var tokenizer icumsg.Tokenizer
 	buffer := make([]icumsg.Token, 0, 64)
 
+	var streamer icumsg.Tokenizer
+	var tokens []icumsg.Token
+
 	f.Fuzz(func(t *testing.T, input string) {
 		buffer = buffer[:0]
-		_, _ = tokenizer.Tokenize(language.English, buffer, input)
+		buffer, err := tokenizer.Tokenize(language.English, buffer, input)
+
+		// Tokens yields the same tokens and error.
+		tokens = tokens[:0]
+		var streamErr error
+		for tk, err := range streamer.Tokens(language.English, input) {
+			if err != nil {
+				streamErr = err
+				break
+			}
+			tokens = append(tokens, tk)
+		}
+		test.RequireEqual(t, err, streamErr)
+		if err == nil {
+			requireTokensEqualBuffer(t, buffer, tokens)
+		}
 	})
 }
 
@@ -1061,19 +1218,74 @@ func BenchmarkTokenize(b *testing.B) {
 	var tokenizer icumsg.Tokenizer
 	buffer := make([]icumsg.Token, 0, 64)
 
-	for _, input := range [...]string{
+	for _, input := range benchmarkMessages(b) {
+		b.Run("", func(b *testing.B) {
+			for b.Loop() {
+				var err error
+				buffer = buffer[:0] // Reset buffer.
+				buffer, err = tokenizer.Tokenize(language.English, buffer, input)
+				if err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTokens(b *testing.B) {
+	var tokenizer icumsg.Tokenizer
+
+	for _, input := range benchmarkMessages(b) {
+		b.Run("", func(b *testing.B) {
+			for b.Loop() {
+				for _, err := range tokenizer.Tokens(language.English, input) {
+					if err != nil {
+						panic(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func benchmarkMessages(b *testing.B) []string {
+	return []string{
 		"Very small",
 		"Good morning {userName}, how are you?",
 		ReadFile[string](b, "testdata/lorem_ipsum.txt"),
 		ReadFile[string](b, "testdata/lorem_ipsum_args.icu.txt"),
 		ReadFile[string](b, "testdata/nested.icu.txt"),
-	} {
+	}
+}
+
+func BenchmarkCompile(b *testing.B) {
+	for _, input := range benchmarkMessages(b) {
 		b.Run("", func(b *testing.B) {
 			for b.Loop() {
-				var err error
-				buffer = buffer[:0] // Reset buffer.
-				buffer, err = tokenizer.Tokenize(language.English, buffer, input)
-				if err != nil {
+				if _, err := format.Compile(language.English, input); err != nil {
+					panic(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMessageAppend(b *testing.B) {
+	values := map[string]any{"gender": "female", "numMessages": 5}
+	dst := make([]byte, 0, 4096)
+
+	for _, input := range benchmarkMessages(b) {
+		m, err := format.Compile(language.English, input)
+		test.RequireNoErr(b, err)
+		args := make([]any, len(m.Args()))
+		for i, name := range m.Args() {
+			if args[i] = values[name]; args[i] == nil {
+				args[i] = name
+			}
+		}
+		b.Run("", func(b *testing.B) {
+			for b.Loop() {
+				if dst, err = m.Append(dst[:0], args...); err != nil {
 					panic(err)
 				}
 			}
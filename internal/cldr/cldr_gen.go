@@ -6,6 +6,9 @@ package cldr
 
 import "golang.org/x/text/language"
 
+// CLDRVersion is the version of CLDR the rules are generated from.
+const CLDRVersion = "47"
+
 // Rules defines supported CLDR plural rules.
 type Rules struct{ Zero, One, Two, Few, Many, Other bool }
 
@@ -697,3 +700,724 @@ func init() {
 		Rules{Other: true, One: true},
 		Rules{Other: true}, true)
 }
+
+// Samples defines integer samples of CLDR plural categories.
+type Samples struct{ Zero, One, Two, Few, Many, Other []uint64 }
+
+// CardinalSamplesByTag maps language tags to integer samples
+// of cardinal plural categories.
+var CardinalSamplesByTag = make(map[language.Tag]Samples, 219)
+
+// CardinalSamplesByBase maps base languages to integer samples
+// of cardinal plural categories.
+var CardinalSamplesByBase = make(map[language.Base]Samples, 219)
+
+func init() {
+	register := func(s string, samples Samples, isBase bool) {
+		l, err := language.Parse(s)
+		if err != nil {
+			panic(err)
+		}
+		CardinalSamplesByTag[l] = samples
+		if isBase {
+			base, _ := l.Base()
+			CardinalSamplesByBase[base] = samples
+		}
+	}
+	register("af", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ak", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("am", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("an", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ar", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Few:   []uint64{3, 4, 5, 6, 7, 8, 9, 10, 103, 104, 105, 106, 107, 108, 109, 110, 1003},
+		Many:  []uint64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 111, 1011},
+		Other: []uint64{100, 101, 102, 200, 201, 202, 300, 301, 302, 400, 401, 402, 500, 501, 502, 600, 1000, 10000, 100000, 1000000}}, true)
+	register("ars", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Few:   []uint64{3, 4, 5, 6, 7, 8, 9, 10, 103, 104, 105, 106, 107, 108, 109, 110, 1003},
+		Many:  []uint64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 111, 1011},
+		Other: []uint64{100, 101, 102, 200, 201, 202, 300, 301, 302, 400, 401, 402, 500, 501, 502, 600, 1000, 10000, 100000, 1000000}}, true)
+	register("as", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("asa", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ast", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("az", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bal", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("be", Samples{
+		One:  []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:  []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Many: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bem", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bez", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bg", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bho", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("blo", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bm", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bn", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bo", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("br", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 81, 101, 1001},
+		Two:   []uint64{2, 22, 32, 42, 52, 62, 82, 102, 1002},
+		Few:   []uint64{3, 4, 9, 23, 24, 29, 33, 34, 39, 43, 44, 49, 103, 1003},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 5, 6, 7, 8, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 100, 1000, 10000, 100000}}, true)
+	register("brx", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("bs", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:   []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ca", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, true)
+	register("ce", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ceb", Samples{
+		One:   []uint64{0, 1, 2, 3, 5, 7, 8, 10, 11, 12, 13, 15, 17, 18, 20, 21, 100, 1000, 10000, 100000, 1000000},
+		Other: []uint64{4, 6, 9, 14, 16, 19, 24, 26, 104, 1004}}, true)
+	register("cgg", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("chr", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ckb", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("cs", Samples{
+		One:   []uint64{1},
+		Few:   []uint64{2, 3, 4},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("csw", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("cy", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Few:   []uint64{3},
+		Many:  []uint64{6},
+		Other: []uint64{4, 5, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("da", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("de", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("doi", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("dsb", Samples{
+		One:   []uint64{1, 101, 201, 301, 401, 501, 601, 701, 1001},
+		Two:   []uint64{2, 102, 202, 302, 402, 502, 602, 702, 1002},
+		Few:   []uint64{3, 4, 103, 104, 203, 204, 303, 304, 403, 404, 503, 504, 603, 604, 703, 704, 1003},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("dv", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("dz", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ee", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("el", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("en", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("eo", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("es", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, true)
+	register("et", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("eu", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("fa", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ff", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("fi", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("fil", Samples{
+		One:   []uint64{0, 1, 2, 3, 5, 7, 8, 10, 11, 12, 13, 15, 17, 18, 20, 21, 100, 1000, 10000, 100000, 1000000},
+		Other: []uint64{4, 6, 9, 14, 16, 19, 24, 26, 104, 1004}}, true)
+	register("fo", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("fr", Samples{
+		One:   []uint64{0, 1},
+		Many:  []uint64{1000000},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000}}, true)
+	register("fur", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("fy", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ga", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Few:   []uint64{3, 4, 5, 6},
+		Many:  []uint64{7, 8, 9, 10},
+		Other: []uint64{0, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("gd", Samples{
+		One:   []uint64{1, 11},
+		Two:   []uint64{2, 12},
+		Few:   []uint64{3, 4, 5, 6, 7, 8, 9, 10, 13, 14, 15, 16, 17, 18, 19},
+		Other: []uint64{0, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("gl", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("gsw", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("gu", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("guw", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("gv", Samples{
+		One:   []uint64{1, 11, 21, 31, 41, 51, 61, 71, 101, 1001},
+		Two:   []uint64{2, 12, 22, 32, 42, 52, 62, 72, 102, 1002},
+		Few:   []uint64{0, 20, 40, 60, 80, 100, 120, 140, 1000, 10000, 100000, 1000000},
+		Other: []uint64{3, 4, 5, 6, 7, 8, 9, 10, 13, 14, 15, 16, 17, 18, 19, 23, 103, 1003}}, true)
+	register("ha", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("haw", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("he", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("hi", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("hnj", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("hr", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:   []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("hsb", Samples{
+		One:   []uint64{1, 101, 201, 301, 401, 501, 601, 701, 1001},
+		Two:   []uint64{2, 102, 202, 302, 402, 502, 602, 702, 1002},
+		Few:   []uint64{3, 4, 103, 104, 203, 204, 303, 304, 403, 404, 503, 504, 603, 604, 703, 704, 1003},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("hu", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("hy", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ia", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("id", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ig", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ii", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("io", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("is", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("it", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, true)
+	register("iu", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ja", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("jbo", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("jgo", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("jmc", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("jv", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("jw", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, false)
+	register("ka", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kab", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kaj", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kcg", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kde", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kea", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kk", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kkj", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kl", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("km", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kn", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ko", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ks", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ksb", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ksh", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ku", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("kw", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Two:   []uint64{2, 22, 42, 62, 82, 102, 122, 142, 1000, 10000, 100000},
+		Few:   []uint64{3, 23, 43, 63, 83, 103, 123, 143, 1003},
+		Many:  []uint64{21, 41, 61, 81, 101, 121, 141, 161, 1001},
+		Other: []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1004, 1000000}}, true)
+	register("ky", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lag", Samples{
+		Zero:  []uint64{0},
+		One:   []uint64{1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lb", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lg", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lij", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lkt", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lld", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, true)
+	register("ln", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lo", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lt", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:   []uint64{2, 3, 4, 5, 6, 7, 8, 9, 22, 23, 24, 25, 26, 27, 28, 29, 102, 1002},
+		Other: []uint64{0, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 30, 40, 50, 60, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("lv", Samples{
+		Zero:  []uint64{0, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 30, 40, 50, 60, 100, 1000, 10000, 100000, 1000000},
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 22, 23, 24, 25, 26, 27, 28, 29, 102, 1002}}, true)
+	register("mas", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("mg", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("mgo", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("mk", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ml", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("mn", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("mo", Samples{
+		One:   []uint64{1},
+		Few:   []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 101, 1001},
+		Other: []uint64{20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 100, 1000, 10000, 100000, 1000000}}, false)
+	register("mr", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ms", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("mt", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Few:   []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 103, 104, 105, 106, 107, 108, 109, 1003},
+		Many:  []uint64{11, 12, 13, 14, 15, 16, 17, 18, 19, 111, 112, 113, 114, 115, 116, 117, 1011},
+		Other: []uint64{20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("my", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nah", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("naq", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nb", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nd", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ne", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nl", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nn", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nnh", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("no", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nqo", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nr", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nso", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ny", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("nyn", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("om", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("or", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("os", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("osa", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("pa", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("pap", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("pcm", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("pl", Samples{
+		One:  []uint64{1},
+		Few:  []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Many: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("prg", Samples{
+		Zero:  []uint64{0, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 30, 40, 50, 60, 100, 1000, 10000, 100000, 1000000},
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 22, 23, 24, 25, 26, 27, 28, 29, 102, 1002}}, true)
+	register("ps", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("pt", Samples{
+		One:   []uint64{0, 1},
+		Many:  []uint64{1000000},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000}}, true)
+	register("pt-PT", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, false)
+	register("rm", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ro", Samples{
+		One:   []uint64{1},
+		Few:   []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 101, 1001},
+		Other: []uint64{20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("rof", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ru", Samples{
+		One:  []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:  []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Many: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("rwk", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sah", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("saq", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sat", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sc", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("scn", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, true)
+	register("sd", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sdh", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("se", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("seh", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ses", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sg", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sh", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:   []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, false)
+	register("shi", Samples{
+		One:   []uint64{0, 1},
+		Few:   []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10},
+		Other: []uint64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("si", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sk", Samples{
+		One:   []uint64{1},
+		Few:   []uint64{2, 3, 4},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sl", Samples{
+		One:   []uint64{1, 101, 201, 301, 401, 501, 601, 701, 1001},
+		Two:   []uint64{2, 102, 202, 302, 402, 502, 602, 702, 1002},
+		Few:   []uint64{3, 4, 103, 104, 203, 204, 303, 304, 403, 404, 503, 504, 603, 604, 703, 704, 1003},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sma", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("smi", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("smj", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("smn", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sms", Samples{
+		One:   []uint64{1},
+		Two:   []uint64{2},
+		Other: []uint64{0, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sn", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("so", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sq", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sr", Samples{
+		One:   []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:   []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Other: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ss", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ssy", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("st", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("su", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sv", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("sw", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("syr", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ta", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("te", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("teo", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("th", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ti", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("tig", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("tk", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("tl", Samples{
+		One:   []uint64{0, 1, 2, 3, 5, 7, 8, 10, 11, 12, 13, 15, 17, 18, 20, 21, 100, 1000, 10000, 100000, 1000000},
+		Other: []uint64{4, 6, 9, 14, 16, 19, 24, 26, 104, 1004}}, false)
+	register("tn", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("to", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("tpi", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("tr", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ts", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("tzm", Samples{
+		One:   []uint64{0, 1, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 100, 101, 102, 103, 104, 105, 106, 1000, 10000, 100000, 1000000}}, true)
+	register("ug", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("uk", Samples{
+		One:  []uint64{1, 21, 31, 41, 51, 61, 71, 81, 101, 1001},
+		Few:  []uint64{2, 3, 4, 22, 23, 24, 32, 33, 34, 42, 43, 44, 52, 53, 54, 62, 102, 1002},
+		Many: []uint64{0, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("und", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, false)
+	register("ur", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("uz", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("ve", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("vec", Samples{
+		One:   []uint64{1},
+		Many:  []uint64{1000000},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000}}, true)
+	register("vi", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("vo", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("vun", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("wa", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("wae", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("wo", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("xh", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("xog", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("yi", Samples{
+		One:   []uint64{1},
+		Other: []uint64{0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("yo", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("yue", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("zh", Samples{
+		Other: []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 100, 1000, 10000, 100000, 1000000}}, true)
+	register("zu", Samples{
+		One:   []uint64{0, 1},
+		Other: []uint64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 100, 1000, 10000, 100000, 1000000}}, true)
+}
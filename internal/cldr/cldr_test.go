@@ -31,3 +31,21 @@ func TestPluralRulesByTag(t *testing.T) {
 	requireEqual(t, cldr.Rules{Other: true, One: true, Many: true}, p.Cardinal)
 	requireEqual(t, cldr.Rules{Other: true, One: true}, p.Ordinal)
 }
+
+func TestCardinalSamplesByTag(t *testing.T) {
+	s := cldr.CardinalSamplesByTag[language.Polish]
+	requireEqual(t, 1, len(s.One))
+	requireEqual(t, uint64(1), s.One[0])
+	// Ranges are expanded: 2~4, 22~24, ...
+	requireEqual(t, uint64(22), s.Few[3])
+	// Polish "other" has decimal samples only.
+	requireEqual(t, 0, len(s.Other))
+
+	// Samples in compact notation (1c6) are skipped.
+	s = cldr.CardinalSamplesByTag[language.French]
+	requireEqual(t, 1, len(s.Many))
+	requireEqual(t, uint64(1000000), s.Many[0])
+
+	base, _ := language.German.Base()
+	requireEqual(t, 1, len(cldr.CardinalSamplesByBase[base].One))
+}
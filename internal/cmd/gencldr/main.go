@@ -11,6 +11,8 @@ import (
 	"maps"
 	"os"
 	"slices"
+	"strconv"
+	"strings"
 
 	"golang.org/x/text/language"
 )
@@ -121,6 +123,9 @@ func write(
 
 	writef("import \"golang.org/x/text/language\"\n")
 
+	writef("// CLDRVersion is the version of CLDR the rules are generated from.\n")
+	writef("const CLDRVersion = %q\n\n", cardinals.Supplemental.Version.CLDRVersion)
+
 	writef("// Rules defines supported CLDR plural rules.\n")
 	writef("type Rules struct { Zero, One, Two, Few, Many, Other bool }\n\n")
 
@@ -205,13 +210,88 @@ func write(
 		writef("}, %t)\n", isBase)
 	}
 	writef("}\n\n")
+
+	writef("// Samples defines integer samples of CLDR plural categories.\n")
+	writef("type Samples struct { Zero, One, Two, Few, Many, Other []uint64 }\n\n")
+
+	writef("// CardinalSamplesByTag maps language tags to integer samples\n" +
+		"// of cardinal plural categories.\n")
+	writef("var CardinalSamplesByTag = make(map[language.Tag]Samples, %d)\n",
+		len(cardinalsKeys))
+	writef("// CardinalSamplesByBase maps base languages to integer samples\n" +
+		"// of cardinal plural categories.\n")
+	writef("var CardinalSamplesByBase = make(map[language.Base]Samples, %d)\n",
+		len(cardinalsKeys))
+
+	writef("func init () {\n")
+	writef("register := func(s string, samples Samples, isBase bool) {\n")
+	writef("l, err := language.Parse(s)\n")
+	writef("if err != nil { panic(err) }\n")
+	writef("CardinalSamplesByTag[l] = samples\n")
+	writef("if isBase {\n")
+	writef("\tbase, _ := l.Base()\n")
+	writef("\tCardinalSamplesByBase[base] = samples")
+	writef("}")
+	writef("}\n")
 	for _, k := range cardinalsKeys {
 		rules := cardinals.Supplemental.PluralsTypeCardinals[k]
-		l, err := language.Parse(k)
+		base, _ := language.MustParse(k).Base()
+		isBase := base.String() == k
+
+		writef("register(%q, Samples{", k)
+		for _, c := range [...]struct{ name, rule string }{
+			{"Zero", rules.Zero},
+			{"One", rules.One},
+			{"Two", rules.Two},
+			{"Few", rules.Few},
+			{"Many", rules.Many},
+			{"Other", rules.Other},
+		} {
+			samples := integerSamples(c.rule)
+			if len(samples) == 0 {
+				continue
+			}
+			writef("\n%s: []uint64{", c.name)
+			for i, n := range samples {
+				if i > 0 {
+					writef(", ")
+				}
+				writef("%d", n)
+			}
+			writef("},")
+		}
+		writef("}, %t)\n", isBase)
+	}
+	writef("}\n")
+}
+
+// integerSamples returns the integer samples of a CLDR plural rule
+// such as "... @integer 0, 5~7, 100, 1c6, … @decimal ..." expanding ranges.
+// Samples in compact notation (1c6) and the ellipsis are skipped.
+func integerSamples(rule string) []uint64 {
+	_, s, ok := strings.Cut(rule, "@integer")
+	if !ok {
+		return nil
+	}
+	s, _, _ = strings.Cut(s, "@")
+	var samples []uint64
+	for sample := range strings.SplitSeq(s, ",") {
+		sample = strings.TrimSpace(sample)
+		from, to, isRange := strings.Cut(sample, "~")
+		if !isRange {
+			to = from
+		}
+		first, err := strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			continue // Compact notation or ellipsis.
+		}
+		last, err := strconv.ParseUint(to, 10, 64)
 		if err != nil {
 			panic(err)
 		}
-		_ = rules
-		_ = l
+		for n := first; n <= last; n++ {
+			samples = append(samples, n)
+		}
 	}
+	return samples
 }
@@ -0,0 +1,509 @@
+// Package msgfile parses ICU message files and JSON message catalogs
+// keeping track of the position of each message in the file.
+package msgfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// File is a parsed message file.
+type File struct {
+	Name     string
+	Locale   language.Tag
+	Data     string
+	Messages []Message
+
+	lineStarts []int
+}
+
+// Message is a single ICU message in a file.
+type Message struct {
+	// ID is the message key in JSON catalogs and empty for single-message files.
+	ID string
+
+	// Text is the decoded ICU message.
+	Text string
+
+	// Comment is the "description" of the message metadata
+	// ("@" followed by the message key) in JSON catalogs.
+	Comment string
+
+	// Offset is the byte offset of the first byte of Text in the file.
+	Offset int
+
+	// offsets maps byte offsets in Text to byte offsets in the file
+	// and is nil if Text is stored in the file as is.
+	offsets []int
+}
+
+// FileOffset returns the byte offset in the file of byte offset i in m.Text.
+func (m *Message) FileOffset(i int) int {
+	if m.offsets == nil {
+		return m.Offset + i
+	}
+	if i >= len(m.offsets) {
+		return m.offsets[len(m.offsets)-1]
+	}
+	return m.offsets[i]
+}
+
+// TextOffset returns the byte offset in m.Text of byte offset i in the file.
+// Offsets inside of escape sequences map to the escaped character.
+// Returns false if i is outside of m.
+func (m *Message) TextOffset(i int) (int, bool) {
+	if i < m.FileOffset(0) || i > m.FileOffset(len(m.Text)) {
+		return 0, false
+	}
+	if m.offsets == nil {
+		return i - m.Offset, true
+	}
+	// Find the last text offset mapped to a file offset <= i.
+	n, _ := slices.BinarySearch(m.offsets, i+1)
+	return min(n-1, len(m.Text)), true
+}
+
+// Position is a position in a file.
+// Line and Column are 1-based, Column is counted in Unicode code points.
+type Position struct {
+	Offset, Line, Column int
+}
+
+func (p Position) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Column) }
+
+// Position returns the position of byte offset in f.
+func (f *File) Position(offset int) Position {
+	offset = min(max(offset, 0), len(f.Data))
+	line, _ := slices.BinarySearch(f.lineStarts, offset+1)
+	lineStart := f.lineStarts[line-1]
+	return Position{
+		Offset: offset,
+		Line:   line,
+		Column: utf8.RuneCountInString(f.Data[lineStart:offset]) + 1,
+	}
+}
+
+// UTF16Column returns the 0-based column of byte offset in f
+// counted in UTF-16 code units.
+func (f *File) UTF16Column(offset int) int {
+	p := f.Position(offset)
+	lineStart := f.lineStarts[p.Line-1]
+	n := 0
+	for _, r := range f.Data[lineStart:p.Offset] {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// OffsetOf returns the byte offset in f of the 0-based line
+// and the 0-based UTF-16 column.
+func (f *File) OffsetOf(line, utf16Column int) int {
+	if line < 0 {
+		return 0
+	}
+	if line >= len(f.lineStarts) {
+		return len(f.Data)
+	}
+	offset := f.lineStarts[line]
+	for utf16Column > 0 && offset < len(f.Data) && f.Data[offset] != '\n' {
+		r, size := utf8.DecodeRuneInString(f.Data[offset:])
+		utf16Column -= utf16.RuneLen(r)
+		offset += size
+	}
+	return offset
+}
+
+var (
+	ErrUnknownLocale = errors.New("unable to determine locale")
+	ErrSyntax        = errors.New("syntax error")
+)
+
+// Error is a syntax error in a JSON catalog.
+type Error struct {
+	File string
+	Pos  Position
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%s: %v: %s", e.File, e.Pos, ErrSyntax, e.Msg)
+}
+
+func (e *Error) Unwrap() error { return ErrSyntax }
+
+// IsCatalog returns true if fileName is a JSON catalog.
+func IsCatalog(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json", ".arb":
+		return true
+	}
+	return false
+}
+
+// LocaleFromFileName determines the locale from file names such as
+// "de-AT.json", "messages.de-AT.json", "app_de.arb" or "welcome.fr.icu".
+func LocaleFromFileName(fileName string) (language.Tag, bool) {
+	name := filepath.Base(fileName)
+	for {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".json" && ext != ".arb" && ext != ".icu" && ext != ".txt" {
+			break
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	candidates := []string{name}
+	if i := strings.LastIndexAny(name, "._"); i != -1 {
+		candidates = append(candidates, name[i+1:])
+	}
+	for _, c := range slices.Backward(candidates) {
+		if c == "" {
+			continue
+		}
+		if t, err := language.Parse(c); err == nil {
+			return t, true
+		}
+	}
+	return language.Und, false
+}
+
+// Parse parses a message file. JSON catalogs (.json and .arb) are objects
+// mapping message IDs to ICU messages, nested objects are flattened with
+// their keys joined by ".", keys starting with "@" are ignored except
+// "@@locale" which defines the locale of the catalog and
+// ARB-style metadata objects providing the message comment:
+//
+//	"greeting": "Hello {name}!",
+//	"@greeting": {"description": "Greeting on the start page"}
+//
+// Any other file is a single ICU message.
+// If locale is language.Und then it's determined from the catalog or
+// the file name.
+func Parse(name string, data []byte, locale language.Tag) (*File, error) {
+	f := &File{Name: name, Locale: locale, Data: string(data)}
+	f.lineStarts = append(f.lineStarts, 0)
+	for i := range len(f.Data) {
+		if f.Data[i] == '\n' {
+			f.lineStarts = append(f.lineStarts, i+1)
+		}
+	}
+
+	if IsCatalog(name) {
+		p := parser{f: f}
+		if err := p.parseCatalog(); err != nil {
+			return nil, err
+		}
+		for i := range f.Messages {
+			f.Messages[i].Comment = p.comments[f.Messages[i].ID]
+		}
+	} else {
+		f.Messages = []Message{{Text: f.Data}}
+	}
+
+	if f.Locale == language.Und {
+		var ok bool
+		if f.Locale, ok = LocaleFromFileName(name); !ok {
+			return nil, fmt.Errorf("%s: %w", name, ErrUnknownLocale)
+		}
+	}
+	return f, nil
+}
+
+type parser struct {
+	f   *File
+	pos int
+
+	// comments maps message IDs to the descriptions of their metadata.
+	comments map[string]string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &Error{
+		File: p.f.Name,
+		Pos:  p.f.Position(p.pos),
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *parser) skipWhitespace() {
+	for p.pos < len(p.f.Data) {
+		switch p.f.Data[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(b byte) error {
+	p.skipWhitespace()
+	if p.pos >= len(p.f.Data) {
+		return p.errorf("unexpected EOF, expected %q", b)
+	}
+	if p.f.Data[p.pos] != b {
+		return p.errorf("expected %q", b)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) parseCatalog() error {
+	if strings.HasPrefix(p.f.Data, "\uFEFF") {
+		p.pos += len("\uFEFF") // Skip BOM.
+	}
+	if err := p.parseObject(""); err != nil {
+		return err
+	}
+	p.skipWhitespace()
+	if p.pos < len(p.f.Data) {
+		return p.errorf("unexpected data after catalog object")
+	}
+	return nil
+}
+
+func (p *parser) parseObject(prefix string) error {
+	if err := p.expect('{'); err != nil {
+		return err
+	}
+	p.skipWhitespace()
+	if p.pos < len(p.f.Data) && p.f.Data[p.pos] == '}' {
+		p.pos++
+		return nil
+	}
+	for {
+		p.skipWhitespace()
+		key, _, _, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(':'); err != nil {
+			return err
+		}
+		p.skipWhitespace()
+		if p.pos >= len(p.f.Data) {
+			return p.errorf("unexpected EOF, expected value")
+		}
+		switch {
+		case p.f.Data[p.pos] == '"':
+			text, start, offsets, err := p.parseString()
+			if err != nil {
+				return err
+			}
+			switch {
+			case key == "@@locale" && prefix == "":
+				if p.f.Locale == language.Und {
+					if p.f.Locale, err = language.Parse(text); err != nil {
+						return p.errorf("invalid @@locale %q: %v", text, err)
+					}
+				}
+			case strings.HasPrefix(key, "@"):
+				// Ignore metadata.
+			default:
+				p.f.Messages = append(p.f.Messages, Message{
+					ID: prefix + key, Text: text, Offset: start, offsets: offsets,
+				})
+			}
+		case p.f.Data[p.pos] == '{' && !strings.HasPrefix(key, "@"):
+			if err := p.parseObject(prefix + key + "."); err != nil {
+				return err
+			}
+		case p.f.Data[p.pos] == '{' && !strings.HasPrefix(key, "@@"):
+			if err := p.parseMetadata(prefix + key[1:]); err != nil {
+				return err
+			}
+		default:
+			if err := p.skipValue(); err != nil {
+				return err
+			}
+		}
+		p.skipWhitespace()
+		if p.pos >= len(p.f.Data) {
+			return p.errorf("unexpected EOF, expected ',' or '}'")
+		}
+		if p.f.Data[p.pos] == '}' {
+			p.pos++
+			return nil
+		}
+		if err := p.expect(','); err != nil {
+			return err
+		}
+	}
+}
+
+// parseMetadata parses the metadata object of message id.
+func (p *parser) parseMetadata(id string) error {
+	start := p.pos
+	if err := p.skipValue(); err != nil {
+		return err
+	}
+	var meta struct {
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(p.f.Data[start:p.pos]), &meta); err != nil {
+		// Only the description is of interest, tolerate anything else.
+		return nil
+	}
+	if meta.Description != "" {
+		if p.comments == nil {
+			p.comments = map[string]string{}
+		}
+		p.comments[id] = meta.Description
+	}
+	return nil
+}
+
+// skipValue skips any JSON value.
+func (p *parser) skipValue() error {
+	p.skipWhitespace()
+	if p.pos >= len(p.f.Data) {
+		return p.errorf("unexpected EOF, expected value")
+	}
+	switch p.f.Data[p.pos] {
+	case '"':
+		_, _, _, err := p.parseString()
+		return err
+	case '{', '[':
+		depth := 0
+		for p.pos < len(p.f.Data) {
+			switch p.f.Data[p.pos] {
+			case '"':
+				if _, _, _, err := p.parseString(); err != nil {
+					return err
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			p.pos++
+			if depth == 0 {
+				return nil
+			}
+		}
+		return p.errorf("unexpected EOF in value")
+	}
+	start := p.pos
+	for p.pos < len(p.f.Data) {
+		switch p.f.Data[p.pos] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			if start == p.pos {
+				return p.errorf("expected value")
+			}
+			return nil
+		}
+		p.pos++
+	}
+	return p.errorf("unexpected EOF in value")
+}
+
+// parseString parses a JSON string and returns its decoded value,
+// the file offset of its first byte and, if the string contains escape sequences,
+// the file offsets of every decoded byte.
+func (p *parser) parseString() (s string, start int, offsets []int, err error) {
+	if p.pos >= len(p.f.Data) || p.f.Data[p.pos] != '"' {
+		return "", 0, nil, p.errorf("expected string")
+	}
+	p.pos++ // Consume the opening quote.
+	start = p.pos
+	var b strings.Builder
+	escaped := false
+	for p.pos < len(p.f.Data) {
+		c := p.f.Data[p.pos]
+		switch {
+		case c == '"':
+			p.pos++ // Consume the closing quote.
+			if !escaped {
+				return p.f.Data[start : p.pos-1], start, nil, nil
+			}
+			offsets = append(offsets, p.pos-1)
+			return b.String(), start, offsets, nil
+		case c == '\\':
+			if !escaped {
+				escaped = true
+				b.WriteString(p.f.Data[start:p.pos])
+				for i := start; i < p.pos; i++ {
+					offsets = append(offsets, i)
+				}
+			}
+			escStart := p.pos
+			r, err := p.parseEscape()
+			if err != nil {
+				return "", 0, nil, err
+			}
+			n := b.Len()
+			b.WriteRune(r)
+			for range b.Len() - n {
+				offsets = append(offsets, escStart)
+			}
+		case c < 0x20:
+			return "", 0, nil, p.errorf("control character in string")
+		default:
+			if escaped {
+				b.WriteByte(c)
+				offsets = append(offsets, p.pos)
+			}
+			p.pos++
+		}
+	}
+	return "", 0, nil, p.errorf("unexpected EOF in string")
+}
+
+func (p *parser) parseEscape() (rune, error) {
+	p.pos++ // Consume the backslash.
+	if p.pos >= len(p.f.Data) {
+		return 0, p.errorf("unexpected EOF in escape sequence")
+	}
+	c := p.f.Data[p.pos]
+	p.pos++
+	switch c {
+	case '"', '\\', '/':
+		return rune(c), nil
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case 'u':
+		r, err := p.parseHex4()
+		if err != nil {
+			return 0, err
+		}
+		if utf16.IsSurrogate(r) && strings.HasPrefix(p.f.Data[p.pos:], `\u`) {
+			p.pos += 2
+			r2, err := p.parseHex4()
+			if err != nil {
+				return 0, err
+			}
+			return utf16.DecodeRune(r, r2), nil
+		}
+		return r, nil
+	}
+	return 0, p.errorf("invalid escape sequence")
+}
+
+func (p *parser) parseHex4() (rune, error) {
+	if p.pos+4 > len(p.f.Data) {
+		return 0, p.errorf("unexpected EOF in escape sequence")
+	}
+	v, err := strconv.ParseUint(p.f.Data[p.pos:p.pos+4], 16, 16)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+	p.pos += 4
+	return rune(v), nil
+}
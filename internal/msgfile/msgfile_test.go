@@ -0,0 +1,114 @@
+package msgfile_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg/internal/msgfile"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestLocaleFromFileName(t *testing.T) {
+	f := func(t *testing.T, fileName string, expect language.Tag, expectOK bool) {
+		t.Helper()
+		actual, ok := msgfile.LocaleFromFileName(fileName)
+		test.RequireEqual(t, expectOK, ok)
+		test.RequireEqual(t, expect, actual)
+	}
+
+	f(t, "de.json", language.German, true)
+	f(t, "path/to/de-AT.json", language.MustParse("de-AT"), true)
+	f(t, "messages.de-AT.json", language.MustParse("de-AT"), true)
+	f(t, "app_uk.arb", language.Ukrainian, true)
+	f(t, "welcome.fr.icu", language.French, true)
+	f(t, "welcome.fr.icu.txt", language.French, true)
+	f(t, "messages.json", language.Und, false)
+}
+
+func TestParseMessageFile(t *testing.T) {
+	f, err := msgfile.Parse("welcome.en.icu",
+		[]byte("Hello\n{name}!"), language.Und)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.English, f.Locale)
+	test.RequireEqual(t, 1, len(f.Messages))
+	m := f.Messages[0]
+	test.RequireEqual(t, "", m.ID)
+	test.RequireEqual(t, "Hello\n{name}!", m.Text)
+	test.RequireEqual(t, msgfile.Position{Offset: 6, Line: 2, Column: 1},
+		f.Position(m.FileOffset(6)))
+
+	_, err = msgfile.Parse("welcome.icu", []byte("Hello"), language.Und)
+	test.RequireErrIs(t, msgfile.ErrUnknownLocale, err)
+
+	f, err = msgfile.Parse("welcome.icu", []byte("Hello"), language.German)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.German, f.Locale)
+}
+
+func TestParseCatalog(t *testing.T) {
+	const data = "{\n" +
+		`  "@@locale": "uk",` + "\n" +
+		`  "greeting": "Привіт {name}",` + "\n" +
+		`  "@greeting": {"description": "Hi", "placeholders": {"name": {}}},` + "\n" +
+		`  "nested": {"escaped": "a\"b\\nä{x}", "n": 42, "list": [1, "x", {}]}` + "\n" +
+		"}\n"
+	f, err := msgfile.Parse("messages.json", []byte(data), language.Und)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.Ukrainian, f.Locale)
+	test.RequireEqual(t, 2, len(f.Messages))
+
+	m := f.Messages[0]
+	test.RequireEqual(t, "greeting", m.ID)
+	test.RequireEqual(t, "Привіт {name}", m.Text)
+	test.RequireEqual(t, "Hi", m.Comment)
+	// Position of "{name}".
+	test.RequireEqual(t, msgfile.Position{Offset: 50, Line: 3, Column: 23},
+		f.Position(m.FileOffset(len("Привіт "))))
+	test.RequireEqual(t, 22, f.UTF16Column(m.FileOffset(len("Привіт "))))
+	test.RequireEqual(t, m.FileOffset(len("Привіт ")), f.OffsetOf(2, 22))
+
+	m = f.Messages[1]
+	test.RequireEqual(t, "nested.escaped", m.ID)
+	test.RequireEqual(t, "a\"b\\nä{x}", m.Text)
+	test.RequireEqual(t, "", m.Comment)
+	// Position of "{x}" after escape sequences.
+	test.RequireEqual(t, "{x}", f.Data[m.FileOffset(7):m.FileOffset(7)+3])
+	test.RequireEqual(t, `\"`, f.Data[m.FileOffset(1):m.FileOffset(1)+2])
+
+	// Offsets in the file map back to offsets in the text.
+	for i := range len(m.Text) + 1 {
+		actual, ok := m.TextOffset(m.FileOffset(i))
+		test.RequireEqual(t, true, ok)
+		if i > 0 && m.FileOffset(i) == m.FileOffset(i-1) {
+			continue // Bytes of an escaped multi-byte character.
+		}
+		test.RequireEqual(t, i, actual)
+	}
+	// The 'n' of "\\n" maps to the escaped backslash.
+	actual, _ := m.TextOffset(m.FileOffset(3) + 1)
+	test.RequireEqual(t, 3, actual)
+	_, ok := m.TextOffset(m.Offset - 2)
+	test.RequireEqual(t, false, ok)
+
+	// Explicit locale takes precedence.
+	f, err = msgfile.Parse("messages.json", []byte(data), language.German)
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, language.German, f.Locale)
+}
+
+func TestParseCatalogErr(t *testing.T) {
+	f := func(t *testing.T, data, expect string) {
+		t.Helper()
+		_, err := msgfile.Parse("en.json", []byte(data), language.Und)
+		test.RequireErrIs(t, msgfile.ErrSyntax, err)
+		test.RequireEqual(t, expect, err.Error())
+	}
+
+	f(t, ``, `en.json:1:1: syntax error: unexpected EOF, expected '{'`)
+	f(t, `[]`, `en.json:1:1: syntax error: expected '{'`)
+	f(t, `{"a" "b"}`, `en.json:1:6: syntax error: expected ':'`)
+	f(t, "{\n\"a\": \"b\n\"}", `en.json:2:8: syntax error: control character in string`)
+	f(t, `{"a": "\x"}`, `en.json:1:10: syntax error: invalid escape sequence`)
+	f(t, `{"a": "b"} x`, `en.json:1:12: syntax error: unexpected data after catalog object`)
+	f(t, `{"a": "b"`, `en.json:1:10: syntax error: unexpected EOF, expected ',' or '}'`)
+}
@@ -0,0 +1,281 @@
+// Package printf converts between ICU message contents and printf-style
+// format strings with positional specifiers such as "%1$d" as used by
+// native mobile resource formats.
+package printf
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/romshark/icumsg"
+)
+
+var (
+	ErrUnrepresentable      = errors.New("not representable")
+	ErrUnsupportedSpecifier = errors.New("unsupported format specifier")
+)
+
+// Verbs are the conversion verbs of string and number arguments,
+// such as "s" and "d" on Android or "@" and "lld" on Apple platforms.
+type Verbs struct{ String, Number string }
+
+// Encoder converts ICU message contents to format strings.
+// Argument positions are assigned in the order of first appearance
+// and are shared across all calls of Encode.
+type Encoder struct {
+	Verbs Verbs
+
+	// Args are the argument names by position, Args[0] is "%1$".
+	Args []string
+
+	// Indexed writes specifiers with explicit argument indexes
+	// of package fmt such as "%[1]d" instead of "%1$d".
+	Indexed bool
+}
+
+// specifier returns the specifier of the argument at position.
+func (e *Encoder) specifier(position int, verb string) string {
+	if e.Indexed {
+		return fmt.Sprintf("%%[%d]%s", position, verb)
+	}
+	return fmt.Sprintf("%%%d$%s", position, verb)
+}
+
+// Position returns the position of argument name assigning
+// the next one if the argument has none yet.
+func (e *Encoder) Position(name string) int {
+	for i, a := range e.Args {
+		if a == name {
+			return i + 1
+		}
+	}
+	e.Args = append(e.Args, name)
+	return len(e.Args)
+}
+
+// Encode writes buffer[start:end] of the ICU message src to b.
+// Literals are unescaped with '%' doubled and simple arguments without
+// type become string specifiers while number arguments without style or
+// of style integer become number specifiers. In plural options, hash is
+// the position of the plural argument "#" refers to and 0 otherwise.
+// plural is called for plural arguments and may be nil.
+// Any other argument is ErrUnrepresentable.
+func (e *Encoder) Encode(
+	b *strings.Builder, src string, buffer []icumsg.Token, start, end, hash int,
+	plural func(index int) error,
+) error {
+	for i := start; i < end; i++ {
+		t := buffer[i]
+		switch t.Type {
+		case icumsg.TokenTypeLiteral:
+			e.literal(b, src[t.IndexStart:t.IndexEnd], hash)
+		case icumsg.TokenTypeSimpleArg:
+			name := buffer[i+1].String(src, buffer)
+			verb := e.Verbs.String
+			if a := argument(src, buffer, i); a.Type != icumsg.TokenTypeSimpleArg {
+				if a.Type != icumsg.TokenTypeArgTypeNumber ||
+					a.Style != 0 && a.Style != icumsg.TokenTypeArgStyleInteger {
+					return fmt.Errorf("%w: %s", ErrUnrepresentable, t.String(src, buffer))
+				}
+				verb = e.Verbs.Number
+			}
+			b.WriteString(e.specifier(e.Position(name), verb))
+			// Skip the argument name, type and style.
+			i = icumsg.SimpleArgEnd(buffer, i)
+		case icumsg.TokenTypePlural:
+			if plural == nil {
+				return fmt.Errorf("%w: nested %s %q", ErrUnrepresentable,
+					t.Type, buffer[i+1].String(src, buffer))
+			}
+			if err := plural(i); err != nil {
+				return err
+			}
+			i = t.IndexEnd
+		case icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+			return fmt.Errorf("%w: %s %q", ErrUnrepresentable,
+				t.Type, buffer[i+1].String(src, buffer))
+		}
+	}
+	return nil
+}
+
+// argument returns the simple argument at buffer[index].
+func argument(src string, buffer []icumsg.Token, index int) icumsg.Argument {
+	for a := range icumsg.Arguments(src, buffer[index:]) {
+		return a
+	}
+	return icumsg.Argument{}
+}
+
+// literal writes the unescaped literal l with '%' doubled.
+func (e *Encoder) literal(b *strings.Builder, l string, hash int) {
+	for text, isHash := range icumsg.LiteralSegments(l, hash != 0) {
+		b.WriteString(strings.ReplaceAll(text, "%", "%%"))
+		if isHash {
+			b.WriteString(e.specifier(hash, e.Verbs.Number))
+		}
+	}
+}
+
+// Segment is text or a specifier of a format string.
+type Segment struct {
+	// Text is the source of the segment, such as "files: ", "%%" or "%1$ld".
+	Text string
+
+	// Spec is the specifier without '%' and position, such as "d", "ld",
+	// "s", "@" or "#@name@". Spec is empty for text.
+	Spec string
+
+	// Position is the explicit position of the specifier or 0 if it has none.
+	Position int
+}
+
+// IsNumber returns true if the segment is a number specifier
+// (d, i and u with any length modifier).
+func (s Segment) IsNumber() bool {
+	return s.Spec != "" && strings.IndexByte("diu", s.Spec[len(s.Spec)-1]) != -1
+}
+
+// Segments returns an iterator over the text and specifiers of the
+// format string s. Escaped percent signs are yielded as the text "%%".
+// Unsupported specifiers are yielded as the text "%" with an error
+// wrapping ErrUnsupportedSpecifier, iteration continues after the '%'.
+func Segments(s string) iter.Seq2[Segment, error] {
+	return func(yield func(Segment, error) bool) {
+		for s != "" {
+			i := strings.IndexByte(s, '%')
+			switch {
+			case i == -1:
+				yield(Segment{Text: s}, nil)
+				return
+			case i > 0:
+				if !yield(Segment{Text: s[:i]}, nil) {
+					return
+				}
+				s = s[i:]
+				continue
+			case strings.HasPrefix(s, "%%"):
+				if !yield(Segment{Text: "%%"}, nil) {
+					return
+				}
+				s = s[2:]
+				continue
+			}
+			spec, rest, position, ok := parseSpecifier(s[1:])
+			if !ok {
+				err := fmt.Errorf("%w: %%%s", ErrUnsupportedSpecifier, clip(s[1:]))
+				if !yield(Segment{Text: "%"}, err) {
+					return
+				}
+				s = s[1:]
+				continue
+			}
+			seg := Segment{Text: s[:len(s)-len(rest)], Spec: spec, Position: position}
+			if !yield(seg, nil) {
+				return
+			}
+			s = rest
+		}
+	}
+}
+
+// Decode converts the format string s to ICU message contents.
+// Text is escaped (see icumsg.EscapeLiteral) and specifiers of numbers
+// (d, i and u with any length modifier) become number arguments while
+// specifiers of strings (s and @) become simple arguments named by
+// their position in args or "arg1", "arg2", ... if args has no name.
+// Non-positional specifiers are numbered sequentially.
+// Number specifiers of position hash (non-zero in plural forms) become "#".
+// Apple plural variable references ("%#@name@" and "%1$#@name@") are
+// replaced by the result of variable and are ErrUnsupportedSpecifier
+// if variable is nil. Any other specifier is ErrUnsupportedSpecifier.
+func Decode(
+	s string, args []string, hash int,
+	variable func(position int, name string) (string, error),
+) (string, error) {
+	var b strings.Builder
+	next := 1 // The position of the next non-positional specifier.
+	for seg, err := range Segments(s) {
+		if err != nil {
+			return "", err
+		}
+		if seg.Spec == "" {
+			if seg.Text == "%%" {
+				b.WriteByte('%')
+			} else {
+				b.WriteString(icumsg.EscapeLiteral(seg.Text))
+			}
+			continue
+		}
+		position := seg.Position
+		if position == 0 {
+			position = next
+			next++
+		}
+		switch {
+		case strings.HasPrefix(seg.Spec, "#@"):
+			if variable == nil {
+				return "", fmt.Errorf("%w: %%%s", ErrUnsupportedSpecifier, seg.Spec)
+			}
+			v, err := variable(position, strings.TrimSuffix(seg.Spec[2:], "@"))
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		case !seg.IsNumber():
+			b.WriteString("{" + argName(args, position) + "}")
+		case position == hash:
+			b.WriteByte('#')
+		default:
+			b.WriteString("{" + argName(args, position) + ", number}")
+		}
+	}
+	return b.String(), nil
+}
+
+// parseSpecifier parses the specifier at the start of s following '%'
+// and returns the specifier without position and the rest of s.
+func parseSpecifier(s string) (spec, rest string, position int, ok bool) {
+	if i := strings.IndexByte(s, '$'); i > 0 {
+		if n, err := strconv.Atoi(s[:i]); err == nil && n > 0 {
+			position, s = n, s[i+1:]
+		}
+	}
+	if strings.HasPrefix(s, "#@") {
+		end := strings.IndexByte(s[2:], '@')
+		if end < 1 {
+			return "", "", 0, false
+		}
+		return s[:end+3], s[end+3:], position, true
+	}
+	i := 0
+	for i < len(s) && strings.IndexByte("hlqzjt", s[i]) != -1 {
+		i++ // Length modifiers.
+	}
+	if i >= len(s) || strings.IndexByte("diu@s", s[i]) == -1 ||
+		(s[i] == '@' || s[i] == 's') && i > 0 {
+		return "", "", 0, false
+	}
+	if s[i] == '@' || s[i] == 's' {
+		return s[i : i+1], s[i+1:], position, true
+	}
+	return s[:i+1], s[i+1:], position, true
+}
+
+func argName(args []string, position int) string {
+	if position <= len(args) && args[position-1] != "" {
+		return args[position-1]
+	}
+	return "arg" + strconv.Itoa(position)
+}
+
+// clip returns the beginning of s for error messages.
+func clip(s string) string {
+	if len(s) > 8 {
+		return s[:8] + "..."
+	}
+	return s
+}
@@ -0,0 +1,133 @@
+package printf_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/printf"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestEncode(t *testing.T) {
+	f := func(t *testing.T, src, expect string, expectArgs ...string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		e := printf.Encoder{Verbs: printf.Verbs{String: "s", Number: "d"}}
+		var b strings.Builder
+		test.RequireNoErr(t, e.Encode(&b, src, buffer, 0, len(buffer), 0, nil))
+		test.RequireEqual(t, expect, b.String())
+		test.RequireDeepEqual(t, expectArgs, e.Args)
+	}
+
+	f(t, "Plain", "Plain")
+	f(t, "It''s '{'100%'}' #", "It's {100%%} #")
+	f(t, "{a} {b, number} {a} {c, number, integer}", "%1$s %2$d %1$s %3$d", "a", "b", "c")
+}
+
+func TestEncodeIndexed(t *testing.T) {
+	src := "{a} {n, plural, other {# {b, number}}}"
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(language.English, nil, src)
+	test.RequireNoErr(t, err)
+	e := printf.Encoder{Verbs: printf.Verbs{String: "v", Number: "d"}, Indexed: true}
+	var b strings.Builder
+	test.RequireNoErr(t, e.Encode(&b, src, buffer, 0, len(buffer), 0, func(i int) error {
+		return e.Encode(&b, src, buffer, i+3, buffer[i+2].IndexEnd, e.Position("n"), nil)
+	}))
+	test.RequireEqual(t, "%[1]v %[2]d %[3]d", b.String())
+}
+
+func TestEncodeErr(t *testing.T) {
+	f := func(t *testing.T, src string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		var e printf.Encoder
+		var b strings.Builder
+		err = e.Encode(&b, src, buffer, 0, len(buffer), 0, nil)
+		test.RequireErrIs(t, printf.ErrUnrepresentable, err)
+	}
+
+	f(t, "{d, date}")
+	f(t, "{n, number, percent}")
+	f(t, "{n, plural, other {#}}")
+	f(t, "{g, select, other {x}}")
+	f(t, "{n, selectordinal, other {#}}")
+}
+
+func TestDecode(t *testing.T) {
+	f := func(t *testing.T, s string, args []string, hash int, expect string) {
+		t.Helper()
+		actual, err := printf.Decode(s, args, hash, func(pos int, name string) (string, error) {
+			return "<" + name + ">", nil
+		})
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, actual)
+	}
+
+	f(t, "It's {100%%}", nil, 0, "It''s '{'100%'}'")
+	f(t, "%1$s has %2$d", []string{"name", "n"}, 0, "{name} has {n, number}")
+	f(t, "%s has %lld", []string{"name"}, 0, "{name} has {arg2, number}")
+	f(t, "%1$d file and %2$@", []string{"n", "x"}, 1, "# file and {x}")
+	f(t, "%d $ %i", nil, 1, "# $ {arg2, number}")
+	f(t, "%#@files@ in %2$#@dirs@", nil, 0, "<files> in <dirs>")
+}
+
+func TestDecodeErr(t *testing.T) {
+	f := func(t *testing.T, s string) {
+		t.Helper()
+		_, err := printf.Decode(s, nil, 0, nil)
+		test.RequireErrIs(t, printf.ErrUnsupportedSpecifier, err)
+	}
+
+	f(t, "%")
+	f(t, "%f")
+	f(t, "%.2f")
+	f(t, "%1$x")
+	f(t, "%ls")
+	f(t, "%#@files@")
+	f(t, "%#@@")
+}
+
+func TestSegments(t *testing.T) {
+	type segment struct {
+		printf.Segment
+		Err bool
+	}
+	f := func(t *testing.T, s string, expect ...segment) {
+		t.Helper()
+		var actual []segment
+		for seg, err := range printf.Segments(s) {
+			if err != nil {
+				test.RequireErrIs(t, printf.ErrUnsupportedSpecifier, err)
+			}
+			actual = append(actual, segment{seg, err != nil})
+		}
+		test.RequireDeepEqual(t, expect, actual)
+	}
+	text := func(s string) segment { return segment{Segment: printf.Segment{Text: s}} }
+	spec := func(s, spec string, position int) segment {
+		return segment{Segment: printf.Segment{Text: s, Spec: spec, Position: position}}
+	}
+
+	f(t, "")
+	f(t, "plain", text("plain"))
+	f(t, "%d files", spec("%d", "d", 0), text(" files"))
+	f(t, "%2$ld and %1$@", spec("%2$ld", "ld", 2), text(" and "), spec("%1$@", "@", 1))
+	f(t, "100%%d", text("100"), text("%%"), text("d"))
+	f(t, "%f %i", segment{Segment: printf.Segment{Text: "%"}, Err: true},
+		text("f "), spec("%i", "i", 0))
+	f(t, "%#@files@", spec("%#@files@", "#@files@", 0))
+
+	for _, n := range []string{"d", "i", "u", "ld", "lld", "hhu"} {
+		test.RequireEqual(t, true, printf.Segment{Spec: n}.IsNumber())
+	}
+	for _, n := range []string{"", "s", "@", "#@files@"} {
+		test.RequireEqual(t, false, printf.Segment{Spec: n}.IsNumber())
+	}
+}
@@ -0,0 +1,96 @@
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Config configures the severity and options of rules.
+//
+// Example of a JSON configuration file:
+//
+//	{
+//	  "rules": {
+//	    "arg-name-case": {"severity": "error", "options": {"style": "snake"}},
+//	    "prefer-one": {"severity": "off"},
+//	    "select-required-keys": {
+//	      "options": {"keys": {"gender": ["male", "female"]}}
+//	    }
+//	  }
+//	}
+type Config struct {
+	Rules map[string]RuleConfig `json:"rules"`
+}
+
+// RuleConfig configures a single rule.
+type RuleConfig struct {
+	// Severity overrides the default severity of the rule if set.
+	Severity *Severity `json:"severity,omitempty"`
+
+	// Options are passed to the rule if it implements Configurable.
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// Configurable is implemented by rules that accept options.
+type Configurable interface {
+	// Configure applies the JSON encoded options.
+	Configure(options json.RawMessage) error
+}
+
+var (
+	ErrUnknownRule         = errors.New("unknown rule")
+	ErrRuleNotConfigurable = errors.New("rule has no options")
+)
+
+// ReadConfig reads a JSON configuration from r.
+func ReadConfig(r io.Reader) (Config, error) {
+	var c Config
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&c); err != nil {
+		return Config{}, fmt.Errorf("decoding config: %w", err)
+	}
+	return c, nil
+}
+
+// LoadConfig reads a JSON configuration file.
+func LoadConfig(fileName string) (Config, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return Config{}, err
+	}
+	defer func() { _ = f.Close() }()
+	return ReadConfig(f)
+}
+
+func (c Config) apply(l *Linter) error {
+	for id, rc := range c.Rules {
+		var rule Rule
+		for _, r := range l.rules {
+			if r.ID() == id {
+				rule = r
+				break
+			}
+		}
+		if rule == nil {
+			return fmt.Errorf("%w: %q", ErrUnknownRule, id)
+		}
+		if rc.Severity != nil {
+			l.severities[id] = *rc.Severity
+		}
+		if len(rc.Options) == 0 {
+			continue
+		}
+		cr, ok := rule.(Configurable)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrRuleNotConfigurable, id)
+		}
+		if err := cr.Configure(rc.Options); err != nil {
+			return fmt.Errorf("configuring rule %q: %w", id, err)
+		}
+	}
+	return nil
+}
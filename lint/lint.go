@@ -0,0 +1,221 @@
+// Package lint provides a pluggable framework of lint rules
+// for tokenized ICU messages.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"golang.org/x/text/language"
+)
+
+// Severity defines how severe a diagnostic is.
+type Severity int8
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+var ErrUnknownSeverity = errors.New("unknown severity")
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// ParseSeverity parses "off", "info", "warning" or "error".
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "off":
+		return SeverityOff, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownSeverity, s)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(text []byte) (err error) {
+	*s, err = ParseSeverity(string(text))
+	return err
+}
+
+// Diagnostic is a problem found by a rule.
+type Diagnostic struct {
+	// Rule is the ID of the rule that reported the diagnostic.
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+
+	// Start and End are byte offsets in the message source.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	Message string `json:"message"`
+}
+
+// Message is a tokenized ICU message to lint.
+type Message struct {
+	// ID is the message ID. Optional.
+	ID     string
+	Locale language.Tag
+	Source string
+	Buffer []icumsg.Token
+
+	// Comment is an optional translator comment. Comments may contain
+	// suppression directives (see Suppressed).
+	Comment string
+}
+
+// Rule is a lint rule.
+type Rule interface {
+	// ID returns the unique ID of the rule, such as "arg-name-case".
+	ID() string
+
+	// Description returns a single-sentence description of the rule,
+	// capitalized and ending with a period.
+	Description() string
+
+	// DefaultSeverity returns the severity used unless configured otherwise.
+	DefaultSeverity() Severity
+
+	// Check checks the message of c and reports problems using c.Report.
+	Check(c *Context)
+}
+
+// Context is the context a rule is checking a message in.
+type Context struct {
+	Message
+
+	rule        Rule
+	severity    Severity
+	diagnostics []Diagnostic
+}
+
+// Report reports a problem in c.Source[start:end].
+func (c *Context) Report(start, end int, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Rule:     c.rule.ID(),
+		Severity: c.severity,
+		Start:    start,
+		End:      end,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// ReportToken reports a problem in token c.Buffer[index].
+func (c *Context) ReportToken(index int, format string, args ...any) {
+	t := c.Buffer[index]
+	s := t.String(c.Source, c.Buffer)
+	start := t.IndexStart
+	if t.Type > icumsg.TokenTypeOptionNumber {
+		start = c.Buffer[t.IndexStart].IndexStart // Terminator.
+	}
+	c.Report(start, start+len(s), format, args...)
+}
+
+// Linter runs a set of rules.
+type Linter struct {
+	rules      []Rule
+	severities map[string]Severity
+}
+
+// ErrDuplicateRule is returned by New if two rules have the same ID.
+var ErrDuplicateRule = errors.New("duplicate rule")
+
+// New creates a new linter running rules with the configured severities
+// and options. If rules is empty then DefaultRules are used.
+func New(config Config, rules ...Rule) (*Linter, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	l := &Linter{rules: rules, severities: make(map[string]Severity, len(rules))}
+	for _, r := range rules {
+		if _, ok := l.severities[r.ID()]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateRule, r.ID())
+		}
+		l.severities[r.ID()] = r.DefaultSeverity()
+	}
+	if err := config.apply(l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Rules returns all rules of the linter.
+func (l *Linter) Rules() []Rule { return slices.Clone(l.rules) }
+
+// Lint runs all enabled rules on m and returns all diagnostics
+// sorted by position. Rules suppressed by m.Comment aren't run.
+func (l *Linter) Lint(m Message) []Diagnostic {
+	suppressed := Suppressed(m.Comment)
+	c := &Context{Message: m}
+	for _, r := range l.rules {
+		sev := l.severities[r.ID()]
+		if sev == SeverityOff || isSuppressed(suppressed, r.ID()) {
+			continue
+		}
+		c.rule, c.severity = r, sev
+		r.Check(c)
+	}
+	slices.SortStableFunc(c.diagnostics, func(a, b Diagnostic) int {
+		return a.Start - b.Start
+	})
+	return c.diagnostics
+}
+
+// SuppressDirective is the directive suppressing rules in message comments.
+const SuppressDirective = "icumsg-lint-ignore"
+
+// Suppressed returns the IDs of the rules suppressed by directives in comment.
+// A directive "icumsg-lint-ignore" suppresses all rules, while
+// "icumsg-lint-ignore: rule-a, rule-b" suppresses only the listed rules,
+// in which case the list ends at the end of the line.
+// Returns nil if comment contains no directive and []string{"*"}
+// if all rules are suppressed.
+func Suppressed(comment string) []string {
+	var ids []string
+	for line := range strings.Lines(comment) {
+		i := strings.Index(line, SuppressDirective)
+		if i == -1 {
+			continue
+		}
+		rest := strings.TrimSpace(line[i+len(SuppressDirective):])
+		list, ok := strings.CutPrefix(rest, ":")
+		if !ok || strings.TrimSpace(list) == "" {
+			return []string{"*"}
+		}
+		for id := range strings.SplitSeq(list, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func isSuppressed(suppressed []string, id string) bool {
+	return slices.Contains(suppressed, "*") || slices.Contains(suppressed, id)
+}
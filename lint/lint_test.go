@@ -0,0 +1,123 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/lint"
+	"golang.org/x/text/language"
+)
+
+func message(t *testing.T, locale language.Tag, src string) lint.Message {
+	t.Helper()
+	var tok icumsg.Tokenizer
+	buffer, err := tok.Tokenize(locale, nil, src)
+	test.RequireNoErr(t, err)
+	return lint.Message{Locale: locale, Source: src, Buffer: buffer}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []lint.Severity{
+		lint.SeverityOff, lint.SeverityInfo, lint.SeverityWarning, lint.SeverityError,
+	} {
+		actual, err := lint.ParseSeverity(s.String())
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, s, actual)
+	}
+	_, err := lint.ParseSeverity("fatal")
+	test.RequireErrIs(t, lint.ErrUnknownSeverity, err)
+}
+
+func TestSuppressed(t *testing.T) {
+	f := func(t *testing.T, comment string, expect []string) {
+		t.Helper()
+		test.RequireDeepEqual(t, expect, lint.Suppressed(comment))
+	}
+
+	f(t, "", nil)
+	f(t, "Greeting on the start page", nil)
+	f(t, "icumsg-lint-ignore", []string{"*"})
+	f(t, "Greeting\nicumsg-lint-ignore:", []string{"*"})
+	f(t, "icumsg-lint-ignore: prefer-one", []string{"prefer-one"})
+	f(t, "Greeting icumsg-lint-ignore: prefer-one, arg-name-case\nicumsg-lint-ignore: x",
+		[]string{"prefer-one", "arg-name-case", "x"})
+}
+
+func TestLint(t *testing.T) {
+	l, err := lint.New(lint.Config{})
+	test.RequireNoErr(t, err)
+
+	m := message(t, language.English,
+		"{user_name} {n, plural, =1 {one file} other { # files}}")
+	test.RequireDeepEqual(t, []lint.Diagnostic{
+		{
+			Rule: "arg-name-case", Severity: lint.SeverityWarning,
+			Start: 1, End: 10,
+			Message: `argument name "user_name" is not camel case`,
+		},
+		{
+			Rule: "prefer-one", Severity: lint.SeverityWarning,
+			Start: 24, End: 26,
+			Message: `use "one" instead of "=1"`,
+		},
+		{
+			Rule: "option-whitespace", Severity: lint.SeverityWarning,
+			Start: 45, End: 53,
+			Message: `option "other" has leading or trailing whitespace`,
+		},
+	}, l.Lint(m))
+
+	m.Comment = "icumsg-lint-ignore: prefer-one,option-whitespace"
+	test.RequireEqual(t, 1, len(l.Lint(m)))
+	m.Comment = "icumsg-lint-ignore"
+	test.RequireEqual(t, 0, len(l.Lint(m)))
+}
+
+func TestConfig(t *testing.T) {
+	config, err := lint.ReadConfig(strings.NewReader(`{"rules": {
+		"arg-name-case": {"severity": "error", "options": {"style": "snake"}},
+		"prefer-one": {"severity": "off"},
+		"select-required-keys": {"options": {"keys": {"gender": ["male", "female"]}}}
+	}}`))
+	test.RequireNoErr(t, err)
+	l, err := lint.New(config)
+	test.RequireNoErr(t, err)
+
+	m := message(t, language.English,
+		"{userName} {gender, select, male {He} other {They}} {n, plural, =1 {a} other {b}}")
+	test.RequireDeepEqual(t, []lint.Diagnostic{
+		{
+			Rule: "arg-name-case", Severity: lint.SeverityError,
+			Start: 1, End: 9,
+			Message: `argument name "userName" is not snake case`,
+		},
+		{
+			Rule: "select-required-keys", Severity: lint.SeverityError,
+			Start: 11, End: 51,
+			Message: `select argument "gender" is missing required options: female`,
+		},
+	}, l.Lint(m))
+}
+
+func TestConfigErr(t *testing.T) {
+	f := func(t *testing.T, config string, expect error) {
+		t.Helper()
+		c, err := lint.ReadConfig(strings.NewReader(config))
+		if err == nil {
+			_, err = lint.New(c)
+		}
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, `{"rules": {"unknown": {}}}`, lint.ErrUnknownRule)
+	f(t, `{"rules": {"prefer-one": {"severity": "fatal"}}}`, lint.ErrUnknownSeverity)
+	f(t, `{"rules": {"option-whitespace": {"options": {"x": 1}}}}`,
+		lint.ErrRuleNotConfigurable)
+}
+
+func TestNewDuplicateRule(t *testing.T) {
+	_, err := lint.New(lint.Config{}, &lint.OptionWhitespace{}, &lint.OptionWhitespace{})
+	test.RequireErrIs(t, lint.ErrDuplicateRule, err)
+}
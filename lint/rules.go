@@ -0,0 +1,270 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"golang.org/x/text/language"
+)
+
+// DefaultRules returns new instances of all built-in rules
+// with default options.
+func DefaultRules() []Rule {
+	return []Rule{
+		&SelectRequiredKeys{},
+		&PreferOne{Locales: []string{"en"}},
+		&MaxPluralDepth{Max: 2},
+		&OptionWhitespace{},
+		&ArgNameCase{Style: CaseCamel},
+	}
+}
+
+func configure(options json.RawMessage, dst any) error {
+	d := json.NewDecoder(bytes.NewReader(options))
+	d.DisallowUnknownFields()
+	return d.Decode(dst)
+}
+
+// SelectRequiredKeys reports select arguments that lack any of
+// the options required for the argument name.
+//
+// Options:
+//
+//	{"keys": {"gender": ["male", "female"]}}
+type SelectRequiredKeys struct {
+	// Keys maps argument names to the option names they require.
+	Keys map[string][]string `json:"keys"`
+}
+
+var _ Configurable = new(SelectRequiredKeys)
+
+func (*SelectRequiredKeys) ID() string { return "select-required-keys" }
+
+func (*SelectRequiredKeys) Description() string {
+	return "Select arguments must have all options required for the argument."
+}
+
+func (*SelectRequiredKeys) DefaultSeverity() Severity { return SeverityError }
+
+func (r *SelectRequiredKeys) Configure(options json.RawMessage) error {
+	return configure(options, r)
+}
+
+func (r *SelectRequiredKeys) Check(c *Context) {
+	for i, t := range c.Buffer {
+		if t.Type != icumsg.TokenTypeSelect {
+			continue
+		}
+		name := c.Buffer[i+1].String(c.Source, c.Buffer)
+		required, ok := r.Keys[name]
+		if !ok {
+			continue
+		}
+		var present []string
+		for o := range icumsg.Options(c.Buffer, i) {
+			present = append(present, icumsg.OptionName(c.Source, c.Buffer, o))
+		}
+		var missing []string
+		for _, k := range required {
+			if !slices.Contains(present, k) {
+				missing = append(missing, k)
+			}
+		}
+		if len(missing) > 0 {
+			c.ReportToken(i, "select argument %q is missing required options: %s",
+				name, strings.Join(missing, ", "))
+		}
+	}
+}
+
+// PreferOne reports "=1" options of plural arguments without offset
+// in locales where the "one" category applies only to 1,
+// such as English, in which case "one" should be used instead.
+//
+// Options:
+//
+//	{"locales": ["en", "de"]}
+type PreferOne struct {
+	// Locales are the locales the rule applies to.
+	// Regional variants match their base language ("en" matches "en-US").
+	Locales []string `json:"locales"`
+}
+
+var _ Configurable = new(PreferOne)
+
+func (*PreferOne) ID() string { return "prefer-one" }
+
+func (*PreferOne) Description() string {
+	return `Plural option "=1" should be "one".`
+}
+
+func (*PreferOne) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *PreferOne) Configure(options json.RawMessage) error {
+	return configure(options, r)
+}
+
+func (r *PreferOne) appliesTo(locale language.Tag) bool {
+	base, _ := locale.Base()
+	for _, l := range r.Locales {
+		if l == locale.String() || l == base.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PreferOne) Check(c *Context) {
+	if !r.appliesTo(c.Locale) {
+		return
+	}
+	for i, t := range c.Buffer {
+		if t.Type != icumsg.TokenTypePlural ||
+			c.Buffer[i+2].Type == icumsg.TokenTypePluralOffset {
+			continue
+		}
+		for o := range icumsg.Options(c.Buffer, i) {
+			if c.Buffer[o].Type == icumsg.TokenTypeOptionNumber &&
+				c.Buffer[o+1].String(c.Source, c.Buffer) == "=1" {
+				c.ReportToken(o+1, `use "one" instead of "=1"`)
+			}
+		}
+	}
+}
+
+// MaxPluralDepth reports plural and selectordinal arguments
+// nested deeper than the maximum depth.
+//
+// Options:
+//
+//	{"max": 2}
+type MaxPluralDepth struct {
+	// Max is the maximum number of nested plural and selectordinal arguments.
+	Max int `json:"max"`
+}
+
+var _ Configurable = new(MaxPluralDepth)
+
+func (*MaxPluralDepth) ID() string { return "max-plural-depth" }
+
+func (*MaxPluralDepth) Description() string {
+	return "Plural arguments must not be nested too deep."
+}
+
+func (*MaxPluralDepth) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *MaxPluralDepth) Configure(options json.RawMessage) error {
+	return configure(options, r)
+}
+
+func (r *MaxPluralDepth) Check(c *Context) {
+	var ends []int // Terminator indexes of the enclosing plurals.
+	for i, t := range c.Buffer {
+		for len(ends) > 0 && i > ends[len(ends)-1] {
+			ends = ends[:len(ends)-1]
+		}
+		if t.Type != icumsg.TokenTypePlural && t.Type != icumsg.TokenTypeSelectOrdinal {
+			continue
+		}
+		if len(ends) >= r.Max {
+			c.ReportToken(i, "plural argument %q nested at depth %d exceeds maximum of %d",
+				c.Buffer[i+1].String(c.Source, c.Buffer), len(ends)+1, r.Max)
+		}
+		ends = append(ends, t.IndexEnd)
+	}
+}
+
+// OptionWhitespace reports options with leading or trailing whitespace.
+type OptionWhitespace struct{}
+
+func (*OptionWhitespace) ID() string { return "option-whitespace" }
+
+func (*OptionWhitespace) Description() string {
+	return "Options must not have leading or trailing whitespace."
+}
+
+func (*OptionWhitespace) DefaultSeverity() Severity { return SeverityWarning }
+
+func (*OptionWhitespace) Check(c *Context) {
+	for i, t := range c.Buffer {
+		if t.Type < icumsg.TokenTypeOption || t.Type > icumsg.TokenTypeOptionNumber {
+			continue
+		}
+		// Option names never contain '{', the tokenizer drops leading
+		// whitespace of select options which is why the source is checked.
+		start := t.IndexStart + strings.IndexByte(c.Source[t.IndexStart:], '{') + 1
+		end := c.Buffer[t.IndexEnd].IndexEnd - 1
+		content := c.Source[start:end]
+		if content == "" {
+			continue
+		}
+		if icumsg.IsWhitespace(content[0]) || icumsg.IsWhitespace(content[len(content)-1]) {
+			c.Report(start, end, "option %q has leading or trailing whitespace",
+				icumsg.OptionName(c.Source, c.Buffer, i))
+		}
+	}
+}
+
+// Argument name case styles.
+const (
+	CaseCamel  = "camel"  // userName
+	CasePascal = "pascal" // UserName
+	CaseSnake  = "snake"  // user_name
+)
+
+var casePatterns = map[string]*regexp.Regexp{
+	CaseCamel:  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	CasePascal: regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	CaseSnake:  regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+}
+
+var positionalArgName = regexp.MustCompile(`^[0-9]+$`)
+
+// ArgNameCase reports argument names not following the case style.
+// Positional (numeric) argument names are ignored.
+//
+// Options:
+//
+//	{"style": "camel"}
+type ArgNameCase struct {
+	// Style is either of CaseCamel, CasePascal and CaseSnake.
+	Style string `json:"style"`
+}
+
+var _ Configurable = new(ArgNameCase)
+
+func (*ArgNameCase) ID() string { return "arg-name-case" }
+
+func (*ArgNameCase) Description() string {
+	return "Argument names must follow the configured case style."
+}
+
+func (*ArgNameCase) DefaultSeverity() Severity { return SeverityWarning }
+
+func (r *ArgNameCase) Configure(options json.RawMessage) error {
+	if err := configure(options, r); err != nil {
+		return err
+	}
+	if _, ok := casePatterns[r.Style]; !ok {
+		return fmt.Errorf("unsupported style: %q", r.Style)
+	}
+	return nil
+}
+
+func (r *ArgNameCase) Check(c *Context) {
+	pattern := casePatterns[r.Style]
+	if pattern == nil {
+		return
+	}
+	for a := range icumsg.Arguments(c.Source, c.Buffer) {
+		if positionalArgName.MatchString(a.Name) || pattern.MatchString(a.Name) {
+			continue
+		}
+		c.ReportToken(a.Index+1, "argument name %q is not %s case", a.Name, r.Style)
+	}
+}
@@ -0,0 +1,103 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/lint"
+	"golang.org/x/text/language"
+)
+
+// check runs rule on src and returns the diagnostic messages.
+func check(t *testing.T, rule lint.Rule, locale language.Tag, src string) []string {
+	t.Helper()
+	l, err := lint.New(lint.Config{}, rule)
+	test.RequireNoErr(t, err)
+	var messages []string
+	for _, d := range l.Lint(message(t, locale, src)) {
+		messages = append(messages, src[d.Start:d.End]+": "+d.Message)
+	}
+	return messages
+}
+
+func TestSelectRequiredKeys(t *testing.T) {
+	f := func(t *testing.T, src string, expect ...string) {
+		t.Helper()
+		r := &lint.SelectRequiredKeys{Keys: map[string][]string{
+			"gender": {"male", "female"},
+		}}
+		test.RequireDeepEqual(t, expect, check(t, r, language.English, src))
+	}
+
+	f(t, "{gender, select, male {He} female {She} other {They}}")
+	f(t, "{kind, select, other {x}}")
+	f(t, "{gender, select, other {They}}",
+		`{gender, select, other {They}}: `+
+			`select argument "gender" is missing required options: male, female`)
+	f(t, "{n, plural, other {{gender, select, male {He} other {They}}}}",
+		`{gender, select, male {He} other {They}}: `+
+			`select argument "gender" is missing required options: female`)
+}
+
+func TestPreferOne(t *testing.T) {
+	f := func(t *testing.T, locale language.Tag, src string, expect ...string) {
+		t.Helper()
+		r := &lint.PreferOne{Locales: []string{"en"}}
+		test.RequireDeepEqual(t, expect, check(t, r, locale, src))
+	}
+
+	f(t, language.English, "{n, plural, one {#} other {#}}")
+	f(t, language.English, "{n, plural, =1 {#} other {#}}", `=1: use "one" instead of "=1"`)
+	f(t, language.AmericanEnglish, "{n, plural, =0 {none} =1 {#} other {#}}",
+		`=1: use "one" instead of "=1"`)
+	// Offset changes the meaning of categories.
+	f(t, language.English, "{n, plural, offset:1 =1 {#} other {#}}")
+	f(t, language.English, "{n, selectordinal, =1 {#st} other {#th}}")
+	f(t, language.French, "{n, plural, =1 {#} other {#}}")
+}
+
+func TestMaxPluralDepth(t *testing.T) {
+	f := func(t *testing.T, src string, expect ...string) {
+		t.Helper()
+		test.RequireDeepEqual(t, expect, check(t, &lint.MaxPluralDepth{Max: 2},
+			language.English, src))
+	}
+
+	f(t, "{a, plural, other {{b, plural, other {x}}}}")
+	f(t, "{a, plural, other {{s, select, other {{b, plural, other {x}}}}}}")
+	f(t, "{a, plural, other {#}} {b, plural, other {#}} {c, plural, other {#}}")
+	f(t, "{a, plural, other {{b, plural, other {#}}}} {c, plural, other {#}}")
+	f(t, "{a, plural, other {{b, selectordinal, other {{c, plural, other {x}}}}}}",
+		`{c, plural, other {x}}: plural argument "c" nested at depth 3 exceeds maximum of 2`)
+}
+
+func TestOptionWhitespace(t *testing.T) {
+	f := func(t *testing.T, src string, expect ...string) {
+		t.Helper()
+		test.RequireDeepEqual(t, expect, check(t, &lint.OptionWhitespace{},
+			language.English, src))
+	}
+
+	f(t, "{s, select, a {x} other {y z}}")
+	f(t, "{s, select, a { x} other {y}}", ` x: option "a" has leading or trailing whitespace`)
+	f(t, "{n, plural, =0 {x\n} other {#}}", "x\n: option \"=0\" has leading or trailing whitespace")
+	f(t, "{n, plural, other {{s, select, other {x }}}}",
+		`x : option "other" has leading or trailing whitespace`)
+}
+
+func TestArgNameCase(t *testing.T) {
+	f := func(t *testing.T, style, src string, expect ...string) {
+		t.Helper()
+		test.RequireDeepEqual(t, expect, check(t, &lint.ArgNameCase{Style: style},
+			language.English, src))
+	}
+
+	f(t, lint.CaseCamel, "{userName} {n} {0} {count, plural, other {#}}")
+	f(t, lint.CaseCamel, "{user_name} {UserName}",
+		`user_name: argument name "user_name" is not camel case`,
+		`UserName: argument name "UserName" is not camel case`)
+	f(t, lint.CasePascal, "{UserName} {userName}",
+		`userName: argument name "userName" is not pascal case`)
+	f(t, lint.CaseSnake, "{user_name} {s, select, other {{userName}}}",
+		`userName: argument name "userName" is not snake case`)
+}
@@ -0,0 +1,515 @@
+package mf2
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/cldr"
+)
+
+// ErrMissingFallback is returned by ToICU for matchers without a variant
+// applying when no other does, usually the one with only "*" keys.
+var ErrMissingFallback = errors.New("missing fallback variant")
+
+// FromICU converts the ICU message src tokenized into buffer to MF2.
+// Every plural, selectordinal and select argument becomes a selector
+// declared with ".input" ({$n :number}, {$n :number select=ordinal} and
+// {$g :string} respectively) and nested arguments are flattened into
+// a single ".match" with a variant per combination of options.
+// Options "other" become "*", "=N" become "N" and "#" becomes {$n}.
+// Typed simple arguments become :number, :integer, :percent, :currency,
+// :date and :time expressions with the date and time styles as option
+// "style". Plural offsets, skeletons, custom styles and the types
+// spellout, ordinal and duration are ErrUnrepresentable.
+func FromICU(src string, buffer []icumsg.Token) (*Message, error) {
+	c := icuConverter{src: src, buffer: buffer}
+	variants, err := c.expand(0, len(buffer), "")
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{Declarations: c.declarations}
+	if len(c.selectors) == 0 {
+		m.Pattern = variants[0].pattern
+		return m, nil
+	}
+	for _, s := range c.selectors {
+		m.Selectors = append(m.Selectors, s.name)
+	}
+	for _, v := range variants {
+		keys := make([]Key, len(c.selectors))
+		for i := range keys {
+			keys[i] = Key{Catchall: true}
+			if k, ok := v.keys[i]; ok {
+				keys[i] = k
+			}
+		}
+		if slices.ContainsFunc(m.Variants, func(x Variant) bool {
+			return slices.Equal(x.Keys, keys)
+		}) {
+			continue // Unreachable duplicate.
+		}
+		m.Variants = append(m.Variants, Variant{Keys: keys, Pattern: v.pattern})
+	}
+	return m, nil
+}
+
+type icuSelector struct {
+	name, arg string
+	kind      icumsg.TokenType
+}
+
+type icuVariant struct {
+	keys    map[int]Key // Keys by selector index.
+	pattern Pattern
+}
+
+type icuConverter struct {
+	src          string
+	buffer       []icumsg.Token
+	selectors    []icuSelector
+	declarations []Declaration
+}
+
+// selector returns the index of the selector of argument arg of kind
+// declaring it if necessary. Arguments used by selectors of different
+// kinds are declared again with ".local".
+func (c *icuConverter) selector(arg string, kind icumsg.TokenType) int {
+	for i, s := range c.selectors {
+		if s.arg == arg && s.kind == kind {
+			return i
+		}
+	}
+	e := Expression{Operand: &Value{Variable: arg}, Function: "number"}
+	switch kind {
+	case icumsg.TokenTypeSelect:
+		e.Function = "string"
+	case icumsg.TokenTypeSelectOrdinal:
+		e.Options = []Option{{Name: "select", Value: Value{Literal: "ordinal"}}}
+	}
+	d := Declaration{Input: true, Name: arg, Expression: e}
+	for n := 2; slices.ContainsFunc(c.selectors, func(s icuSelector) bool {
+		return s.name == d.Name
+	}); n++ {
+		d.Input, d.Name = false, fmt.Sprintf("%s_%d", arg, n)
+	}
+	c.declarations = append(c.declarations, d)
+	c.selectors = append(c.selectors, icuSelector{name: d.Name, arg: arg, kind: kind})
+	return len(c.selectors) - 1
+}
+
+// expand converts buffer[start:end] to a variant per combination of options.
+// hash is the name of the variable "#" refers to, empty outside
+// of plural and selectordinal options.
+func (c *icuConverter) expand(start, end int, hash string) ([]icuVariant, error) {
+	variants := []icuVariant{{keys: map[int]Key{}, pattern: Pattern{}}}
+	appendParts := func(parts ...Part) {
+		for i := range variants {
+			variants[i].pattern = appendPattern(variants[i].pattern, parts...)
+		}
+	}
+	for i := start; i < end; i++ {
+		t := c.buffer[i]
+		switch t.Type {
+		case icumsg.TokenTypeLiteral:
+			appendParts(literal(c.src[t.IndexStart:t.IndexEnd], hash)...)
+		case icumsg.TokenTypeSimpleArg:
+			e, err := c.expression(i)
+			if err != nil {
+				return nil, err
+			}
+			appendParts(Part{Expression: e})
+			// Skip the argument name, type and style.
+			i = icumsg.SimpleArgEnd(c.buffer, i)
+		case icumsg.TokenTypePlural, icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+			arg := c.buffer[i+1].String(c.src, c.buffer)
+			if c.buffer[i+2].Type == icumsg.TokenTypePluralOffset {
+				return nil, fmt.Errorf("%w: offset of %q", ErrUnrepresentable, arg)
+			}
+			sel := c.selector(arg, t.Type)
+			optionHash := hash
+			if t.Type != icumsg.TokenTypeSelect {
+				optionHash = c.selectors[sel].name
+			}
+			var next []icuVariant
+			for j := range icumsg.Options(c.buffer, i) {
+				key := optionKey(c.src, c.buffer, j)
+				sub, err := c.expand(j+1, c.buffer[j].IndexEnd, optionHash)
+				if err != nil {
+					return nil, err
+				}
+				for _, v := range variants {
+					for _, u := range sub {
+						if k, ok := v.keys[sel]; ok && k != key {
+							continue // Contradicting options of the same selector.
+						}
+						keys := map[int]Key{sel: key}
+						for s, k := range v.keys {
+							keys[s] = k
+						}
+						consistent := true
+						for s, k := range u.keys {
+							if x, ok := keys[s]; ok && x != k {
+								consistent = false
+							}
+							keys[s] = k
+						}
+						if !consistent {
+							continue
+						}
+						next = append(next, icuVariant{
+							keys:    keys,
+							pattern: appendPattern(slices.Clone(v.pattern), u.pattern...),
+						})
+					}
+				}
+			}
+			variants = next
+			i = t.IndexEnd
+		}
+	}
+	return variants, nil
+}
+
+// optionKey returns the key of the option at buffer[index].
+func optionKey(src string, buffer []icumsg.Token, index int) Key {
+	switch t := buffer[index].Type; t {
+	case icumsg.TokenTypeOptionOther:
+		return Key{Catchall: true}
+	case icumsg.TokenTypeOptionNumber:
+		return Key{Value: strings.TrimPrefix(icumsg.OptionName(src, buffer, index), "=")}
+	case icumsg.TokenTypeOption:
+		name := icumsg.OptionName(src, buffer, index)
+		if name == "other" {
+			return Key{Catchall: true}
+		}
+		return Key{Value: name}
+	default:
+		c, _ := cldr.CategoryOf(t)
+		return Key{Value: c.String()}
+	}
+}
+
+// expression converts the simple argument at buffer[index].
+func (c *icuConverter) expression(index int) (*Expression, error) {
+	var a icumsg.Argument
+	for a = range icumsg.Arguments(c.src, c.buffer[index:]) {
+		break
+	}
+	e := &Expression{Operand: &Value{Variable: a.Name}}
+	unrepresentable := func() (*Expression, error) {
+		return nil, fmt.Errorf("%w: %s", ErrUnrepresentable,
+			c.buffer[index].String(c.src, c.buffer))
+	}
+	switch a.Type {
+	case icumsg.TokenTypeSimpleArg:
+		return e, nil
+	case icumsg.TokenTypeArgTypeNumber:
+		switch a.Style {
+		case 0:
+			e.Function = "number"
+		case icumsg.TokenTypeArgStyleInteger:
+			e.Function = "integer"
+		case icumsg.TokenTypeArgStylePercent:
+			e.Function = "percent"
+		case icumsg.TokenTypeArgStyleCurrency:
+			e.Function = "currency"
+		default:
+			return unrepresentable()
+		}
+	case icumsg.TokenTypeArgTypeDate, icumsg.TokenTypeArgTypeTime:
+		e.Function = "date"
+		if a.Type == icumsg.TokenTypeArgTypeTime {
+			e.Function = "time"
+		}
+		switch a.Style {
+		case 0:
+		case icumsg.TokenTypeArgStyleShort, icumsg.TokenTypeArgStyleMedium,
+			icumsg.TokenTypeArgStyleLong, icumsg.TokenTypeArgStyleFull:
+			e.Options = []Option{{
+				Name: "style", Value: Value{Literal: styleNames[a.Style]},
+			}}
+		default:
+			return unrepresentable()
+		}
+	default:
+		return unrepresentable()
+	}
+	return e, nil
+}
+
+var keywords = map[icumsg.TokenType]string{
+	icumsg.TokenTypePlural:        "plural",
+	icumsg.TokenTypeSelect:        "select",
+	icumsg.TokenTypeSelectOrdinal: "selectordinal",
+}
+
+var styleNames = map[icumsg.TokenType]string{
+	icumsg.TokenTypeArgStyleShort:  "short",
+	icumsg.TokenTypeArgStyleMedium: "medium",
+	icumsg.TokenTypeArgStyleLong:   "long",
+	icumsg.TokenTypeArgStyleFull:   "full",
+}
+
+// literal unescapes the ICU literal l replacing '#' by {$hash}
+// unless hash is empty.
+func literal(l, hash string) []Part {
+	var parts []Part
+	for text, isHash := range icumsg.LiteralSegments(l, hash != "") {
+		if text != "" {
+			parts = append(parts, Part{Text: text})
+		}
+		if isHash {
+			parts = append(parts, Part{Expression: &Expression{
+				Operand: &Value{Variable: hash},
+			}})
+		}
+	}
+	return parts
+}
+
+// appendPattern appends parts to p merging adjacent text.
+func appendPattern(p Pattern, parts ...Part) Pattern {
+	for _, part := range parts {
+		if last := len(p) - 1; last >= 0 && part.Expression == nil &&
+			part.Markup == nil && p[last].Expression == nil && p[last].Markup == nil {
+			p[last].Text += part.Text
+			continue
+		}
+		p = append(p, part)
+	}
+	if p == nil {
+		p = Pattern{}
+	}
+	return p
+}
+
+// ToICU converts m to an ICU message. Selectors annotated with :number or
+// :integer become plural arguments, with :number select=ordinal
+// selectordinal arguments and all others select arguments.
+// Multiple selectors become nested arguments in the order of ".match".
+// Numeric keys of plural selectors become "=N" options and both "*"
+// and "other" become "other". Placeholders referring to the innermost
+// plural selector become "#". Markup, literal operands with functions
+// and functions other than :string, :number, :integer, :percent,
+// :currency, :date and :time are ErrUnrepresentable.
+// The result isn't validated.
+func (m *Message) ToICU() (string, error) {
+	c := mf2Converter{m: m}
+	if len(m.Selectors) == 0 {
+		return c.pattern(m.Pattern, "")
+	}
+	hash := ""
+	for _, s := range m.Selectors {
+		kind, _, err := c.selectorKind(s)
+		if err != nil {
+			return "", err
+		}
+		if kind != icumsg.TokenTypeSelect {
+			hash = s
+		}
+	}
+	var b strings.Builder
+	candidates := make([]candidate, len(m.Variants))
+	for i, v := range m.Variants {
+		candidates[i] = candidate{Variant: v}
+	}
+	if err := c.match(&b, 0, candidates, hash); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type mf2Converter struct{ m *Message }
+
+// resolve returns the argument name and the expression
+// the variable name is bound to.
+func (c mf2Converter) resolve(name string) (string, Expression) {
+	d := c.m.Declaration(name)
+	if d == nil || d.Input {
+		if d == nil {
+			return name, Expression{}
+		}
+		return name, d.Expression
+	}
+	if d.Expression.Operand == nil || d.Expression.Operand.Variable == "" {
+		return name, d.Expression
+	}
+	arg, e := c.resolve(d.Expression.Operand.Variable)
+	if d.Expression.Function != "" {
+		e.Function, e.Options = d.Expression.Function, d.Expression.Options
+	}
+	return arg, e
+}
+
+// selectorKind returns the kind of ICU argument the selector becomes.
+func (c mf2Converter) selectorKind(name string) (icumsg.TokenType, string, error) {
+	arg, e := c.resolve(name)
+	switch e.Function {
+	case "number", "integer":
+		for _, o := range e.Options {
+			if o.Name == "select" && o.Value.Literal == "ordinal" {
+				return icumsg.TokenTypeSelectOrdinal, arg, nil
+			}
+		}
+		return icumsg.TokenTypePlural, arg, nil
+	case "", "string":
+		return icumsg.TokenTypeSelect, arg, nil
+	}
+	return 0, "", fmt.Errorf("%w: selector $%s with :%s", ErrUnrepresentable, name, e.Function)
+}
+
+// candidate is a variant with its key preferences for the selectors
+// matched so far, 0 for matching keys and 1 for catchall keys.
+type candidate struct {
+	Variant
+	ranks []int
+}
+
+// match writes the argument of selector level choosing among
+// the candidates like MF2 does: the candidate preferred by the earliest
+// selector applies, later selectors only decide among equally preferred
+// candidates.
+func (c mf2Converter) match(b *strings.Builder, level int, candidates []candidate, hash string) error {
+	if level == len(c.m.Selectors) {
+		if len(candidates) == 0 {
+			return ErrMissingFallback
+		}
+		s, err := c.pattern(candidates[0].Pattern, hash)
+		b.WriteString(s)
+		return err
+	}
+	sel := c.m.Selectors[level]
+	kind, arg, err := c.selectorKind(sel)
+	if err != nil {
+		return err
+	}
+	isOther := func(k Key) bool { return k.Catchall || k.Value == "other" }
+	// filter returns the candidates matching key sorted by preference.
+	filter := func(match func(Key) bool) []candidate {
+		var filtered []candidate
+		for _, v := range candidates {
+			k := v.Keys[level]
+			if !k.Catchall && !match(k) {
+				continue
+			}
+			rank := 0
+			if k.Catchall {
+				rank = 1
+			}
+			filtered = append(filtered, candidate{
+				Variant: v.Variant, ranks: append(slices.Clone(v.ranks), rank),
+			})
+		}
+		slices.SortStableFunc(filtered, func(a, b candidate) int {
+			return slices.Compare(a.ranks, b.ranks)
+		})
+		return filtered
+	}
+
+	var keys []string
+	for _, v := range candidates {
+		if k := v.Keys[level]; !isOther(k) && !slices.Contains(keys, k.Value) {
+			keys = append(keys, k.Value)
+		}
+	}
+	b.WriteString("{" + arg + ", " + keywords[kind] + ",")
+	for _, k := range keys {
+		name := k
+		if kind != icumsg.TokenTypeSelect {
+			if _, err := cldr.ParseCategory(k); err != nil {
+				if !isNumber(k) {
+					return fmt.Errorf("%w: key %q of $%s", ErrUnrepresentable, k, sel)
+				}
+				name = "=" + k
+			}
+		}
+		b.WriteString(" " + name + " {")
+		err := c.match(b, level+1, filter(func(x Key) bool { return x.Value == k }), hash)
+		if err != nil {
+			return err
+		}
+		b.WriteString("}")
+	}
+	b.WriteString(" other {")
+	if err := c.match(b, level+1, filter(isOther), hash); err != nil {
+		return err
+	}
+	b.WriteString("}}")
+	return nil
+}
+
+// pattern converts p. hash is the variable of the innermost plural selector.
+func (c mf2Converter) pattern(p Pattern, hash string) (string, error) {
+	var b strings.Builder
+	for _, part := range p {
+		switch {
+		case part.Markup != nil:
+			return "", fmt.Errorf("%w: markup {#%s}", ErrUnrepresentable, part.Markup.Name)
+		case part.Expression != nil:
+			if err := c.expression(&b, part.Expression, hash); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteString(icumsg.EscapeLiteral(part.Text))
+		}
+	}
+	return b.String(), nil
+}
+
+func (c mf2Converter) expression(b *strings.Builder, e *Expression, hash string) error {
+	if e.Operand == nil {
+		return fmt.Errorf("%w: function :%s without operand", ErrUnrepresentable, e.Function)
+	}
+	if e.Operand.Variable == "" {
+		if e.Function != "" {
+			return fmt.Errorf("%w: literal %q with :%s",
+				ErrUnrepresentable, e.Operand.Literal, e.Function)
+		}
+		b.WriteString(icumsg.EscapeLiteral(e.Operand.Literal))
+		return nil
+	}
+	if e.Operand.Variable == hash && e.Function == "" {
+		b.WriteByte('#')
+		return nil
+	}
+	arg, resolved := c.resolve(e.Operand.Variable)
+	if e.Function != "" {
+		resolved.Function, resolved.Options = e.Function, e.Options
+	}
+	unrepresentable := func() error {
+		var s strings.Builder
+		writeExpression(&s, e)
+		return fmt.Errorf("%w: %s", ErrUnrepresentable, s.String())
+	}
+	style := ""
+	for _, o := range resolved.Options {
+		switch {
+		case o.Name == "select":
+		case o.Name == "style" && (resolved.Function == "date" || resolved.Function == "time"):
+			switch o.Value.Literal {
+			case "short", "medium", "long", "full":
+				style = ", " + o.Value.Literal
+			default:
+				return unrepresentable()
+			}
+		default:
+			return unrepresentable()
+		}
+	}
+	switch resolved.Function {
+	case "", "string":
+		b.WriteString("{" + arg + "}")
+	case "number":
+		b.WriteString("{" + arg + ", number}")
+	case "integer", "percent", "currency":
+		b.WriteString("{" + arg + ", number, " + resolved.Function + "}")
+	case "date", "time":
+		b.WriteString("{" + arg + ", " + resolved.Function + style + "}")
+	default:
+		return unrepresentable()
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package mf2_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/mf2"
+	"golang.org/x/text/language"
+)
+
+func TestFromICU(t *testing.T) {
+	f := func(t *testing.T, src, expect string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		m, err := mf2.FromICU(src, buffer)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, m.String())
+
+		// The output parses to the same message.
+		parsed, err := mf2.Parse(m.String())
+		test.RequireNoErr(t, err)
+		test.RequireDeepEqual(t, m, parsed)
+	}
+
+	f(t, "", "")
+	f(t, "It''s '{'{name}'}' # 100%", `It's \{{$name}\} # 100%`)
+	f(t, ".{n, number} {n, number, integer} {p, number, percent} {c, number, currency}",
+		"{{.{$n :number} {$n :integer} {$p :percent} {$c :currency}}}")
+	f(t, "{d, date} {d, date, short} {t, time, full}",
+		"{$d :date} {$d :date style=short} {$t :time style=full}")
+	f(t, "You have {count, plural, =0 {no files} one {# file} other {# '#' files}}.",
+		`.input {$count :number}
+.match $count
+0 {{You have no files.}}
+one {{You have {$count} file.}}
+* {{You have {$count} # files.}}`)
+	f(t, "{pos, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+		`.input {$pos :number select=ordinal}
+.match $pos
+one {{{$pos}st}}
+two {{{$pos}nd}}
+few {{{$pos}rd}}
+* {{{$pos}th}}`)
+	// Nested arguments are flattened.
+	f(t, "{g, select, female {{n, plural, one {She has # file} other {She has # files}}} "+
+		"other {{n, plural, one {They have # file} other {They have # files}}}}",
+		`.input {$g :string}
+.input {$n :number}
+.match $g $n
+female one {{She has {$n} file}}
+female * {{She has {$n} files}}
+* one {{They have {$n} file}}
+* * {{They have {$n} files}}`)
+	// Selectors used in only some options match any key in the others.
+	f(t, "{n, plural, one {# file} other {# files of {g, select, a {A} other {B}}}}",
+		`.input {$n :number}
+.input {$g :string}
+.match $n $g
+one * {{{$n} file}}
+* a {{{$n} files of A}}
+* * {{{$n} files of B}}`)
+	// Repeated selectors match the same keys.
+	f(t, "{n, plural, one {#} other {#s}}, {n, plural, one {a} other {b}}",
+		`.input {$n :number}
+.match $n
+one {{{$n}, a}}
+* {{{$n}s, b}}`)
+	// The same argument of a different kind is declared again.
+	f(t, "{n, plural, other {{n, selectordinal, one {#st} other {#th}}}}",
+		`.input {$n :number}
+.local $n_2 = {$n :number select=ordinal}
+.match $n $n_2
+* one {{{$n_2}st}}
+* * {{{$n_2}th}}`)
+}
+
+func TestFromICUErr(t *testing.T) {
+	f := func(t *testing.T, src string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		_, err = mf2.FromICU(src, buffer)
+		test.RequireErrIs(t, mf2.ErrUnrepresentable, err)
+	}
+
+	f(t, "{n, plural, offset:1 other {#}}")
+	f(t, "{n, number, ::currency/EUR}")
+	f(t, "{d, date, ::yMMMd}")
+	f(t, "{n, spellout}")
+	f(t, "{n, ordinal}")
+	f(t, "{n, duration}")
+}
+
+func TestToICU(t *testing.T) {
+	f := func(t *testing.T, input, expect string) {
+		t.Helper()
+		m, err := mf2.Parse(input)
+		test.RequireNoErr(t, err)
+		actual, err := m.ToICU()
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, actual)
+		var tokenizer icumsg.Tokenizer
+		_, err = tokenizer.Tokenize(language.English, nil, actual)
+		test.RequireNoErr(t, err)
+	}
+
+	f(t, "It's {|{literal}|} #{$name}", "It''s '{'literal'}' '#'{name}")
+	f(t, ".input {$d :date style=long} .local $n = {$x :integer} {{{$d} {$n} {$x :number} "+
+		"{$p :percent} {$t :time}}}",
+		"{d, date, long} {x, number, integer} {x, number} {p, number, percent} {t, time}")
+	f(t, `.input {$count :number}
+.match $count
+0 {{no files}}
+one {{{$count} file}}
+* {{{$count} files}}`, "{count, plural, =0 {no files} one {# file} other {# files}}")
+	f(t, `.input {$pos :number select=ordinal}
+.match $pos
+one {{{$pos}st}}
+other {{{$pos}th}}`, "{pos, selectordinal, one {#st} other {#th}}")
+	// Variants with a matching key are preferred over catchall keys
+	// of earlier selectors.
+	f(t, `.input {$g :string}
+.input {$n :number}
+.match $g $n
+female one {{She has one}}
+female * {{She has {$n}}}
+* one {{They have one}}
+* * {{They have {$n}}}`,
+		"{g, select, female {{n, plural, one {She has one} other {She has #}}} "+
+			"other {{n, plural, one {They have one} other {They have #}}}}")
+	f(t, `.input {$g :string}
+.input {$n :number}
+.match $g $n
+female * {{She}}
+* one {{One}}
+* * {{Other}}`,
+		"{g, select, female {{n, plural, one {She} other {She}}} "+
+			"other {{n, plural, one {One} other {Other}}}}")
+}
+
+func TestToICUErr(t *testing.T) {
+	f := func(t *testing.T, input string, expect error) {
+		t.Helper()
+		m, err := mf2.Parse(input)
+		test.RequireNoErr(t, err)
+		_, err = m.ToICU()
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, "{#b}bold{/b}", mf2.ErrUnrepresentable)
+	f(t, "{:now}", mf2.ErrUnrepresentable)
+	f(t, "{|1| :number}", mf2.ErrUnrepresentable)
+	f(t, "{$x :unknown}", mf2.ErrUnrepresentable)
+	f(t, "{$x :number minimumFractionDigits=2}", mf2.ErrUnrepresentable)
+	f(t, ".input {$x :unknown} .match $x * {{x}}", mf2.ErrUnrepresentable)
+	f(t, ".input {$n :number} .match $n |a b| {{x}} * {{y}}", mf2.ErrUnrepresentable)
+	f(t, ".input {$n :number} .match $n one {{x}}", mf2.ErrMissingFallback)
+}
@@ -0,0 +1,339 @@
+// Package mf2 parses and prints Unicode MessageFormat 2 (MF2) messages
+// and converts them to ICU MessageFormat messages and back.
+package mf2
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrSyntax = errors.New("syntax error")
+
+	// ErrUnrepresentable is returned for constructs that have no equivalent
+	// in the target syntax, such as plural offsets and custom number
+	// patterns in ICU or markup and unknown functions in MF2.
+	ErrUnrepresentable = errors.New("not representable")
+)
+
+// Message is an MF2 message. Messages without selectors are pattern
+// messages, all others are matchers.
+type Message struct {
+	Declarations []Declaration
+
+	// Pattern is the pattern of a pattern message.
+	Pattern Pattern
+
+	// Selectors are the names of the variables of ".match".
+	Selectors []string
+
+	// Variants are the variants of a matcher.
+	Variants []Variant
+}
+
+// Declaration is an ".input" or ".local" declaration.
+type Declaration struct {
+	// Input is true for ".input" and false for ".local" declarations.
+	Input bool
+
+	// Name is the name of the declared variable without "$".
+	Name       string
+	Expression Expression
+}
+
+// Variant is a variant of a matcher with a key per selector.
+type Variant struct {
+	Keys    []Key
+	Pattern Pattern
+}
+
+// Key is a variant key.
+type Key struct {
+	// Catchall is true for "*".
+	Catchall bool
+
+	Value string
+}
+
+// Pattern is a sequence of text and placeholders.
+type Pattern []Part
+
+// Part is either text, an expression or markup.
+type Part struct {
+	// Text is the unescaped text if both Expression and Markup are nil.
+	Text string
+
+	Expression *Expression
+	Markup     *Markup
+}
+
+// Expression is an expression placeholder such as {$count :number}.
+type Expression struct {
+	// Operand is nil for expressions with a function only.
+	Operand *Value
+
+	// Function is the function name without ":", empty if there is none.
+	Function   string
+	Options    []Option
+	Attributes []Attribute
+}
+
+// Value is a literal or a variable reference.
+type Value struct {
+	// Variable is the name of the variable without "$",
+	// empty for literals.
+	Variable string
+
+	// Literal is the unescaped literal.
+	Literal string
+}
+
+// Option is a function or markup option.
+type Option struct {
+	Name  string
+	Value Value
+}
+
+// Attribute is an expression or markup attribute such as @translate=no.
+type Attribute struct {
+	Name string
+
+	// Value is the unescaped literal, empty if there is none.
+	Value string
+}
+
+// MarkupKind is the kind of a markup placeholder.
+type MarkupKind int8
+
+const (
+	_ MarkupKind = iota
+	MarkupOpen
+	MarkupStandalone
+	MarkupClose
+)
+
+// Markup is a markup placeholder such as {#b}, {/b} or {#br/}.
+type Markup struct {
+	Kind       MarkupKind
+	Name       string
+	Options    []Option
+	Attributes []Attribute
+}
+
+// Declaration returns the declaration of variable name
+// or nil if there is none.
+func (m *Message) Declaration(name string) *Declaration {
+	for i := range m.Declarations {
+		if m.Declarations[i].Name == name {
+			return &m.Declarations[i]
+		}
+	}
+	return nil
+}
+
+// String returns m in MF2 syntax. Pattern messages without declarations
+// are printed as simple messages unless the pattern starts with
+// '.' or whitespace.
+func (m *Message) String() string {
+	var b strings.Builder
+	if len(m.Declarations) == 0 && len(m.Selectors) == 0 {
+		if len(m.Pattern) == 0 || m.Pattern[0].Expression != nil ||
+			m.Pattern[0].Markup != nil || !startsAmbiguous(m.Pattern[0].Text) {
+			writePattern(&b, m.Pattern)
+			return b.String()
+		}
+	}
+	for _, d := range m.Declarations {
+		if d.Input {
+			b.WriteString(".input ")
+		} else {
+			b.WriteString(".local $" + d.Name + " = ")
+		}
+		writeExpression(&b, &d.Expression)
+		b.WriteByte('\n')
+	}
+	if len(m.Selectors) == 0 {
+		b.WriteString("{{")
+		writePattern(&b, m.Pattern)
+		b.WriteString("}}")
+		return b.String()
+	}
+	b.WriteString(".match")
+	for _, s := range m.Selectors {
+		b.WriteString(" $" + s)
+	}
+	for _, v := range m.Variants {
+		b.WriteByte('\n')
+		for _, k := range v.Keys {
+			if k.Catchall {
+				b.WriteString("* ")
+			} else {
+				writeLiteral(&b, k.Value)
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString("{{")
+		writePattern(&b, v.Pattern)
+		b.WriteString("}}")
+	}
+	return b.String()
+}
+
+func startsAmbiguous(text string) bool {
+	return text != "" && (text[0] == '.' || isWhitespace(text[0]))
+}
+
+func writePattern(b *strings.Builder, p Pattern) {
+	for _, part := range p {
+		switch {
+		case part.Expression != nil:
+			writeExpression(b, part.Expression)
+		case part.Markup != nil:
+			writeMarkup(b, part.Markup)
+		default:
+			textEscaper.WriteString(b, part.Text)
+		}
+	}
+}
+
+var (
+	textEscaper    = strings.NewReplacer(`\`, `\\`, `{`, `\{`, `}`, `\}`)
+	literalEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+)
+
+func writeExpression(b *strings.Builder, e *Expression) {
+	b.WriteByte('{')
+	if e.Operand != nil {
+		writeValue(b, *e.Operand)
+	}
+	if e.Function != "" {
+		if e.Operand != nil {
+			b.WriteByte(' ')
+		}
+		b.WriteString(":" + e.Function)
+		writeOptions(b, e.Options)
+	}
+	writeAttributes(b, e.Attributes)
+	b.WriteByte('}')
+}
+
+func writeMarkup(b *strings.Builder, m *Markup) {
+	if m.Kind == MarkupClose {
+		b.WriteString("{/" + m.Name)
+	} else {
+		b.WriteString("{#" + m.Name)
+	}
+	writeOptions(b, m.Options)
+	writeAttributes(b, m.Attributes)
+	if m.Kind == MarkupStandalone {
+		b.WriteString("/}")
+	} else {
+		b.WriteByte('}')
+	}
+}
+
+func writeOptions(b *strings.Builder, options []Option) {
+	for _, o := range options {
+		b.WriteString(" " + o.Name + "=")
+		writeValue(b, o.Value)
+	}
+}
+
+func writeAttributes(b *strings.Builder, attributes []Attribute) {
+	for _, a := range attributes {
+		b.WriteString(" @" + a.Name)
+		if a.Value != "" {
+			b.WriteByte('=')
+			writeLiteral(b, a.Value)
+		}
+	}
+}
+
+func writeValue(b *strings.Builder, v Value) {
+	if v.Variable != "" {
+		b.WriteString("$" + v.Variable)
+		return
+	}
+	writeLiteral(b, v.Literal)
+}
+
+// writeLiteral writes l unquoted if it's a valid unquoted literal.
+func writeLiteral(b *strings.Builder, l string) {
+	if isUnquotedLiteral(l) {
+		b.WriteString(l)
+		return
+	}
+	b.WriteByte('|')
+	literalEscaper.WriteString(b, l)
+	b.WriteByte('|')
+}
+
+// isUnquotedLiteral reports whether l is a name or a number literal.
+func isUnquotedLiteral(l string) bool {
+	if l == "" {
+		return false
+	}
+	if isNameStart(rune(l[0])) || l[0] >= 0x80 {
+		return isName(l)
+	}
+	return isNumber(l)
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' ||
+		r >= 0xC0 && r != 0xD7 && r != 0xF7 && r != 0x3000 && r != 0xFEFF &&
+			(r < 0x2000 || r > 0x206F) && (r < 0xFFF0 || r > 0xFFFF)
+}
+
+func isNameChar(r rune) bool {
+	return isNameStart(r) || r >= '0' && r <= '9' || r == '-' || r == '.' ||
+		r == 0xB7 || r >= 0x300 && r <= 0x36F || r == 0x203F || r == 0x2040
+}
+
+func isName(s string) bool {
+	for i, r := range s {
+		if i == 0 && !isNameStart(r) || !isNameChar(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isNumber reports whether s is a number literal:
+// "-"? (0 | [1-9][0-9]*) ("." [0-9]+)? ([eE] [-+]? [0-9]+)?
+func isNumber(s string) bool {
+	i := 0
+	digits := func() int {
+		start := i
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	if n := digits(); n == 0 || n > 1 && s[i-n] == '0' {
+		return false
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == len(s)
+}
@@ -0,0 +1,33 @@
+package mf2_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/mf2"
+)
+
+func TestString(t *testing.T) {
+	f := func(t *testing.T, input, expect string) {
+		t.Helper()
+		m, err := mf2.Parse(input)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, m.String())
+
+		// The output parses to the same message.
+		m2, err := mf2.Parse(m.String())
+		test.RequireNoErr(t, err)
+		test.RequireDeepEqual(t, m, m2)
+	}
+
+	f(t, "", "")
+	f(t, `Hello \\ \{ {  $name  } |!`, `Hello \\ \{ {$name} |!`)
+	f(t, "{{.dot}}", "{{.dot}}")
+	f(t, "{{ space}}", "{{ space}}")
+	f(t, `{ |a b|:f  o = |x|  @a  @b = c }{#b @x}{/b}{ #br /}`,
+		`{|a b| :f o=x @a @b=c}{#b @x}{/b}{#br/}`)
+	f(t, ".input{$n :number}.local $m={$n :integer}{{{$m}}}",
+		".input {$n :number}\n.local $m = {$n :integer}\n{{{$m}}}")
+	f(t, ".input {$n :number} .match $n 1 {{one}} |x y| {{x}} * {{many}}",
+		".input {$n :number}\n.match $n\n1 {{one}}\n|x y| {{x}}\n* {{many}}")
+}
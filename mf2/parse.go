@@ -0,0 +1,411 @@
+package mf2
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parse parses the MF2 message s. Whitespace between tokens
+// is accepted wherever the syntax allows optional whitespace and
+// in some places where it requires whitespace.
+func Parse(s string) (*Message, error) {
+	p := parser{s: s}
+	m, err := p.message()
+	if err != nil {
+		return nil, fmt.Errorf("%w at %d: %v", ErrSyntax, p.pos, err)
+	}
+	return m, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) message() (*Message, error) {
+	m := new(Message)
+	p.skipWhitespace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '.' && !strings.HasPrefix(p.s[p.pos:], "{{") {
+		// Simple message including the leading whitespace.
+		p.pos = 0
+		pattern, err := p.pattern(false)
+		if err != nil {
+			return nil, err
+		}
+		m.Pattern = pattern
+		return m, nil
+	}
+	for {
+		p.skipWhitespace()
+		switch {
+		case strings.HasPrefix(p.s[p.pos:], ".input"):
+			p.pos += len(".input")
+			p.skipWhitespace()
+			if !strings.HasPrefix(p.s[p.pos:], "{") {
+				return nil, fmt.Errorf("expected variable expression")
+			}
+			e, _, err := p.placeholder()
+			if err != nil {
+				return nil, err
+			}
+			if e == nil || e.Operand == nil || e.Operand.Variable == "" {
+				return nil, fmt.Errorf("expected variable expression")
+			}
+			m.Declarations = append(m.Declarations, Declaration{
+				Input: true, Name: e.Operand.Variable, Expression: *e,
+			})
+		case strings.HasPrefix(p.s[p.pos:], ".local"):
+			p.pos += len(".local")
+			p.skipWhitespace()
+			name, err := p.variable()
+			if err != nil {
+				return nil, err
+			}
+			p.skipWhitespace()
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			p.skipWhitespace()
+			e, _, err := p.placeholder()
+			if err != nil {
+				return nil, err
+			}
+			if e == nil {
+				return nil, fmt.Errorf("expected expression")
+			}
+			m.Declarations = append(m.Declarations, Declaration{Name: name, Expression: *e})
+		case strings.HasPrefix(p.s[p.pos:], ".match"):
+			p.pos += len(".match")
+			return m, p.matcher(m)
+		case strings.HasPrefix(p.s[p.pos:], "{{"):
+			p.pos += 2
+			pattern, err := p.pattern(true)
+			if err != nil {
+				return nil, err
+			}
+			m.Pattern = pattern
+			return m, p.end()
+		default:
+			return nil, fmt.Errorf("expected declaration or quoted pattern")
+		}
+	}
+}
+
+func (p *parser) matcher(m *Message) error {
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) || p.s[p.pos] != '$' {
+			break
+		}
+		name, err := p.variable()
+		if err != nil {
+			return err
+		}
+		m.Selectors = append(m.Selectors, name)
+	}
+	if len(m.Selectors) == 0 {
+		return fmt.Errorf("expected selector")
+	}
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) {
+			break
+		}
+		var v Variant
+		for !strings.HasPrefix(p.s[p.pos:], "{{") {
+			if p.s[p.pos] == '*' {
+				p.pos++
+				v.Keys = append(v.Keys, Key{Catchall: true})
+			} else {
+				l, err := p.literal()
+				if err != nil {
+					return err
+				}
+				v.Keys = append(v.Keys, Key{Value: l})
+			}
+			p.skipWhitespace()
+			if p.pos >= len(p.s) {
+				return fmt.Errorf("expected quoted pattern")
+			}
+		}
+		if len(v.Keys) != len(m.Selectors) {
+			return fmt.Errorf("expected %d keys, got %d", len(m.Selectors), len(v.Keys))
+		}
+		p.pos += 2
+		pattern, err := p.pattern(true)
+		if err != nil {
+			return err
+		}
+		v.Pattern = pattern
+		m.Variants = append(m.Variants, v)
+	}
+	if len(m.Variants) == 0 {
+		return fmt.Errorf("expected variant")
+	}
+	return nil
+}
+
+// pattern parses a pattern up to the end of input or,
+// if quoted, up to and including "}}".
+func (p *parser) pattern(quoted bool) (Pattern, error) {
+	pattern := Pattern{}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			pattern = append(pattern, Part{Text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; c {
+		case '\\':
+			if p.pos+1 >= len(p.s) || strings.IndexByte(`\{}|`, p.s[p.pos+1]) == -1 {
+				return nil, fmt.Errorf("invalid escape sequence")
+			}
+			text.WriteByte(p.s[p.pos+1])
+			p.pos += 2
+		case '{':
+			flush()
+			e, markup, err := p.placeholder()
+			if err != nil {
+				return nil, err
+			}
+			pattern = append(pattern, Part{Expression: e, Markup: markup})
+		case '}':
+			if !quoted || !strings.HasPrefix(p.s[p.pos:], "}}") {
+				return nil, fmt.Errorf("unexpected '}'")
+			}
+			p.pos += 2
+			flush()
+			return pattern, nil
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("expected \"}}\"")
+	}
+	flush()
+	return pattern, nil
+}
+
+// placeholder parses an expression or markup placeholder.
+func (p *parser) placeholder() (*Expression, *Markup, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, nil, err
+	}
+	p.skipWhitespace()
+	if p.pos < len(p.s) && (p.s[p.pos] == '#' || p.s[p.pos] == '/') {
+		m := &Markup{Kind: MarkupOpen}
+		if p.s[p.pos] == '/' {
+			m.Kind = MarkupClose
+		}
+		p.pos++
+		var err error
+		if m.Name, err = p.identifier(); err != nil {
+			return nil, nil, err
+		}
+		if m.Options, err = p.options(); err != nil {
+			return nil, nil, err
+		}
+		if m.Attributes, err = p.attributes(); err != nil {
+			return nil, nil, err
+		}
+		p.skipWhitespace()
+		if m.Kind == MarkupOpen && strings.HasPrefix(p.s[p.pos:], "/") {
+			m.Kind = MarkupStandalone
+			p.pos++
+		}
+		return nil, m, p.expect("}")
+	}
+
+	e := new(Expression)
+	if p.pos < len(p.s) && p.s[p.pos] != ':' {
+		v, err := p.value()
+		if err != nil {
+			return nil, nil, err
+		}
+		e.Operand = &v
+		p.skipWhitespace()
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == ':' {
+		p.pos++
+		var err error
+		if e.Function, err = p.identifier(); err != nil {
+			return nil, nil, err
+		}
+		if e.Options, err = p.options(); err != nil {
+			return nil, nil, err
+		}
+	} else if e.Operand == nil {
+		return nil, nil, fmt.Errorf("expected operand or function")
+	}
+	var err error
+	if e.Attributes, err = p.attributes(); err != nil {
+		return nil, nil, err
+	}
+	p.skipWhitespace()
+	return e, nil, p.expect("}")
+}
+
+func (p *parser) options() (options []Option, err error) {
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("expected '}'")
+		}
+		if r, _ := utf8.DecodeRuneInString(p.s[p.pos:]); !isNameStart(r) {
+			return options, nil
+		}
+		var o Option
+		if o.Name, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if o.Value, err = p.value(); err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+	}
+}
+
+func (p *parser) attributes() (attributes []Attribute, err error) {
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.s) || p.s[p.pos] != '@' {
+			return attributes, nil
+		}
+		p.pos++
+		var a Attribute
+		if a.Name, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		save := p.pos
+		p.skipWhitespace()
+		if p.pos < len(p.s) && p.s[p.pos] == '=' {
+			p.pos++
+			p.skipWhitespace()
+			if a.Value, err = p.literal(); err != nil {
+				return nil, err
+			}
+		} else {
+			p.pos = save
+		}
+		attributes = append(attributes, a)
+	}
+}
+
+// value parses a variable or a literal.
+func (p *parser) value() (Value, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '$' {
+		name, err := p.variable()
+		return Value{Variable: name}, err
+	}
+	l, err := p.literal()
+	return Value{Literal: l}, err
+}
+
+func (p *parser) variable() (string, error) {
+	if err := p.expect("$"); err != nil {
+		return "", err
+	}
+	return p.name()
+}
+
+// literal parses a quoted or unquoted literal.
+func (p *parser) literal() (string, error) {
+	if p.pos < len(p.s) && p.s[p.pos] == '|' {
+		p.pos++
+		var b strings.Builder
+		for p.pos < len(p.s) {
+			switch c := p.s[p.pos]; c {
+			case '\\':
+				if p.pos+1 >= len(p.s) || strings.IndexByte(`\{}|`, p.s[p.pos+1]) == -1 {
+					return "", fmt.Errorf("invalid escape sequence")
+				}
+				b.WriteByte(p.s[p.pos+1])
+				p.pos += 2
+			case '|':
+				p.pos++
+				return b.String(), nil
+			default:
+				b.WriteByte(c)
+				p.pos++
+			}
+		}
+		return "", fmt.Errorf("unclosed quoted literal")
+	}
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if !isNameChar(r) && r != '+' {
+			break
+		}
+		p.pos += size
+	}
+	if l := p.s[start:p.pos]; isName(l) || isNumber(l) {
+		return l, nil
+	}
+	p.pos = start
+	return "", fmt.Errorf("expected literal")
+}
+
+// identifier parses a name optionally prefixed with a namespace.
+func (p *parser) identifier() (string, error) {
+	name, err := p.name()
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == ':' {
+		p.pos++
+		n, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		name += ":" + n
+	}
+	return name, nil
+}
+
+func (p *parser) name() (string, error) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if p.pos == start && !isNameStart(r) || !isNameChar(r) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("expected name")
+	}
+	return p.s[start:p.pos], nil
+}
+
+func (p *parser) expect(s string) error {
+	if !strings.HasPrefix(p.s[p.pos:], s) {
+		return fmt.Errorf("expected %q", s)
+	}
+	p.pos += len(s)
+	return nil
+}
+
+func (p *parser) end() error {
+	p.skipWhitespace()
+	if p.pos < len(p.s) {
+		return fmt.Errorf("unexpected content after quoted pattern")
+	}
+	return nil
+}
+
+func (p *parser) skipWhitespace() {
+	for p.pos < len(p.s) && isWhitespace(p.s[p.pos]) {
+		p.pos++
+	}
+}
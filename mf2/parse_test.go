@@ -0,0 +1,109 @@
+package mf2_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/mf2"
+)
+
+func TestParse(t *testing.T) {
+	f := func(t *testing.T, input string, expect *mf2.Message) {
+		t.Helper()
+		actual, err := mf2.Parse(input)
+		test.RequireNoErr(t, err)
+		test.RequireDeepEqual(t, expect, actual)
+	}
+
+	f(t, "", &mf2.Message{Pattern: mf2.Pattern{}})
+	f(t, ` Hello \{{$name}\}!`, &mf2.Message{Pattern: mf2.Pattern{
+		{Text: " Hello {"},
+		{Expression: &mf2.Expression{Operand: &mf2.Value{Variable: "name"}}},
+		{Text: "}!"},
+	}})
+	f(t, `{|a\|b| :x:fn opt=1.5e3 other=$v @attr @translate=no}{:now}{#b}{/b}{#br /}`,
+		&mf2.Message{Pattern: mf2.Pattern{
+			{Expression: &mf2.Expression{
+				Operand:  &mf2.Value{Literal: "a|b"},
+				Function: "x:fn",
+				Options: []mf2.Option{
+					{Name: "opt", Value: mf2.Value{Literal: "1.5e3"}},
+					{Name: "other", Value: mf2.Value{Variable: "v"}},
+				},
+				Attributes: []mf2.Attribute{{Name: "attr"}, {Name: "translate", Value: "no"}},
+			}},
+			{Expression: &mf2.Expression{Function: "now"}},
+			{Markup: &mf2.Markup{Kind: mf2.MarkupOpen, Name: "b"}},
+			{Markup: &mf2.Markup{Kind: mf2.MarkupClose, Name: "b"}},
+			{Markup: &mf2.Markup{Kind: mf2.MarkupStandalone, Name: "br"}},
+		}})
+	f(t, "  .local $x = {|y|}\n{{.{$x}}}  ", &mf2.Message{
+		Declarations: []mf2.Declaration{{
+			Name: "x", Expression: mf2.Expression{Operand: &mf2.Value{Literal: "y"}},
+		}},
+		Pattern: mf2.Pattern{
+			{Text: "."},
+			{Expression: &mf2.Expression{Operand: &mf2.Value{Variable: "x"}}},
+		},
+	})
+	f(t, `.input {$n :number}
+.match $n $g
+0 * {{none}}
+one |a b| {{{$n} a b}}
+* * {{{$n} files}}`, &mf2.Message{
+		Declarations: []mf2.Declaration{{
+			Input: true, Name: "n", Expression: mf2.Expression{
+				Operand: &mf2.Value{Variable: "n"}, Function: "number",
+			},
+		}},
+		Selectors: []string{"n", "g"},
+		Variants: []mf2.Variant{
+			{
+				Keys:    []mf2.Key{{Value: "0"}, {Catchall: true}},
+				Pattern: mf2.Pattern{{Text: "none"}},
+			},
+			{
+				Keys: []mf2.Key{{Value: "one"}, {Value: "a b"}},
+				Pattern: mf2.Pattern{
+					{Expression: &mf2.Expression{Operand: &mf2.Value{Variable: "n"}}},
+					{Text: " a b"},
+				},
+			},
+			{
+				Keys: []mf2.Key{{Catchall: true}, {Catchall: true}},
+				Pattern: mf2.Pattern{
+					{Expression: &mf2.Expression{Operand: &mf2.Value{Variable: "n"}}},
+					{Text: " files"},
+				},
+			},
+		},
+	})
+}
+
+func TestParseErr(t *testing.T) {
+	f := func(t *testing.T, input string) {
+		t.Helper()
+		_, err := mf2.Parse(input)
+		test.RequireErrIs(t, mf2.ErrSyntax, err)
+	}
+
+	f(t, "a } b")
+	f(t, `a \x`)
+	f(t, "{$x")
+	f(t, "{}")
+	f(t, "{$}")
+	f(t, "{|x}")
+	f(t, "{$x :}")
+	f(t, "{$x :f o}")
+	f(t, "{#}")
+	f(t, "{{x}")
+	f(t, "{{x}} y")
+	f(t, ".input {|x|} {{}}")
+	f(t, ".local x = {$y} {{}}")
+	f(t, ".unknown {{}}")
+	f(t, ".match {{}}")
+	f(t, ".match $x")
+	f(t, ".match $x a b {{}}")
+	f(t, ".match $x a")
+	f(t, ".match $x 01 {{}}")
+}
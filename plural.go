@@ -0,0 +1,169 @@
+package icumsg
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/romshark/icumsg/internal/cldr"
+	"golang.org/x/text/language"
+)
+
+// PluralRules defines which CLDR plural categories are supported.
+type PluralRules struct{ Zero, One, Two, Few, Many, Other bool }
+
+// set marks the category of option token type t as supported.
+// No-op if t isn't a plural category option.
+func (r *PluralRules) set(t TokenType) {
+	switch t {
+	case TokenTypeOptionZero:
+		r.Zero = true
+	case TokenTypeOptionOne:
+		r.One = true
+	case TokenTypeOptionTwo:
+		r.Two = true
+	case TokenTypeOptionFew:
+		r.Few = true
+	case TokenTypeOptionMany:
+		r.Many = true
+	case TokenTypeOptionOther:
+		r.Other = true
+	}
+}
+
+// has returns true if the category of option token type t is supported.
+func (r PluralRules) has(t TokenType) bool {
+	switch t {
+	case TokenTypeOptionZero:
+		return r.Zero
+	case TokenTypeOptionOne:
+		return r.One
+	case TokenTypeOptionTwo:
+		return r.Two
+	case TokenTypeOptionFew:
+		return r.Few
+	case TokenTypeOptionMany:
+		return r.Many
+	case TokenTypeOptionOther:
+		return r.Other
+	}
+	return false
+}
+
+// all iterates over the option token types of all supported categories
+// in CLDR order (zero to other).
+func (r PluralRules) all() iter.Seq[TokenType] {
+	return func(yield func(TokenType) bool) {
+		for tp := TokenTypeOptionZero; tp <= TokenTypeOptionOther; tp++ {
+			if r.has(tp) && !yield(tp) {
+				return
+			}
+		}
+	}
+}
+
+// categoryName returns the CLDR plural category name of option token type t.
+func categoryName(t TokenType) string {
+	switch t {
+	case TokenTypeOptionZero:
+		return "zero"
+	case TokenTypeOptionOne:
+		return "one"
+	case TokenTypeOptionTwo:
+		return "two"
+	case TokenTypeOptionFew:
+		return "few"
+	case TokenTypeOptionMany:
+		return "many"
+	case TokenTypeOptionOther:
+		return "other"
+	}
+	return ""
+}
+
+// PluralRuleProvider provides cardinal and ordinal CLDR plural rules for locales.
+type PluralRuleProvider interface {
+	// PluralRules returns cardinal and ordinal plural rules for locale.
+	PluralRules(locale language.Tag) (cardinal, ordinal PluralRules)
+}
+
+// CLDRVersion is the version of CLDR that CLDRPluralRules are based on.
+const CLDRVersion = cldr.CLDRVersion
+
+// CLDRPluralRules is the default PluralRuleProvider
+// backed by the generated CLDR plural rules data.
+// Locales that are not found fall back to their base language.
+type CLDRPluralRules struct{}
+
+var _ PluralRuleProvider = CLDRPluralRules{}
+
+// PluralRules implements PluralRuleProvider.
+func (CLDRPluralRules) PluralRules(locale language.Tag) (cardinal, ordinal PluralRules) {
+	r, ok := cldr.PluralRulesByTag[locale]
+	if !ok {
+		base, _ := locale.Base()
+		r = cldr.PluralRulesByBase[base]
+	}
+	return PluralRules(r.Cardinal), PluralRules(r.Ordinal)
+}
+
+// PluralRuleRegistry is a PluralRuleProvider that allows registering
+// and overriding plural rules for specific locales, such as constructed or
+// private-use locales (for example "x-pirate" or "en-x-kids").
+// Locales that weren't registered are resolved through the fallback provider.
+// The zero value is ready to use and falls back to CLDRPluralRules.
+// PluralRuleRegistry is safe for concurrent use.
+type PluralRuleRegistry struct {
+	lock     sync.RWMutex
+	fallback PluralRuleProvider
+	byTag    map[language.Tag][2]PluralRules
+}
+
+var _ PluralRuleProvider = new(PluralRuleRegistry)
+
+// NewPluralRuleRegistry creates a new registry falling back to fallback
+// for locales that weren't registered. If fallback is nil,
+// CLDRPluralRules is used.
+func NewPluralRuleRegistry(fallback PluralRuleProvider) *PluralRuleRegistry {
+	if fallback == nil {
+		fallback = CLDRPluralRules{}
+	}
+	return &PluralRuleRegistry{
+		fallback: fallback,
+		byTag:    map[language.Tag][2]PluralRules{},
+	}
+}
+
+// Register sets cardinal and ordinal plural rules for locale overriding
+// any previous registration and the fallback provider.
+// Option "other" is always supported regardless of cardinal.Other and ordinal.Other.
+func (r *PluralRuleRegistry) Register(locale language.Tag, cardinal, ordinal PluralRules) {
+	cardinal.Other, ordinal.Other = true, true
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.byTag == nil {
+		r.byTag = map[language.Tag][2]PluralRules{}
+	}
+	r.byTag[locale] = [2]PluralRules{cardinal, ordinal}
+}
+
+// PluralRules implements PluralRuleProvider.
+func (r *PluralRuleRegistry) PluralRules(
+	locale language.Tag,
+) (cardinal, ordinal PluralRules) {
+	r.lock.RLock()
+	p, ok := r.byTag[locale]
+	r.lock.RUnlock()
+	if ok {
+		return p[0], p[1]
+	}
+	return pluralRules(r.fallback, locale)
+}
+
+// pluralRules returns plural rules for locale using p,
+// or CLDRPluralRules if p is nil.
+func pluralRules(p PluralRuleProvider, locale language.Tag) (cardinal, ordinal PluralRules) {
+	if p == nil {
+		return CLDRPluralRules{}.PluralRules(locale)
+	}
+	return p.PluralRules(locale)
+}
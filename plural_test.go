@@ -0,0 +1,145 @@
+package icumsg_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestCLDRPluralRules(t *testing.T) {
+	cardinal, ordinal := icumsg.CLDRPluralRules{}.PluralRules(language.English)
+	test.RequireEqual(t, icumsg.PluralRules{One: true, Other: true}, cardinal)
+	test.RequireEqual(t,
+		icumsg.PluralRules{One: true, Two: true, Few: true, Other: true}, ordinal)
+
+	// Falls back to base.
+	cardinal, ordinal = icumsg.CLDRPluralRules{}.PluralRules(language.MustParse("fr-HT"))
+	test.RequireEqual(t, icumsg.PluralRules{One: true, Many: true, Other: true}, cardinal)
+	test.RequireEqual(t, icumsg.PluralRules{One: true, Other: true}, ordinal)
+}
+
+func TestPluralRuleRegistry(t *testing.T) {
+	pirate := language.MustParse("x-pirate")
+	enKids := language.MustParse("en-x-kids")
+
+	r := icumsg.NewPluralRuleRegistry(nil)
+	r.Register(pirate,
+		icumsg.PluralRules{Zero: true, Many: true},
+		icumsg.PluralRules{Other: true})
+	r.Register(enKids,
+		icumsg.PluralRules{One: true, Two: true, Other: true},
+		icumsg.PluralRules{Other: true})
+
+	cardinal, ordinal := r.PluralRules(pirate)
+	test.RequireEqual(t,
+		icumsg.PluralRules{Zero: true, Many: true, Other: true}, cardinal)
+	test.RequireEqual(t, icumsg.PluralRules{Other: true}, ordinal)
+
+	// Fallback to CLDR.
+	cardinal, ordinal = r.PluralRules(language.German)
+	test.RequireEqual(t, icumsg.PluralRules{One: true, Other: true}, cardinal)
+	test.RequireEqual(t, icumsg.PluralRules{Other: true}, ordinal)
+
+	// Override CLDR.
+	r.Register(language.German,
+		icumsg.PluralRules{One: true, Few: true, Other: true},
+		icumsg.PluralRules{Other: true})
+	cardinal, _ = r.PluralRules(language.German)
+	test.RequireEqual(t, icumsg.PluralRules{One: true, Few: true, Other: true}, cardinal)
+
+	var tokenizer icumsg.Tokenizer
+	tokenizer.Plurals = r
+
+	_, err := tokenizer.Tokenize(pirate, nil, "{n, plural, zero{a} many{b} other{c}}")
+	test.RequireNoErr(t, err)
+
+	_, err = tokenizer.Tokenize(pirate, nil, "{n, plural, one{a} other{c}}")
+	test.RequireErrIs(t, icumsg.ErrUnsupportedPluralRule, err)
+	test.RequireEqual(t, 12, tokenizer.Pos())
+
+	_, err = tokenizer.Tokenize(enKids, nil, "{n, plural, one{a} two{b} other{c}}")
+	test.RequireNoErr(t, err)
+
+	// Without the registry en-x-kids falls back to "en".
+	_, err = (&icumsg.Tokenizer{}).Tokenize(
+		enKids, nil, "{n, plural, one{a} two{b} other{c}}",
+	)
+	test.RequireErrIs(t, icumsg.ErrUnsupportedPluralRule, err)
+}
+
+func TestPluralRuleRegistryZeroValue(t *testing.T) {
+	var r icumsg.PluralRuleRegistry
+
+	// Falls back to CLDR.
+	cardinal, _ := r.PluralRules(language.English)
+	test.RequireEqual(t, icumsg.PluralRules{One: true, Other: true}, cardinal)
+
+	pirate := language.MustParse("x-pirate")
+	r.Register(pirate, icumsg.PluralRules{Zero: true}, icumsg.PluralRules{})
+	cardinal, ordinal := r.PluralRules(pirate)
+	test.RequireEqual(t, icumsg.PluralRules{Zero: true, Other: true}, cardinal)
+	test.RequireEqual(t, icumsg.PluralRules{Other: true}, ordinal)
+}
+
+// countingPlurals counts the plural rule lookups.
+type countingPlurals struct{ calls int }
+
+func (p *countingPlurals) PluralRules(
+	locale language.Tag,
+) (cardinal, ordinal icumsg.PluralRules) {
+	p.calls++
+	return icumsg.CLDRPluralRules{}.PluralRules(locale)
+}
+
+func TestTokenizerPluralRulesLookup(t *testing.T) {
+	f := func(t *testing.T, input string, expectCalls int) {
+		t.Helper()
+		var p countingPlurals
+		tokenizer := icumsg.Tokenizer{Plurals: &p}
+		_, err := tokenizer.Tokenize(language.English, nil, input)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expectCalls, p.calls)
+	}
+
+	f(t, "", 0)
+	f(t, "Hello", 0)
+	f(t, "Hello {name}, {g, select, other {{n, number}}}", 0)
+	f(t, "{n, plural, one{#} other{#}}", 1)
+	f(t, "{n, plural, one{#} other{{m, selectordinal, one{#st} other{#th}}}}", 1)
+}
+
+func TestCompletenessPluralRuleProvider(t *testing.T) {
+	pirate := language.MustParse("x-pirate")
+	r := icumsg.NewPluralRuleRegistry(nil)
+	r.Register(pirate,
+		icumsg.PluralRules{Zero: true, Many: true},
+		icumsg.PluralRules{One: true})
+
+	tokenizer := icumsg.Tokenizer{Plurals: r}
+
+	fn := func(t *testing.T, input string, expectIncomplete []string) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(pirate, nil, input)
+		test.RequireNoErr(t, err)
+		var incomplete []string
+		total := tokenizer.Completeness(input, buffer, pirate,
+			func(string) ([]string, icumsg.OptionsPresencePolicy, icumsg.OptionUnknownPolicy) {
+				return nil, 0, 0
+			},
+			func(index int) {
+				incomplete = append(incomplete, buffer[index].String(input, buffer))
+			},
+			func(int) {})
+		test.RequireEqual(t, 1, total)
+		test.RequireDeepEqual(t, expectIncomplete, incomplete)
+	}
+
+	fn(t, "{n, plural, zero{a} many{b} other{c}}", nil)
+	fn(t, "{n, plural, many{b} other{c}}",
+		[]string{"{n, plural, many{b} other{c}}"})
+	fn(t, "{n, selectordinal, one{a} other{c}}", nil)
+	fn(t, "{n, selectordinal, other{c}}",
+		[]string{"{n, selectordinal, other{c}}"})
+}
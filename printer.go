@@ -0,0 +1,180 @@
+package icumsg
+
+import (
+	"iter"
+	"strings"
+)
+
+// Format prints the ICU message tokenized into buffer in canonical form.
+// Literals are printed as is, while whitespace between arguments, types,
+// styles and options is normalized:
+//
+//	{name}
+//	{name, type}
+//	{name, type, style}
+//	{name, plural, offset:1 =0 {...} one {...} other {...}}
+func Format(src string, buffer []Token) string {
+	p := printer{src: src, buffer: buffer}
+	p.print(0, len(buffer))
+	return p.b.String()
+}
+
+// EscapeLiteral returns s escaped for use as a literal in an ICU message.
+// Apostrophes are doubled and '{', '}' and '#' are quoted.
+func EscapeLiteral(s string) string {
+	if strings.IndexAny(s, "'{}#") == -1 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 4)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			b.WriteString("''")
+		case '{', '}', '#':
+			b.WriteByte('\'')
+			b.WriteByte(s[i])
+			b.WriteByte('\'')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// UnescapeLiteral returns the literal l of a message with quoting removed,
+// the inverse of EscapeLiteral. Unquoted '#' is kept as is,
+// see LiteralSegments for literals in plural and selectordinal options.
+func UnescapeLiteral(l string) string {
+	if strings.IndexByte(l, '\'') == -1 {
+		return l
+	}
+	for text := range LiteralSegments(l, false) {
+		return text
+	}
+	return ""
+}
+
+// LiteralSegments returns an iterator over the unescaped text of the
+// literal l of a message. If hash is true, l is split at every unquoted
+// '#', which stands for the number of the enclosing plural or
+// selectordinal argument: the text preceding each '#' is yielded with
+// true and the text following the last one with false.
+// If hash is false, l is yielded as a single segment with '#' kept as is.
+// Segments may be empty.
+func LiteralSegments(l string, hash bool) iter.Seq2[string, bool] {
+	return func(yield func(string, bool) bool) {
+		var b []byte // Unescaped text of the segment if unquoted is true.
+		unquoted, inQuote, start := false, false, 0
+		text := func(end int) string {
+			if unquoted {
+				return string(b)
+			}
+			return l[start:end]
+		}
+		for i := 0; i < len(l); i++ {
+			switch c := l[i]; {
+			case c == '\'':
+				if !unquoted {
+					b, unquoted = append(b[:0], l[start:i]...), true
+				}
+				if i+1 < len(l) && l[i+1] == '\'' {
+					b = append(b, '\'')
+					i++
+				} else {
+					inQuote = !inQuote
+				}
+			case c == '#' && hash && !inQuote:
+				if !yield(text(i), true) {
+					return
+				}
+				unquoted, start = false, i+1
+			case unquoted:
+				b = append(b, c)
+			}
+		}
+		yield(text(len(l)), false)
+	}
+}
+
+// printer prints token buffers in canonical form.
+type printer struct {
+	src    string
+	buffer []Token
+	b      strings.Builder
+
+	// skipOption is optional and when it returns true the option
+	// at buffer[optionIndex] of the choice at buffer[choiceIndex] is not printed.
+	skipOption func(choiceIndex, optionIndex int) bool
+
+	// afterOptions is optional and invoked after all options of the choice
+	// at buffer[index] were printed, before the closing bracket.
+	afterOptions func(index int)
+}
+
+// print prints buffer[start:end].
+func (p *printer) print(start, end int) {
+	for i := start; i < end; i++ {
+		t := p.buffer[i]
+		switch t.Type {
+		case TokenTypeLiteral:
+			p.b.WriteString(p.src[t.IndexStart:t.IndexEnd])
+		case TokenTypeSimpleArg:
+			p.b.WriteByte('{')
+			p.b.WriteString(p.buffer[i+1].String(p.src, p.buffer))
+			i += 2 // Skip the argument name.
+			for ; i < end && p.buffer[i].Type >= TokenTypeArgTypeNumber &&
+				p.buffer[i].Type <= TokenTypeArgStyleSkeleton; i++ {
+				// Argument type and style.
+				p.b.WriteString(", ")
+				p.b.WriteString(p.buffer[i].String(p.src, p.buffer))
+			}
+			i-- // Compensate the loop increment.
+			p.b.WriteByte('}')
+		case TokenTypePlural, TokenTypeSelect, TokenTypeSelectOrdinal:
+			p.printChoice(i)
+			i = t.IndexEnd // Skip contents.
+		}
+	}
+}
+
+func (p *printer) printChoice(index int) {
+	t := p.buffer[index]
+	p.b.WriteByte('{')
+	p.b.WriteString(p.buffer[index+1].String(p.src, p.buffer))
+	switch t.Type {
+	case TokenTypePlural:
+		p.b.WriteString(", plural,")
+	case TokenTypeSelect:
+		p.b.WriteString(", select,")
+	case TokenTypeSelectOrdinal:
+		p.b.WriteString(", selectordinal,")
+	}
+	if o := p.buffer[index+2]; o.Type == TokenTypePluralOffset {
+		p.b.WriteString(" offset:")
+		p.b.WriteString(p.src[o.IndexStart:o.IndexEnd])
+	}
+	for j := range Options(p.buffer, index) {
+		if p.skipOption != nil && p.skipOption(index, j) {
+			continue
+		}
+		p.b.WriteByte(' ')
+		p.b.WriteString(OptionName(p.src, p.buffer, j))
+		p.b.WriteString(" {")
+		p.printOptionContents(j)
+		p.b.WriteByte('}')
+	}
+	if p.afterOptions != nil {
+		p.afterOptions(index)
+	}
+	p.b.WriteByte('}')
+}
+
+// printOptionContents prints the contents of the option at buffer[index].
+func (p *printer) printOptionContents(index int) {
+	start := index + 1
+	if t := p.buffer[index].Type; t == TokenTypeOption || t == TokenTypeOptionNumber {
+		start++ // Skip the option name.
+	}
+	p.print(start, p.buffer[index].IndexEnd)
+}
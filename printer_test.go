@@ -0,0 +1,128 @@
+package icumsg_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+	"golang.org/x/text/language"
+)
+
+func TestFormat(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+
+	f := func(t *testing.T, expect, input string) {
+		t.Helper()
+		buffer, err := tokenizer.Tokenize(language.English, nil, input)
+		test.RequireNoErr(t, err)
+		actual := icumsg.Format(input, buffer)
+		test.RequireEqual(t, expect, actual)
+
+		// Formatting must be idempotent.
+		buffer, err = tokenizer.Tokenize(language.English, nil, actual)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, expect, icumsg.Format(actual, buffer))
+	}
+
+	f(t, "", "")
+	f(t, "Literal only", "Literal only")
+	f(t, "Escaped '{' and '' kept as is", "Escaped '{' and '' kept as is")
+	f(t, "Hello {name}!", "Hello {  name\t}!")
+	f(t, "{n, number}", "{n,number}")
+	f(t, "{n, number, integer}", "{ n ,number ,  integer }")
+	f(t, "{n, number, ::currency/EUR}", "{n,number,::currency/EUR}")
+	f(t, "{d, date, short} {t, time, custom}", "{d,date,short} {t,time,custom}")
+	f(t, "{g, select, male {he} female {she} other {they}}",
+		"{g,select,male{he}female{she}other{they}}")
+	f(t, "{n, plural, offset:1 =0 {none} one {# item} other {# items}}",
+		`{n, plural, offset: 1
+			=0 {none}
+			one {# item}
+			other {# items}
+		}`)
+	f(t, "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+		"{n,selectordinal,one{#st}two{#nd}few{#rd}other{#th}}")
+	// The tokenizer drops leading whitespace in select options.
+	f(t, "{g, select, other {nested {n, plural, other {# {x}}} }}",
+		"{g,select,other{ nested {n,plural,other{# {x}}} }}")
+}
+
+func TestFormatTestdata(t *testing.T) {
+	var tokenizer icumsg.Tokenizer
+	for _, fileName := range [...]string{
+		"testdata/lorem_ipsum.txt",
+		"testdata/lorem_ipsum_args.icu.txt",
+		"testdata/nested.icu.txt",
+	} {
+		input := ReadFile[string](t, fileName)
+		buffer, err := tokenizer.Tokenize(language.English, nil, input)
+		test.RequireNoErr(t, err)
+		formatted := icumsg.Format(input, buffer)
+		formattedBuffer, err := tokenizer.Tokenize(language.English, nil, formatted)
+		test.RequireNoErr(t, err)
+		test.RequireEqual(t, len(buffer), len(formattedBuffer))
+		for i := range buffer {
+			test.RequireEqual(t, buffer[i].Type, formattedBuffer[i].Type)
+		}
+	}
+}
+
+func TestEscapeLiteral(t *testing.T) {
+	f := func(t *testing.T, expect, input string) {
+		t.Helper()
+		test.RequireEqual(t, expect, icumsg.EscapeLiteral(input))
+
+		// Escaped literals must tokenize to a single literal.
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, expect)
+		test.RequireNoErr(t, err)
+		if input != "" {
+			test.RequireEqual(t, 1, len(buffer))
+			test.RequireEqual(t, icumsg.TokenTypeLiteral, buffer[0].Type)
+		}
+	}
+
+	f(t, "", "")
+	f(t, "TODO: ", "TODO: ")
+	f(t, "it''s", "it's")
+	f(t, "'{'x'}'", "{x}")
+	f(t, "'#' '''{'", "# '{")
+}
+
+func TestUnescapeLiteral(t *testing.T) {
+	f := func(t *testing.T, expect, input string) {
+		t.Helper()
+		test.RequireEqual(t, expect, icumsg.UnescapeLiteral(input))
+		test.RequireEqual(t, input, icumsg.UnescapeLiteral(icumsg.EscapeLiteral(input)))
+	}
+
+	f(t, "", "")
+	f(t, "TODO: ", "TODO: ")
+	f(t, "it's", "it''s")
+	f(t, "{x}", "'{x}'")
+	f(t, "# '{", "'#' '''{'")
+	f(t, "# #", "# #")
+}
+
+func TestLiteralSegments(t *testing.T) {
+	type segment struct {
+		Text string
+		Hash bool
+	}
+	f := func(t *testing.T, input string, hash bool, expect ...segment) {
+		t.Helper()
+		var actual []segment
+		for text, hash := range icumsg.LiteralSegments(input, hash) {
+			actual = append(actual, segment{text, hash})
+		}
+		test.RequireDeepEqual(t, expect, actual)
+	}
+
+	f(t, "", true, segment{"", false})
+	f(t, "# items", false, segment{"# items", false})
+	f(t, "# items", true, segment{"", true}, segment{" items", false})
+	f(t, "it''s #", true, segment{"it's ", true}, segment{"", false})
+	f(t, "'#' # '#'", true, segment{"# ", true}, segment{" #", false})
+	f(t, "a#b'{'#", true,
+		segment{"a", true}, segment{"b{", true}, segment{"", false})
+}
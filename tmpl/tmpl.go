@@ -0,0 +1,120 @@
+// Package tmpl provides functions formatting ICU messages in
+// text/template and html/template templates.
+package tmpl
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"slices"
+	"text/template"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/format"
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrNotFound = errors.New("message not found")
+
+	// ErrArguments is returned if the number of positional arguments
+	// doesn't match the arguments of the message.
+	ErrArguments = errors.New("wrong number of arguments")
+)
+
+// Source provides messages, *catalog.Catalog implements it.
+type Source interface {
+	// Source returns the source locale that defines the order
+	// of positional arguments.
+	Source() language.Tag
+
+	// Lookup returns the message of locale or of its fallbacks.
+	Lookup(locale language.Tag, id string) (*catalog.Entry, bool)
+}
+
+// Funcs returns functions for text/template bound to locale and src.
+// Function "icu" formats the message with the given ID:
+//
+//	{{ icu "inbox.count" .Count .Gender }}
+//
+// Arguments are passed either as a single map[string]any or positionally
+// in the order of first appearance in the message of the source locale.
+// In html/template, the results are escaped entirely, see HTMLFuncs
+// for messages with markup.
+func Funcs(locale language.Tag, src Source) template.FuncMap {
+	f := format.Formatter{}
+	return template.FuncMap{
+		"icu": func(id string, args ...any) (string, error) {
+			return formatMessage(f, locale, src, id, args)
+		},
+	}
+}
+
+// HTMLFuncs returns functions for html/template bound to locale and src
+// like Funcs but "icu" returns htmltemplate.HTML. Argument values are
+// always escaped, while the literal text of messages is escaped unless
+// allowMarkup is true, in which case markup written by translators
+// is rendered as is.
+func HTMLFuncs(locale language.Tag, src Source, allowMarkup bool) template.FuncMap {
+	f := format.Formatter{EscapeArgument: html.EscapeString}
+	if !allowMarkup {
+		f.EscapeLiteral = html.EscapeString
+	}
+	return template.FuncMap{
+		"icu": func(id string, args ...any) (htmltemplate.HTML, error) {
+			s, err := formatMessage(f, locale, src, id, args)
+			return htmltemplate.HTML(s), err
+		},
+	}
+}
+
+func formatMessage(
+	f format.Formatter, locale language.Tag, src Source, id string, args []any,
+) (string, error) {
+	e, ok := src.Lookup(locale, id)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	named, ok := mapArgument(args)
+	if !ok {
+		var err error
+		if named, err = positional(src, id, args); err != nil {
+			return "", err
+		}
+	}
+	f.Locale = e.Locale
+	return f.Format(e.Message, e.Tokens, named)
+}
+
+func mapArgument(args []any) (map[string]any, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	m, ok := args[0].(map[string]any)
+	return m, ok
+}
+
+// positional maps args to the arguments of the source message.
+func positional(src Source, id string, args []any) (map[string]any, error) {
+	e, ok := src.Lookup(src.Source(), id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q in source locale %s", ErrNotFound, id, src.Source())
+	}
+	var names []string
+	for a := range icumsg.Arguments(e.Message, e.Tokens) {
+		if !slices.Contains(names, a.Name) {
+			names = append(names, a.Name)
+		}
+	}
+	if len(args) != len(names) {
+		return nil, fmt.Errorf("%w: %q expects %d, got %d",
+			ErrArguments, id, len(names), len(args))
+	}
+	named := make(map[string]any, len(names))
+	for i, name := range names {
+		named[name] = args[i]
+	}
+	return named, nil
+}
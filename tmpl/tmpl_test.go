@@ -0,0 +1,85 @@
+package tmpl_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/tmpl"
+	"golang.org/x/text/language"
+)
+
+func newCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	c := catalog.New(language.English, nil)
+	for _, m := range []struct {
+		Locale      language.Tag
+		ID, Message string
+	}{
+		{language.English, "inbox", "{g, select, female {She has} other {They have}} " +
+			"{n, plural, one {# message} other {# messages}}"},
+		{language.German, "inbox", "{n, plural, one {# Nachricht} other {# Nachrichten}} " +
+			"{g, select, female {hat sie} other {haben sie}}"},
+		{language.English, "hello", "<b>Hello</b> {name}!"},
+	} {
+		test.RequireNoErr(t, c.Add(m.Locale, m.ID, m.Message))
+	}
+	return c
+}
+
+func TestFuncs(t *testing.T) {
+	c := newCatalog(t)
+	f := func(t *testing.T, locale language.Tag, text, expect string, data any) {
+		t.Helper()
+		tp, err := template.New("").Funcs(tmpl.Funcs(locale, c)).Parse(text)
+		test.RequireNoErr(t, err)
+		var b strings.Builder
+		test.RequireNoErr(t, tp.Execute(&b, data))
+		test.RequireEqual(t, expect, b.String())
+	}
+
+	data := map[string]any{"Count": 1500, "Gender": "female"}
+	f(t, language.English, `{{ icu "inbox" .Gender .Count }}`, "She has 1,500 messages", data)
+	// Positional arguments follow the source message.
+	f(t, language.German, `{{ icu "inbox" .Gender .Count }}`, "1.500 Nachrichten hat sie", data)
+	f(t, language.English, `{{ icu "inbox" (.) }}`, "They have 1 message",
+		map[string]any{"n": 1, "g": "x"})
+	// Messages fall back to the source locale.
+	f(t, language.German, `{{ icu "hello" "<i>" }}`, "<b>Hello</b> <i>!", nil)
+}
+
+func TestHTMLFuncs(t *testing.T) {
+	c := newCatalog(t)
+	f := func(t *testing.T, funcs htmltemplate.FuncMap, expect string) {
+		t.Helper()
+		tp, err := htmltemplate.New("").Funcs(funcs).Parse(`<p>{{ icu "hello" . }}</p>`)
+		test.RequireNoErr(t, err)
+		var b strings.Builder
+		test.RequireNoErr(t, tp.Execute(&b, "<script>"))
+		test.RequireEqual(t, expect, b.String())
+	}
+
+	f(t, tmpl.HTMLFuncs(language.English, c, true),
+		"<p><b>Hello</b> &lt;script&gt;!</p>")
+	f(t, tmpl.HTMLFuncs(language.English, c, false),
+		"<p>&lt;b&gt;Hello&lt;/b&gt; &lt;script&gt;!</p>")
+	f(t, tmpl.Funcs(language.English, c),
+		"<p>&lt;b&gt;Hello&lt;/b&gt; &lt;script&gt;!</p>")
+}
+
+func TestFuncsErr(t *testing.T) {
+	c := newCatalog(t)
+	f := func(t *testing.T, expect error, text string) {
+		t.Helper()
+		tp, err := template.New("").Funcs(tmpl.Funcs(language.English, c)).Parse(text)
+		test.RequireNoErr(t, err)
+		err = tp.Execute(&strings.Builder{}, nil)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, tmpl.ErrNotFound, `{{ icu "missing" }}`)
+	f(t, tmpl.ErrArguments, `{{ icu "inbox" 1 }}`)
+}
@@ -0,0 +1,204 @@
+package icumsg
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidBuffer is returned by ValidateBuffer for malformed token buffers.
+var ErrInvalidBuffer = errors.New("invalid token buffer")
+
+// ValidateBuffer checks that buffer is a well-formed token buffer of src,
+// such as a buffer loaded from a cache or produced by other means than
+// Tokenizer, before it's passed to functions that trust its indexes
+// like Token.String, Options and Completeness. It verifies that:
+//
+//   - byte offsets are within src and ranges aren't reversed,
+//     including the range from a complex token to its terminator
+//   - complex tokens point to a terminator of the correct type that
+//     points back at them
+//   - options and their contents are nested within their argument
+//   - argument names follow argument tokens, argument types follow
+//     argument names and argument styles follow argument types
+//   - options of select arguments are select options, options of
+//     plural and selectordinal arguments are plural options and
+//     option names follow the options that have them
+//
+// The returned error wraps ErrInvalidBuffer and names the index of the
+// first offending token. The syntax of src isn't checked again.
+func ValidateBuffer(src string, buffer []Token) error {
+	v := validator{src: src, buffer: buffer}
+	return v.body(0, len(buffer))
+}
+
+type validator struct {
+	src    string
+	buffer []Token
+}
+
+func (v *validator) errorf(index int, format string, args ...any) error {
+	if index >= len(v.buffer) {
+		return fmt.Errorf("%w: %d: %s", ErrInvalidBuffer, index, fmt.Sprintf(format, args...))
+	}
+	return fmt.Errorf("%w: %d (%s): %s", ErrInvalidBuffer, index, v.buffer[index].Type,
+		fmt.Sprintf(format, args...))
+}
+
+// offset checks that o is a byte offset in src.
+func (v *validator) offset(index, o int) error {
+	if o < 0 || o > len(v.src) {
+		return v.errorf(index, "offset %d out of range [0:%d]", o, len(v.src))
+	}
+	return nil
+}
+
+// span checks that the token at index is a range of src.
+func (v *validator) span(index int) error {
+	t := v.buffer[index]
+	if err := v.offset(index, t.IndexStart); err != nil {
+		return err
+	}
+	if err := v.offset(index, t.IndexEnd); err != nil {
+		return err
+	}
+	if t.IndexStart > t.IndexEnd {
+		return v.errorf(index, "reversed range [%d:%d]", t.IndexStart, t.IndexEnd)
+	}
+	return nil
+}
+
+// expect checks that the token at index is a range of src of type t
+// and before end.
+func (v *validator) expect(index, end int, t TokenType) error {
+	if index >= end {
+		return v.errorf(index, "expected %s", t)
+	}
+	if v.buffer[index].Type != t {
+		return v.errorf(index, "expected %s", t)
+	}
+	return v.span(index)
+}
+
+// terminator checks that the complex token at index points to
+// a terminator of type t before end that points back at it and
+// returns the index of the terminator.
+func (v *validator) terminator(index, end int, t TokenType) (int, error) {
+	if err := v.offset(index, v.buffer[index].IndexStart); err != nil {
+		return 0, err
+	}
+	term := v.buffer[index].IndexEnd
+	if term <= index || term >= end {
+		return 0, v.errorf(index, "terminator index %d out of range (%d:%d)", term, index, end)
+	}
+	if v.buffer[term].Type != t {
+		return 0, v.errorf(index, "terminator %d is %s instead of %s", term, v.buffer[term].Type, t)
+	}
+	if v.buffer[term].IndexStart != index {
+		return 0, v.errorf(term, "points to %d instead of %d", v.buffer[term].IndexStart, index)
+	}
+	if err := v.offset(term, v.buffer[term].IndexEnd); err != nil {
+		return 0, err
+	}
+	if v.buffer[index].IndexStart > v.buffer[term].IndexEnd {
+		return 0, v.errorf(index, "reversed range [%d:%d]",
+			v.buffer[index].IndexStart, v.buffer[term].IndexEnd)
+	}
+	return term, nil
+}
+
+// body checks the tokens of buffer[start:end].
+func (v *validator) body(start, end int) error {
+	for i := start; i < end; {
+		var err error
+		switch v.buffer[i].Type {
+		case TokenTypeLiteral:
+			err = v.span(i)
+			i++
+		case TokenTypeSimpleArg:
+			i, err = v.simpleArg(i, end)
+		case TokenTypePlural, TokenTypeSelect, TokenTypeSelectOrdinal:
+			i, err = v.choice(i, end)
+		default:
+			err = v.errorf(i, "unexpected token")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// simpleArg checks the simple argument at index and returns the index
+// of the token following it.
+func (v *validator) simpleArg(index, end int) (int, error) {
+	if err := v.span(index); err != nil {
+		return 0, err
+	}
+	i := index + 1
+	if err := v.expect(i, end, TokenTypeArgName); err != nil {
+		return 0, err
+	}
+	i++
+	if i < end && v.buffer[i].Type >= TokenTypeArgTypeNumber &&
+		v.buffer[i].Type <= TokenTypeArgTypeDuration {
+		if err := v.span(i); err != nil {
+			return 0, err
+		}
+		i++
+		if i < end && v.buffer[i].Type >= TokenTypeArgStyleShort &&
+			v.buffer[i].Type <= TokenTypeArgStyleSkeleton {
+			if err := v.span(i); err != nil {
+				return 0, err
+			}
+			i++
+		}
+	}
+	return i, nil
+}
+
+// choice checks the select, plural or selectordinal argument at index
+// and returns the index of the token following its terminator.
+func (v *validator) choice(index, end int) (int, error) {
+	t := v.buffer[index].Type
+	term, err := v.terminator(index, end, TokenTypeComplexArgTerm)
+	if err != nil {
+		return 0, err
+	}
+	i := index + 1
+	if err := v.expect(i, term, TokenTypeArgName); err != nil {
+		return 0, err
+	}
+	i++
+	if t == TokenTypePlural && i < term && v.buffer[i].Type == TokenTypePluralOffset {
+		if err := v.span(i); err != nil {
+			return 0, err
+		}
+		i++
+	}
+	if i == term {
+		return 0, v.errorf(index, "no options")
+	}
+	for i < term {
+		o := v.buffer[i].Type
+		if t == TokenTypeSelect && o != TokenTypeOption && o != TokenTypeOptionOther ||
+			t != TokenTypeSelect && (o < TokenTypeOptionZero || o > TokenTypeOptionNumber) {
+			return 0, v.errorf(i, "unexpected token in %s", t)
+		}
+		optionTerm, err := v.terminator(i, term, TokenTypeOptionTerm)
+		if err != nil {
+			return 0, err
+		}
+		bodyStart := i + 1
+		if o == TokenTypeOption || o == TokenTypeOptionNumber {
+			if err := v.expect(bodyStart, optionTerm, TokenTypeOptionName); err != nil {
+				return 0, err
+			}
+			bodyStart++
+		}
+		if err := v.body(bodyStart, optionTerm); err != nil {
+			return 0, err
+		}
+		i = optionTerm + 1
+	}
+	return term + 1, nil
+}
@@ -0,0 +1,144 @@
+package icumsg_test
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/test"
+)
+
+func TestValidateBuffer(t *testing.T) {
+	f := func(t *testing.T, src string) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		test.RequireNoErr(t, icumsg.ValidateBuffer(src, buffer))
+	}
+
+	f(t, "")
+	f(t, "Hello")
+	f(t, "It''s '{'quoted'}'")
+	f(t, "Hello {name}!")
+	f(t, "{n, number} {n, number, integer} {n, number, ::currency/EUR}")
+	f(t, "{d, date, short} {d, time} {d, date, yyyy} {s, duration}")
+	f(t, "{g, select, female {She} male {He} other {They}}")
+	f(t, "{n, plural, offset:1 =0 {nobody} =1 {{name}} one {# other} other {# others}}")
+	f(t, "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}")
+	f(t, "{g, select, other {{n, plural, one {{x} #} other {{y, select, other {#}}}}}}")
+	f(t, ReadFile[string](t, "testdata/lorem_ipsum_args.icu.txt"))
+	f(t, ReadFile[string](t, "testdata/nested.icu.txt"))
+}
+
+func TestValidateBufferErr(t *testing.T) {
+	// f tokenizes src, corrupts the buffer with corrupt and expects
+	// ValidateBuffer to fail.
+	f := func(t *testing.T, src string, corrupt func(b []icumsg.Token) []icumsg.Token) {
+		t.Helper()
+		var tokenizer icumsg.Tokenizer
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		test.RequireNoErr(t, err)
+		err = icumsg.ValidateBuffer(src, corrupt(slices.Clone(buffer)))
+		test.RequireErrIs(t, icumsg.ErrInvalidBuffer, err)
+	}
+
+	const simple = "Hello {name}, {n, number, integer}"
+	// Ranges.
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[0].IndexEnd = 100; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[0].IndexStart = -1; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[0].IndexStart = 5; b[0].IndexEnd = 1; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[2].IndexEnd = 100; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[len(b)-1].IndexEnd = 100; return b })
+	// Unknown and misplaced tokens.
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[0].Type = 0; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[0].Type = 0xff; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[0].Type = icumsg.TokenTypeArgName; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token {
+		b[0].Type = icumsg.TokenTypeOptionTerm
+		return b
+	})
+	// Argument names must follow arguments.
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { return b[:2] })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { b[2].Type = icumsg.TokenTypeLiteral; return b })
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { return slices.Delete(b, 5, 6) })
+	// Styles must follow types.
+	f(t, simple, func(b []icumsg.Token) []icumsg.Token { return slices.Delete(b, 6, 7) })
+
+	const plural = "{n, plural, offset:1 =0 {none} one {# {x}} other {#}}"
+	// Links between complex tokens and terminators.
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[0].IndexEnd--; return b })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[0].IndexEnd = 0; return b })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[0].IndexEnd = 100; return b })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[len(b)-1].IndexStart = 1; return b })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token {
+		b[len(b)-1].Type = icumsg.TokenTypeOptionTerm
+		return b
+	})
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[3].IndexEnd = len(b) - 1; return b })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { return b[:len(b)-1] })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[3].IndexStart = len(plural); return b })
+	// Nesting.
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token {
+		// Option body extends beyond the following option.
+		b[3].IndexEnd, b[b[3].IndexEnd].Type = b[7].IndexEnd, icumsg.TokenTypeLiteral
+		b[b[7].IndexEnd].IndexStart = 3
+		return b
+	})
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { return slices.Delete(b, 1, 2) })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token {
+		b[2].Type = icumsg.TokenTypeLiteral // Offset within the argument.
+		return b
+	})
+	// Option types.
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[3].Type = icumsg.TokenTypeOption; return b })
+	f(t, plural, func(b []icumsg.Token) []icumsg.Token { b[4].Type = icumsg.TokenTypeLiteral; return b })
+
+	const sel = "{g, select, a {A} other {O}}"
+	f(t, sel, func(b []icumsg.Token) []icumsg.Token { b[2].Type = icumsg.TokenTypeOptionOne; return b })
+	f(t, sel, func(b []icumsg.Token) []icumsg.Token { return slices.Delete(b, 3, 4) })
+	f(t, sel, func(b []icumsg.Token) []icumsg.Token {
+		// No options.
+		b[0].IndexEnd, b[1].Type = 2, icumsg.TokenTypeArgName
+		b = append(b[:2], b[len(b)-1])
+		b[2].IndexStart = 0
+		return b
+	})
+}
+
+// FuzzValidateBuffer corrupts a token of a valid buffer and checks that
+// functions trusting the buffer don't panic if it's considered valid.
+func FuzzValidateBuffer(f *testing.F) {
+	f.Add("Hello {name}!", uint8(1), uint8(1), 5)
+	f.Add("{n, plural, offset:1 =0 {none} other {# {x}}}", uint8(3), uint8(2), 7)
+	f.Add("{g, select, a {A} other {O}}", uint8(5), uint8(0), 12)
+
+	var tokenizer icumsg.Tokenizer
+	f.Fuzz(func(t *testing.T, src string, index, field uint8, value int) {
+		buffer, err := tokenizer.Tokenize(language.English, nil, src)
+		if err != nil || len(buffer) == 0 {
+			return
+		}
+		tk := &buffer[int(index)%len(buffer)]
+		switch field % 3 {
+		case 0:
+			tk.Type = icumsg.TokenType(value)
+		case 1:
+			tk.IndexStart = value
+		case 2:
+			tk.IndexEnd = value
+		}
+		if icumsg.ValidateBuffer(src, buffer) != nil {
+			return
+		}
+		for i, tk := range buffer {
+			_ = tk.String(src, buffer)
+			for range icumsg.Options(buffer, i) {
+			}
+		}
+		for range icumsg.Arguments(src, buffer) {
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package xliff
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/romshark/icumsg"
+)
+
+type partKind uint8
+
+const (
+	_ partKind = iota
+	partText
+	partPlaceholder // A standalone code such as "{name}" or "#".
+	partStart       // The start of a paired code such as "{n, plural," or " one {".
+	partEnd         // The end of a paired code.
+)
+
+// part is a piece of inline content.
+type part struct {
+	kind partKind
+
+	// text is either the text or the native data of the code.
+	text string
+
+	// end is the native data of the matching partEnd for partStart.
+	end string
+}
+
+// split splits the ICU message src tokenized into buffer into text and
+// codes. Simple arguments and "#" in plural options become placeholders,
+// complex arguments and their options become paired codes enclosing
+// the translatable option contents. Literals are unescaped.
+func split(src string, buffer []icumsg.Token) []part {
+	s := splitter{src: src, buffer: buffer}
+	s.split(0, len(buffer), false)
+	return s.parts
+}
+
+type splitter struct {
+	src    string
+	buffer []icumsg.Token
+	parts  []part
+}
+
+// split splits buffer[start:end]. hash is true inside
+// plural and selectordinal options where "#" is a placeholder.
+func (s *splitter) split(start, end int, hash bool) {
+	for i := start; i < end; i++ {
+		t := s.buffer[i]
+		switch t.Type {
+		case icumsg.TokenTypeLiteral:
+			s.literal(s.src[t.IndexStart:t.IndexEnd], hash)
+		case icumsg.TokenTypeSimpleArg:
+			s.parts = append(s.parts, part{
+				kind: partPlaceholder, text: s.src[t.IndexStart:t.IndexEnd],
+			})
+			// Skip the argument name, type and style.
+			i = icumsg.SimpleArgEnd(s.buffer, i)
+		case icumsg.TokenTypePlural, icumsg.TokenTypeSelect, icumsg.TokenTypeSelectOrdinal:
+			s.choice(i, hash)
+			i = t.IndexEnd // Skip contents.
+		}
+	}
+}
+
+func (s *splitter) choice(index int, hash bool) {
+	var header strings.Builder
+	header.WriteByte('{')
+	header.WriteString(s.buffer[index+1].String(s.src, s.buffer))
+	switch s.buffer[index].Type {
+	case icumsg.TokenTypePlural:
+		header.WriteString(", plural,")
+		hash = true
+	case icumsg.TokenTypeSelect:
+		header.WriteString(", select,")
+	case icumsg.TokenTypeSelectOrdinal:
+		header.WriteString(", selectordinal,")
+		hash = true
+	}
+	if o := s.buffer[index+2]; o.Type == icumsg.TokenTypePluralOffset {
+		header.WriteString(" offset:")
+		header.WriteString(s.src[o.IndexStart:o.IndexEnd])
+	}
+	s.parts = append(s.parts, part{kind: partStart, text: header.String(), end: "}"})
+	for j := range icumsg.Options(s.buffer, index) {
+		s.parts = append(s.parts, part{
+			kind: partStart,
+			text: " " + icumsg.OptionName(s.src, s.buffer, j) + " {",
+			end:  "}",
+		})
+		start := j + 1
+		if t := s.buffer[j].Type; t == icumsg.TokenTypeOption ||
+			t == icumsg.TokenTypeOptionNumber {
+			start++ // Skip the option name.
+		}
+		s.split(start, s.buffer[j].IndexEnd, hash)
+		s.parts = append(s.parts, part{kind: partEnd, text: "}"})
+	}
+	s.parts = append(s.parts, part{kind: partEnd, text: "}"})
+}
+
+// literal appends the unescaped literal l.
+func (s *splitter) literal(l string, hash bool) {
+	for text, isHash := range icumsg.LiteralSegments(l, hash) {
+		s.text(text)
+		if isHash {
+			s.parts = append(s.parts, part{kind: partPlaceholder, text: "#"})
+		}
+	}
+}
+
+// text appends t merging it with any preceding text.
+func (s *splitter) text(t string) {
+	if t == "" {
+		return
+	}
+	if n := len(s.parts); n > 0 && s.parts[n-1].kind == partText {
+		s.parts[n-1].text += t
+		return
+	}
+	s.parts = append(s.parts, part{kind: partText, text: t})
+}
+
+// codes assigns IDs to the codes of a unit. The n-th occurrence of a code
+// gets the same ID in source and target so CAT tools can match them.
+type codes struct {
+	ids     map[string]int
+	data    []string // Native data in order of appearance.
+	dataIDs map[string]int
+}
+
+func newCodes() *codes {
+	return &codes{ids: map[string]int{}, dataIDs: map[string]int{}}
+}
+
+// dataRef returns the ID of the native data d for <originalData>.
+func (c *codes) dataRef(d string) string {
+	id, ok := c.dataIDs[d]
+	if !ok {
+		c.data = append(c.data, d)
+		id = len(c.data)
+		c.dataIDs[d] = id
+	}
+	return "d" + strconv.Itoa(id)
+}
+
+// render writes parts as inline content of XLIFF version v to b.
+func (c *codes) render(b *strings.Builder, parts []part, v Version) {
+	occurrences := map[string]int{}
+	id := func(p part) string {
+		key := p.text + "\x00" + p.end
+		occurrences[key]++
+		key += "\x00" + strconv.Itoa(occurrences[key])
+		n, ok := c.ids[key]
+		if !ok {
+			n = len(c.ids) + 1
+			c.ids[key] = n
+		}
+		return strconv.Itoa(n)
+	}
+	type open struct{ id, end string }
+	var stack []open
+	for _, p := range parts {
+		switch p.kind {
+		case partText:
+			textEscaper.WriteString(b, p.text)
+		case partPlaceholder:
+			if v == Version12 {
+				b.WriteString(`<ph id="` + id(p) + `">`)
+				textEscaper.WriteString(b, p.text)
+				b.WriteString(`</ph>`)
+				continue
+			}
+			b.WriteString(`<ph id="` + id(p) + `" dataRef="` + c.dataRef(p.text) + `"/>`)
+		case partStart:
+			o := open{id: id(p), end: p.end}
+			stack = append(stack, o)
+			if v == Version12 {
+				b.WriteString(`<bpt id="` + o.id + `">`)
+				textEscaper.WriteString(b, p.text)
+				b.WriteString(`</bpt>`)
+				continue
+			}
+			b.WriteString(`<pc id="` + o.id + `" dataRefStart="` + c.dataRef(p.text) +
+				`" dataRefEnd="` + c.dataRef(p.end) + `">`)
+		case partEnd:
+			o := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if v == Version12 {
+				b.WriteString(`<ept id="` + o.id + `">`)
+				textEscaper.WriteString(b, o.end)
+				b.WriteString(`</ept>`)
+				continue
+			}
+			b.WriteString(`</pc>`)
+		}
+	}
+}
+
+var (
+	textEscaper = strings.NewReplacer(
+		"&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;",
+	)
+	attrEscaper = strings.NewReplacer(
+		"&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;",
+		"\r", "&#xD;", "\n", "&#xA;", "\t", "&#x9;",
+	)
+)
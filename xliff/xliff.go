@@ -0,0 +1,408 @@
+// Package xliff exports ICU messages to XLIFF 1.2 and 2.0 files
+// for CAT tools and imports the translations back.
+//
+// Arguments are protected as inline codes so translators can only move
+// them around: simple arguments and "#" become placeholders (<ph>),
+// complex arguments and their options become paired codes
+// (<pc> in XLIFF 2.0 and <bpt>/<ept> in XLIFF 1.2) enclosing
+// the translatable contents of the options. The ICU syntax of a code
+// is kept as its native data and restored on import.
+package xliff
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"golang.org/x/text/language"
+)
+
+// Version is an XLIFF version.
+type Version string
+
+const (
+	Version12 Version = "1.2"
+	Version20 Version = "2.0"
+)
+
+// File is an XLIFF file.
+type File struct {
+	Version      Version
+	SourceLocale language.Tag
+
+	// TargetLocale is language.Und for files without target language.
+	TargetLocale language.Tag
+
+	Units []Unit
+}
+
+// Unit is a translation unit.
+type Unit struct {
+	ID string
+
+	// Source and Target are ICU messages. Target is empty if there's none.
+	Source, Target string
+
+	// Translated is false if Target is missing or still needs translation
+	// (state "initial" in XLIFF 2.0, "new" or "needs-*" in XLIFF 1.2).
+	Translated bool
+}
+
+var (
+	ErrSyntax             = errors.New("syntax error")
+	ErrUnsupportedVersion = errors.New("unsupported XLIFF version")
+	ErrInvalidInline      = errors.New("invalid inline content")
+	ErrNoTargetLocale     = errors.New("no target locale")
+)
+
+// Export creates a file with a unit for every valid message of the
+// source locale of c. Units of messages translated to target are
+// Translated. Untranslated units are seeded with the source message
+// adapted to the plural categories of target (see icumsg.AdaptPlurals)
+// since translators can't add options protected as inline codes.
+func Export(c *catalog.Catalog, target language.Tag, v Version) *File {
+	src := c.Source()
+	f := &File{Version: v, SourceLocale: src, TargetLocale: target}
+	for _, id := range c.IDs(src) {
+		e := c.Entry(src, id)
+		if e.Err != nil {
+			continue
+		}
+		u := Unit{ID: id, Source: e.Message}
+		if t := c.Entry(target, id); t != nil && t.Err == nil {
+			u.Target, u.Translated = t.Message, true
+		} else {
+			u.Target = icumsg.AdaptPlurals(e.Message, e.Tokens, src, target)
+		}
+		f.Units = append(f.Units, u)
+	}
+	return f
+}
+
+// Import adds the targets of all translated units of f to c.
+// Targets are tokenized for the target locale by c and invalid ones
+// are added too (see catalog.Catalog.Add) and reported in the returned error.
+func Import(c *catalog.Catalog, f *File) error {
+	if f.TargetLocale == language.Und {
+		return ErrNoTargetLocale
+	}
+	var errs []error
+	for _, u := range f.Units {
+		if !u.Translated {
+			continue
+		}
+		if err := c.Add(f.TargetLocale, u.ID, u.Target); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", u.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Write writes f as an XLIFF file of version f.Version.
+// Sources are tokenized for the source locale and targets
+// for the target locale, invalid messages are an error.
+func Write(w io.Writer, f *File) error {
+	if f.Version != Version12 && f.Version != Version20 {
+		return fmt.Errorf("%w: %q", ErrUnsupportedVersion, f.Version)
+	}
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	if f.Version == Version12 {
+		b.WriteString(`<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">` +
+			"\n  <file original=\"messages\" datatype=\"plaintext\" source-language=\"")
+		attrEscaper.WriteString(&b, f.SourceLocale.String())
+		if f.TargetLocale != language.Und {
+			b.WriteString(`" target-language="`)
+			attrEscaper.WriteString(&b, f.TargetLocale.String())
+		}
+		b.WriteString("\">\n    <body>\n")
+	} else {
+		b.WriteString(`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="`)
+		attrEscaper.WriteString(&b, f.SourceLocale.String())
+		if f.TargetLocale != language.Und {
+			b.WriteString(`" trgLang="`)
+			attrEscaper.WriteString(&b, f.TargetLocale.String())
+		}
+		b.WriteString("\">\n  <file id=\"f1\">\n")
+	}
+
+	var tokenizer icumsg.Tokenizer
+	var buffer []icumsg.Token
+	segment := func(c *codes, locale language.Tag, msg string) (string, error) {
+		var err error
+		buffer, err = tokenizer.Tokenize(locale, buffer[:0], msg)
+		if err != nil {
+			return "", err
+		}
+		var s strings.Builder
+		c.render(&s, split(msg, buffer), f.Version)
+		return s.String(), nil
+	}
+	for _, u := range f.Units {
+		c := newCodes()
+		source, err := segment(c, f.SourceLocale, u.Source)
+		if err != nil {
+			return fmt.Errorf("%s: source: %w", u.ID, err)
+		}
+		var target string
+		if u.Target != "" {
+			if target, err = segment(c, f.TargetLocale, u.Target); err != nil {
+				return fmt.Errorf("%s: target: %w", u.ID, err)
+			}
+		}
+		if f.Version == Version12 {
+			writeUnit12(&b, u, source, target)
+		} else {
+			writeUnit20(&b, u, c, source, target)
+		}
+	}
+
+	if f.Version == Version12 {
+		b.WriteString("    </body>\n")
+	}
+	b.WriteString("  </file>\n</xliff>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeUnit12(b *strings.Builder, u Unit, source, target string) {
+	b.WriteString(`      <trans-unit id="`)
+	attrEscaper.WriteString(b, u.ID)
+	b.WriteString("\">\n        <source>" + source + "</source>\n")
+	if u.Target != "" {
+		state := "translated"
+		if !u.Translated {
+			state = "needs-translation"
+		}
+		b.WriteString(`        <target state="` + state + `">` + target + "</target>\n")
+	}
+	b.WriteString("      </trans-unit>\n")
+}
+
+func writeUnit20(b *strings.Builder, u Unit, c *codes, source, target string) {
+	b.WriteString(`    <unit id="`)
+	attrEscaper.WriteString(b, u.ID)
+	b.WriteString("\">\n")
+	if len(c.data) > 0 {
+		b.WriteString("      <originalData>\n")
+		for i, d := range c.data {
+			fmt.Fprintf(b, `        <data id="d%d">`, i+1)
+			textEscaper.WriteString(b, d)
+			b.WriteString("</data>\n")
+		}
+		b.WriteString("      </originalData>\n")
+	}
+	switch {
+	case u.Target == "":
+		b.WriteString("      <segment>\n")
+	case u.Translated:
+		b.WriteString("      <segment state=\"translated\">\n")
+	default:
+		b.WriteString("      <segment state=\"initial\">\n")
+	}
+	b.WriteString("        <source>" + source + "</source>\n")
+	if u.Target != "" {
+		b.WriteString("        <target>" + target + "</target>\n")
+	}
+	b.WriteString("      </segment>\n    </unit>\n")
+}
+
+// Read reads an XLIFF 1.2 or 2.0 file restoring the ICU messages
+// from the inline content. Text is escaped (see icumsg.EscapeLiteral)
+// and codes are replaced by their native data. Units of multiple segments
+// are joined. Messages are not validated, see Import.
+func Read(r io.Reader) (*File, error) {
+	d := xml.NewDecoder(r)
+	f := new(File)
+	var u *Unit
+	var data map[string]string // Native data of XLIFF 2.0 codes.
+	var hasTarget, needsTranslation bool
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch name := t.Name.Local; {
+			case name == "xliff":
+				switch v := attr(t, "version"); {
+				case v == "1.2":
+					f.Version = Version12
+				case strings.HasPrefix(v, "2."):
+					f.Version = Version20
+				default:
+					return nil, fmt.Errorf("%w: %q", ErrUnsupportedVersion, v)
+				}
+				if err := f.setLocales(attr(t, "srcLang"), attr(t, "trgLang")); err != nil {
+					return nil, err
+				}
+			case f.Version == "":
+				return nil, fmt.Errorf("%w: missing xliff root element", ErrSyntax)
+			case name == "file" && f.Version == Version12:
+				err := f.setLocales(attr(t, "source-language"), attr(t, "target-language"))
+				if err != nil {
+					return nil, err
+				}
+			case name == "unit" || name == "trans-unit":
+				u = &Unit{ID: attr(t, "id")}
+				data = map[string]string{}
+				hasTarget, needsTranslation = false, false
+			case u == nil:
+				// Outside of units.
+			case name == "data":
+				if data[attr(t, "id")], err = readText(d); err != nil {
+					return nil, err
+				}
+			case name == "segment":
+				needsTranslation = needsTranslation || attr(t, "state") == "initial"
+			case name == "source" || name == "target":
+				s, err := readInline(d, f.Version, data)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", u.ID, err)
+				}
+				if name == "source" {
+					u.Source += s
+					continue
+				}
+				u.Target += s
+				hasTarget = true
+				state := attr(t, "state")
+				needsTranslation = needsTranslation || state == "new" ||
+					strings.HasPrefix(state, "needs-")
+			case name == "notes" || name == "note" ||
+				name == "seg-source" || name == "alt-trans":
+				if err := d.Skip(); err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrSyntax, err)
+				}
+			}
+		case xml.EndElement:
+			if u != nil && (t.Name.Local == "unit" || t.Name.Local == "trans-unit") {
+				u.Translated = hasTarget && !needsTranslation
+				f.Units = append(f.Units, *u)
+				u = nil
+			}
+		}
+	}
+	if f.Version == "" {
+		return nil, fmt.Errorf("%w: missing xliff root element", ErrSyntax)
+	}
+	return f, nil
+}
+
+func (f *File) setLocales(source, target string) (err error) {
+	if source != "" {
+		if f.SourceLocale, err = language.Parse(source); err != nil {
+			return fmt.Errorf("invalid source language %q: %w", source, err)
+		}
+	}
+	if target != "" {
+		if f.TargetLocale, err = language.Parse(target); err != nil {
+			return fmt.Errorf("invalid target language %q: %w", target, err)
+		}
+	}
+	return nil
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// readText reads the text content of the current element.
+func readText(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.StartElement:
+			return "", fmt.Errorf("%w: unexpected <%s> in code", ErrInvalidInline, t.Name.Local)
+		case xml.EndElement:
+			return b.String(), nil
+		}
+	}
+}
+
+// readInline reads the inline content of the current element
+// and returns it as ICU message.
+func readInline(d *xml.Decoder, v Version, data map[string]string) (string, error) {
+	var b strings.Builder
+	ref := func(e xml.StartElement, name string) error {
+		s, ok := data[attr(e, name)]
+		if !ok {
+			return fmt.Errorf("%w: <%s> %s %q not found",
+				ErrInvalidInline, e.Name.Local, name, attr(e, name))
+		}
+		b.WriteString(s)
+		return nil
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.WriteString(icumsg.EscapeLiteral(string(t)))
+		case xml.EndElement:
+			return b.String(), nil
+		case xml.StartElement:
+			switch name := t.Name.Local; {
+			case name == "mrk":
+				// Annotations are transparent.
+				s, err := readInline(d, v, data)
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(s)
+			case v == Version12 && (name == "ph" || name == "bpt" ||
+				name == "ept" || name == "it"):
+				s, err := readText(d)
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(s)
+			case v == Version20 && (name == "ph" || name == "sc" || name == "ec"):
+				if err := ref(t, "dataRef"); err != nil {
+					return "", err
+				}
+				if err := d.Skip(); err != nil {
+					return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+				}
+			case v == Version20 && name == "pc":
+				if err := ref(t, "dataRefStart"); err != nil {
+					return "", err
+				}
+				s, err := readInline(d, v, data)
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(s)
+				if err := ref(t, "dataRefEnd"); err != nil {
+					return "", err
+				}
+			case v == Version20 && (name == "sm" || name == "em"):
+				if err := d.Skip(); err != nil {
+					return "", fmt.Errorf("%w: %v", ErrSyntax, err)
+				}
+			default:
+				return "", fmt.Errorf("%w: unsupported element <%s>", ErrInvalidInline, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package xliff_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/catalog"
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/xliff"
+	"golang.org/x/text/language"
+)
+
+var (
+	en = language.English
+	de = language.German
+	pl = language.Polish
+)
+
+func newCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, c.Add(en, "greeting", "Hello {name}, it''s '{'{day, date, short}'}'!"))
+	test.RequireNoErr(t, c.Add(en, "files",
+		"{n, plural, one {# file} other {# files in {dir}}}"))
+	_ = c.Add(en, "broken", "{")
+	test.RequireNoErr(t, c.Add(de, "greeting", "Hallo {name} <&>"))
+	return c
+}
+
+func TestExport20(t *testing.T) {
+	f := xliff.Export(newCatalog(t), de, xliff.Version20)
+	var b bytes.Buffer
+	test.RequireNoErr(t, xliff.Write(&b, f))
+	test.RequireEqual(t, `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="de">
+  <file id="f1">
+    <unit id="files">
+      <originalData>
+        <data id="d1">{n, plural,</data>
+        <data id="d2">}</data>
+        <data id="d3"> one {</data>
+        <data id="d4">#</data>
+        <data id="d5"> other {</data>
+        <data id="d6">{dir}</data>
+      </originalData>
+      <segment state="initial">
+        <source><pc id="1" dataRefStart="d1" dataRefEnd="d2"><pc id="2" dataRefStart="d3" dataRefEnd="d2"><ph id="3" dataRef="d4"/> file</pc><pc id="4" dataRefStart="d5" dataRefEnd="d2"><ph id="5" dataRef="d4"/> files in <ph id="6" dataRef="d6"/></pc></pc></source>
+        <target><pc id="1" dataRefStart="d1" dataRefEnd="d2"><pc id="2" dataRefStart="d3" dataRefEnd="d2"><ph id="3" dataRef="d4"/> file</pc><pc id="4" dataRefStart="d5" dataRefEnd="d2"><ph id="5" dataRef="d4"/> files in <ph id="6" dataRef="d6"/></pc></pc></target>
+      </segment>
+    </unit>
+    <unit id="greeting">
+      <originalData>
+        <data id="d1">{name}</data>
+        <data id="d2">{day, date, short}</data>
+      </originalData>
+      <segment state="translated">
+        <source>Hello <ph id="1" dataRef="d1"/>, it's {<ph id="2" dataRef="d2"/>}!</source>
+        <target>Hallo <ph id="1" dataRef="d1"/> &lt;&amp;&gt;</target>
+      </segment>
+    </unit>
+  </file>
+</xliff>
+`, b.String())
+}
+
+func TestExport12(t *testing.T) {
+	f := xliff.Export(newCatalog(t), pl, xliff.Version12)
+	var b bytes.Buffer
+	test.RequireNoErr(t, xliff.Write(&b, f))
+	test.RequireEqual(t, `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">
+  <file original="messages" datatype="plaintext" source-language="en" target-language="pl">
+    <body>
+      <trans-unit id="files">
+        <source><bpt id="1">{n, plural,</bpt><bpt id="2"> one {</bpt><ph id="3">#</ph> file<ept id="2">}</ept><bpt id="4"> other {</bpt><ph id="5">#</ph> files in <ph id="6">{dir}</ph><ept id="4">}</ept><ept id="1">}</ept></source>
+        <target state="needs-translation"><bpt id="1">{n, plural,</bpt><bpt id="2"> one {</bpt><ph id="3">#</ph> file<ept id="2">}</ept><bpt id="4"> other {</bpt><ph id="5">#</ph> files in <ph id="6">{dir}</ph><ept id="4">}</ept><bpt id="7"> few {</bpt><ph id="8">#</ph> files in <ph id="9">{dir}</ph><ept id="7">}</ept><bpt id="10"> many {</bpt><ph id="11">#</ph> files in <ph id="12">{dir}</ph><ept id="10">}</ept><ept id="1">}</ept></target>
+      </trans-unit>
+      <trans-unit id="greeting">
+        <source>Hello <ph id="1">{name}</ph>, it's {<ph id="2">{day, date, short}</ph>}!</source>
+        <target state="needs-translation">Hello <ph id="1">{name}</ph>, it's {<ph id="2">{day, date, short}</ph>}!</target>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`, b.String())
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, v := range []xliff.Version{xliff.Version12, xliff.Version20} {
+		t.Run(string(v), func(t *testing.T) {
+			f := xliff.Export(newCatalog(t), pl, v)
+			var b bytes.Buffer
+			test.RequireNoErr(t, xliff.Write(&b, f))
+			r, err := xliff.Read(&b)
+			test.RequireNoErr(t, err)
+			test.RequireEqual(t, v, r.Version)
+			test.RequireEqual(t, en, r.SourceLocale)
+			test.RequireEqual(t, pl, r.TargetLocale)
+			test.RequireDeepEqual(t, []xliff.Unit{
+				{
+					ID:     "files",
+					Source: "{n, plural, one {# file} other {# files in {dir}}}",
+					Target: "{n, plural, one {# file} other {# files in {dir}}" +
+						" few {# files in {dir}} many {# files in {dir}}}",
+				},
+				{
+					ID:     "greeting",
+					Source: "Hello {name}, it''s '{'{day, date, short}'}'!",
+					Target: "Hello {name}, it''s '{'{day, date, short}'}'!",
+				},
+			}, r.Units)
+		})
+	}
+}
+
+func TestImport20(t *testing.T) {
+	// Translated by a CAT tool: text requiring ICU escaping, segments and markers.
+	f, err := xliff.Read(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.1" srcLang="en" trgLang="pl">
+  <file id="f1">
+    <unit id="files">
+      <notes><note>Ignored</note></notes>
+      <originalData>
+        <data id="d1">{n, plural,</data>
+        <data id="d2">}</data>
+        <data id="d3"> one {</data>
+        <data id="d4">#</data>
+        <data id="d5"> other {</data>
+        <data id="d6"> few {</data>
+        <data id="d7"> many {</data>
+      </originalData>
+      <segment state="final">
+        <source>ignored</source>
+        <target><pc id="1" dataRefStart="d1" dataRefEnd="d2"><pc id="2" dataRefStart="d3" dataRefEnd="d2"><ph id="3" dataRef="d4"/> plik</pc><pc id="4" dataRefStart="d6" dataRefEnd="d2"><ph id="5" dataRef="d4"/> pliki</pc><pc id="6" dataRefStart="d7" dataRefEnd="d2"><ph id="7" dataRef="d4"/> plików</pc></pc></target>
+      </segment>
+      <segment state="final">
+        <source/>
+        <target> <mrk id="m1">!</mrk></target>
+      </segment>
+      <ignorable><source/></ignorable>
+    </unit>
+    <unit id="greeting">
+      <originalData><data id="d1">{name}</data></originalData>
+      <segment>
+        <source>Hello <ph id="1" dataRef="d1"/></source>
+        <target>Cześć <ph id="1" dataRef="d1"/> '#' {}</target>
+      </segment>
+    </unit>
+    <unit id="untranslated">
+      <segment state="initial">
+        <source>Untranslated</source>
+        <target>Untranslated</target>
+      </segment>
+    </unit>
+  </file>
+</xliff>`))
+	test.RequireNoErr(t, err)
+	test.RequireEqual(t, xliff.Version20, f.Version)
+	test.RequireEqual(t, 3, len(f.Units))
+	test.RequireEqual(t, false, f.Units[2].Translated)
+
+	c := catalog.New(en, nil)
+	err = xliff.Import(c, f)
+	// Polish requires the "other" option.
+	test.RequireErrIs(t, icumsg.ErrMissingOptionOther, err)
+	test.RequireEqual(t, "files: missing the mandatory 'other' option", err.Error())
+
+	e := c.Entry(pl, "greeting")
+	test.RequireEqual(t, "Cześć {name} '''#''' '{''}'", e.Message)
+	test.RequireNoErr(t, e.Err)
+	test.RequireEqual(t, (*catalog.Entry)(nil), c.Entry(pl, "untranslated"))
+	test.RequireEqual(t,
+		"{n, plural, one {# plik} few {# pliki} many {# plików}} !",
+		c.Entry(pl, "files").Message)
+
+	test.RequireErrIs(t, xliff.ErrNoTargetLocale, xliff.Import(c, &xliff.File{}))
+}
+
+func TestImport12(t *testing.T) {
+	f, err := xliff.Read(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">
+  <file original="messages" datatype="plaintext" source-language="en" target-language="de">
+    <body>
+      <trans-unit id="files">
+        <source>ignored</source>
+        <target state="final"><bpt id="1">{n, plural,</bpt><bpt id="2"> one {</bpt><ph id="3">#</ph> Datei<ept id="2">}</ept><bpt id="4"> other {</bpt><ph id="5">#</ph> Dateien<ept id="4">}</ept><ept id="1">}</ept></target>
+        <alt-trans><target>Ignored</target></alt-trans>
+        <note>Ignored</note>
+      </trans-unit>
+      <trans-unit id="new">
+        <source>New</source>
+        <target state="new">Neu</target>
+      </trans-unit>
+      <trans-unit id="none">
+        <source>None</source>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>`))
+	test.RequireNoErr(t, err)
+	test.RequireDeepEqual(t, []xliff.Unit{
+		{
+			ID:         "files",
+			Source:     "ignored",
+			Target:     "{n, plural, one {# Datei} other {# Dateien}}",
+			Translated: true,
+		},
+		{ID: "new", Source: "New", Target: "Neu"},
+		{ID: "none", Source: "None"},
+	}, f.Units)
+
+	c := catalog.New(en, nil)
+	test.RequireNoErr(t, xliff.Import(c, f))
+	test.RequireDeepEqual(t, []string{"files"}, c.IDs(de))
+}
+
+func TestReadErr(t *testing.T) {
+	f := func(t *testing.T, input string, expect error) {
+		t.Helper()
+		_, err := xliff.Read(strings.NewReader(input))
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, ``, xliff.ErrSyntax)
+	f(t, `<xliff version="2.0"><file>`, xliff.ErrSyntax)
+	f(t, `<unit/>`, xliff.ErrSyntax)
+	f(t, `<xliff version="3.0"/>`, xliff.ErrUnsupportedVersion)
+	f(t, `<xliff version="2.0"><file><unit id="a"><segment>`+
+		`<source><ph id="1" dataRef="d1"/></source>`+
+		`</segment></unit></file></xliff>`, xliff.ErrInvalidInline)
+	f(t, `<xliff version="2.0"><file><unit id="a"><segment>`+
+		`<source><bpt id="1">{</bpt></source>`+
+		`</segment></unit></file></xliff>`, xliff.ErrInvalidInline)
+	f(t, `<xliff version="1.2"><file><body><trans-unit id="a">`+
+		`<source><ph id="1"><sub>x</sub></ph></source>`+
+		`</trans-unit></body></file></xliff>`, xliff.ErrInvalidInline)
+}
+
+func TestWriteErr(t *testing.T) {
+	f := func(t *testing.T, file *xliff.File, expect error) {
+		t.Helper()
+		err := xliff.Write(&bytes.Buffer{}, file)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, &xliff.File{Version: "1.0"}, xliff.ErrUnsupportedVersion)
+	f(t, &xliff.File{
+		Version: xliff.Version20, SourceLocale: en,
+		Units: []xliff.Unit{{ID: "a", Source: "{"}},
+	}, icumsg.ErrUnexpectedEOF)
+	f(t, &xliff.File{
+		Version: xliff.Version12, SourceLocale: en, TargetLocale: language.Japanese,
+		Units: []xliff.Unit{{ID: "a", Source: "x", Target: "{n, plural, one {#} other {#}}"}},
+	}, icumsg.ErrUnsupportedPluralRule)
+}
@@ -0,0 +1,140 @@
+// Package xtext compiles ICU messages to messages of
+// golang.org/x/text/message/catalog for rendering with message.Printer.
+//
+// Arguments become format specifiers with explicit argument indexes:
+// "%[1]v" for simple arguments and "%[1]d" for number arguments
+// and "#". Plural arguments become variables (catalog.Var) selecting
+// a case with plural.Selectf that are substituted with "${name}".
+package xtext
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/romshark/icumsg"
+	"github.com/romshark/icumsg/internal/printf"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+var (
+	// ErrUnrepresentable is returned for ICU constructs that have no
+	// equivalent in x/text catalogs, such as select and selectordinal
+	// arguments, plural offsets, date and time arguments and literal "${".
+	ErrUnrepresentable = printf.ErrUnrepresentable
+
+	// ErrUnknownArgument is returned for arguments of a message that
+	// aren't among the arguments passed to the printer.
+	ErrUnknownArgument = errors.New("unknown argument")
+)
+
+var verbs = printf.Verbs{String: "v", Number: "d"}
+
+// Messages compiles the ICU message src tokenized into buffer to a
+// message sequence for catalog.Builder.Set. args are the argument names
+// in the order they're passed to the printer, for example
+// []string{"name", "count"} for p.Sprintf(key, name, count).
+// Every plural argument becomes a variable named after the argument,
+// with a suffix "_2", "_3", ... if the same argument is used again.
+func Messages(src string, buffer []icumsg.Token, args []string) ([]catalog.Message, error) {
+	for _, t := range buffer {
+		if t.Type == icumsg.TokenTypeLiteral &&
+			strings.Contains(strings.ReplaceAll(src[t.IndexStart:t.IndexEnd], "'", ""), "${") {
+			return nil, fmt.Errorf("%w: literal %q", ErrUnrepresentable, "${")
+		}
+	}
+	c := compiler{
+		src:    src,
+		buffer: buffer,
+		e:      printf.Encoder{Verbs: verbs, Args: slices.Clone(args), Indexed: true},
+	}
+	var b strings.Builder
+	if err := c.encode(&b, 0, len(buffer), 0); err != nil {
+		return nil, err
+	}
+	if len(c.e.Args) > len(args) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownArgument, c.e.Args[len(args)])
+	}
+	return append(c.vars, catalog.String(b.String())), nil
+}
+
+// Set tokenizes the ICU message src of locale tag, compiles it and sets
+// it as the translation of key in b. See Messages for args.
+func Set(b *catalog.Builder, tag language.Tag, key, src string, args ...string) error {
+	var tokenizer icumsg.Tokenizer
+	buffer, err := tokenizer.Tokenize(tag, nil, src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	m, err := Messages(src, buffer, args)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	if err := b.Set(tag, key, m...); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+type compiler struct {
+	src    string
+	buffer []icumsg.Token
+	e      printf.Encoder
+
+	// vars are the variables of plural arguments with nested
+	// arguments preceding the arguments they're nested in.
+	vars  []catalog.Message
+	names []string
+}
+
+func (c *compiler) encode(b *strings.Builder, start, end, hash int) error {
+	return c.e.Encode(b, c.src, c.buffer, start, end, hash, func(index int) error {
+		name, err := c.plural(index)
+		if err != nil {
+			return err
+		}
+		b.WriteString("${" + name + "}")
+		return nil
+	})
+}
+
+// plural adds the variable of the plural argument at buffer[index]
+// and returns its name.
+func (c *compiler) plural(index int) (string, error) {
+	arg := c.buffer[index+1].String(c.src, c.buffer)
+	if c.buffer[index+2].Type == icumsg.TokenTypePluralOffset {
+		return "", fmt.Errorf("%w: offset of %q", ErrUnrepresentable, arg)
+	}
+	position := c.e.Position(arg)
+
+	// Options "=n" take precedence over categories in ICU
+	// whereas plural.Selectf selects the first matching case.
+	var exact, categories, other []any
+	for j := range icumsg.Options(c.buffer, index) {
+		var b strings.Builder
+		if err := c.encode(&b, j+1, c.buffer[j].IndexEnd, position); err != nil {
+			return "", err
+		}
+		option := icumsg.OptionName(c.src, c.buffer, j)
+		switch {
+		case strings.HasPrefix(option, "="):
+			exact = append(exact, option, b.String())
+		case option == "other":
+			other = append(other, option, b.String())
+		default:
+			categories = append(categories, option, b.String())
+		}
+	}
+
+	name := arg
+	for n := 2; slices.Contains(c.names, name); n++ {
+		name = fmt.Sprintf("%s_%d", arg, n)
+	}
+	c.names = append(c.names, name)
+	cases := slices.Concat(exact, categories, other)
+	c.vars = append(c.vars, catalog.Var(name, plural.Selectf(position, "", cases...)))
+	return name, nil
+}
@@ -0,0 +1,62 @@
+package xtext_test
+
+import (
+	"testing"
+
+	"github.com/romshark/icumsg/internal/test"
+	"github.com/romshark/icumsg/xtext"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+func TestSet(t *testing.T) {
+	f := func(t *testing.T, tag language.Tag, src string, args []string, values []any, expect string) {
+		t.Helper()
+		b := catalog.NewBuilder()
+		test.RequireNoErr(t, xtext.Set(b, tag, "key", src, args...))
+		p := message.NewPrinter(tag, message.Catalog(b))
+		test.RequireEqual(t, expect, p.Sprintf("key", values...))
+	}
+
+	f(t, language.English, "Plain", nil, nil, "Plain")
+	f(t, language.English, "It''s '{'100%'}' $5", nil, nil, "It's {100%} $5")
+	f(t, language.English, "Hello {name}, {n, number} {name}!",
+		[]string{"name", "n"}, []any{"Ann", 1200}, "Hello Ann, 1,200 Ann!")
+	// Arguments in a different order than passed.
+	f(t, language.English, "{b} {a}", []string{"a", "b"}, []any{"A", "B"}, "B A")
+
+	files := "You have {n, plural, =0 {no files} one {# file} other {# files}} in {dir}."
+	args := []string{"n", "dir"}
+	f(t, language.English, files, args, []any{0, "docs"}, "You have no files in docs.")
+	f(t, language.English, files, args, []any{1, "docs"}, "You have 1 file in docs.")
+	f(t, language.English, files, args, []any{1500, "docs"}, "You have 1,500 files in docs.")
+
+	pl := "{n, plural, one {# plik} few {# pliki} other {# plików}}"
+	f(t, language.Polish, pl, []string{"n"}, []any{1}, "1 plik")
+	f(t, language.Polish, pl, []string{"n"}, []any{3}, "3 pliki")
+	f(t, language.Polish, pl, []string{"n"}, []any{5}, "5 plików")
+
+	// Nested and repeated plural arguments.
+	nested := "{n, plural, one {# file} other {# files in " +
+		"{d, plural, one {# folder} other {# folders}}}}, {n, plural, one {it} other {them}}"
+	args = []string{"n", "d"}
+	f(t, language.English, nested, args, []any{1, 2}, "1 file, it")
+	f(t, language.English, nested, args, []any{3, 1}, "3 files in 1 folder, them")
+	f(t, language.English, nested, args, []any{3, 2}, "3 files in 2 folders, them")
+}
+
+func TestSetErr(t *testing.T) {
+	f := func(t *testing.T, expect error, src string, args ...string) {
+		t.Helper()
+		err := xtext.Set(catalog.NewBuilder(), language.English, "key", src, args...)
+		test.RequireErrIs(t, expect, err)
+	}
+
+	f(t, xtext.ErrUnrepresentable, "{g, select, other {x}}", "g")
+	f(t, xtext.ErrUnrepresentable, "{n, selectordinal, other {#}}", "n")
+	f(t, xtext.ErrUnrepresentable, "{n, plural, offset:1 other {#}}", "n")
+	f(t, xtext.ErrUnrepresentable, "{d, date}", "d")
+	f(t, xtext.ErrUnrepresentable, "$'{x}'")
+	f(t, xtext.ErrUnknownArgument, "{a} {b}", "a")
+}